@@ -0,0 +1,270 @@
+package accesslog
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	lgr "github.com/SimplyVC/oasis_api_server/src/logger"
+)
+
+// Formats of the access log lines
+const (
+	FormatCommon   = "common"
+	FormatCombined = "combined"
+	FormatJSON     = "json"
+)
+
+// Default settings used when the access_log section leaves them empty
+const (
+	defaultMaxSize    = 100
+	defaultMaxBackups = 5
+)
+
+// defaultRedacted are the query parameters whose values are never written
+// to the access log
+var defaultRedacted = []string{"token", "access_token", "password",
+	"secret", "api_key", "apikey", "auth"}
+
+var (
+	mutex    sync.RWMutex
+	settings *Settings
+	output   io.Writer
+	closer   io.Closer
+)
+
+// Settings configure the access log, they are read from the access_log
+// section of the main configuration
+type Settings struct {
+	Enabled    bool
+	Format     string
+	File       string
+	MaxSize    int64
+	MaxBackups int
+
+	// SampleRate is the share of requests that are logged, SampleRates
+	// overrides it for the paths of high volume endpoints. Requests
+	// answered with an HTTP error status are always logged, but most
+	// handlers report errors in the body of a 200 response, which are
+	// sampled like any other.
+	SampleRate  float64
+	SampleRates map[string]float64
+
+	// Redacted holds the lowercased names of query parameters whose
+	// values are replaced
+	Redacted map[string]bool
+}
+
+// LoadSettings parses the access_log section of the main configuration
+func LoadSettings(mainConf map[string]map[string]string) (*Settings, error) {
+	section := mainConf["access_log"]
+	settings := &Settings{
+		Enabled:     true,
+		Format:      FormatCombined,
+		File:        section["file"],
+		MaxSize:     defaultMaxSize << 20,
+		MaxBackups:  defaultMaxBackups,
+		SampleRate:  1,
+		SampleRates: map[string]float64{},
+		Redacted:    map[string]bool{},
+	}
+
+	if enabled := section["enabled"]; enabled != "" {
+		value, err := strconv.ParseBool(enabled)
+		if err != nil {
+			return nil, fmt.Errorf("access_log enabled needs to be true or "+
+				"false, got %s", enabled)
+		}
+		settings.Enabled = value
+	}
+	if format := strings.ToLower(section["format"]); format != "" {
+		if format != FormatCommon && format != FormatCombined &&
+			format != FormatJSON {
+			return nil, fmt.Errorf("access_log format needs to be %s, %s "+
+				"or %s, got %s", FormatCommon, FormatCombined, FormatJSON,
+				format)
+		}
+		settings.Format = format
+	}
+	if size := section["max_size_mb"]; size != "" {
+		megabytes, err := strconv.ParseUint(size, 10, 32)
+		if err != nil || megabytes == 0 {
+			return nil, fmt.Errorf("access_log max_size_mb needs to be a "+
+				"positive number of megabytes, got %s", size)
+		}
+		settings.MaxSize = int64(megabytes) << 20
+	}
+	if backups := section["max_backups"]; backups != "" {
+		value, err := strconv.ParseUint(backups, 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("access_log max_backups needs to be a "+
+				"number, got %s", backups)
+		}
+		settings.MaxBackups = int(value)
+	}
+	if rate := section["sample_rate"]; rate != "" {
+		value, err := parseRate(rate)
+		if err != nil {
+			return nil, fmt.Errorf("access_log sample_rate %v", err)
+		}
+		settings.SampleRate = value
+	}
+
+	// Sample rates are written as a comma separated list of path:rate, as
+	// the INI files can't hold an equals sign in a value
+	for _, item := range strings.Split(section["sample_rates"], ",") {
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+		path, rate, ok := strings.Cut(item, ":")
+		path = strings.TrimSpace(path)
+		if !ok || !strings.HasPrefix(path, "/") {
+			return nil, fmt.Errorf("access_log sample_rates needs to be a "+
+				"list of /path:rate, got %s", item)
+		}
+		value, err := parseRate(strings.TrimSpace(rate))
+		if err != nil {
+			return nil, fmt.Errorf("access_log sample_rates of %s %v", path,
+				err)
+		}
+		settings.SampleRates[path] = value
+	}
+
+	redacted := strings.Split(section["redact_params"], ",")
+	for _, name := range append(redacted, defaultRedacted...) {
+		if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+			settings.Redacted[name] = true
+		}
+	}
+	return settings, nil
+}
+
+// parseRate parses a sample rate between 0 and 1
+func parseRate(rate string) (float64, error) {
+	value, err := strconv.ParseFloat(rate, 64)
+	if err != nil || value < 0 || value > 1 {
+		return 0, fmt.Errorf("needs to be a number between 0 and 1, got %s",
+			rate)
+	}
+	return value, nil
+}
+
+// Configure sets how requests are logged, writing to standard output
+// unless a file is set, which is then rotated by size
+func Configure(newSettings *Settings) error {
+	var out io.Writer = os.Stdout
+	var file io.WriteCloser
+	if newSettings.Enabled && newSettings.File != "" {
+		var err error
+		file, err = lgr.OpenRotatingFile(newSettings.File,
+			newSettings.MaxSize, newSettings.MaxBackups)
+		if err != nil {
+			return err
+		}
+		out = file
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if closer != nil {
+		closer.Close()
+	}
+	settings, output, closer = newSettings, out, file
+	return nil
+}
+
+// Close stops logging requests and closes the access log file, if any
+func Close() {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if closer != nil {
+		closer.Close()
+	}
+	settings, output, closer = nil, nil, nil
+}
+
+// Handler logs every request served by next once it has been answered,
+// according to the settings given to Configure
+func Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.RLock()
+		enabled := settings != nil && settings.Enabled
+		mutex.RUnlock()
+		if !enabled {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		start := time.Now()
+		recorder := &responseRecorder{ResponseWriter: w,
+			status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+		write(r, recorder, start)
+	})
+}
+
+// write logs a request if it is sampled
+func write(r *http.Request, recorder *responseRecorder, start time.Time) {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	if settings == nil || !settings.Enabled {
+		return
+	}
+
+	rate, ok := settings.SampleRates[r.URL.Path]
+	if !ok {
+		rate = settings.SampleRate
+	}
+	if recorder.status < http.StatusBadRequest && rate < 1 &&
+		rand.Float64() >= rate {
+		return
+	}
+
+	entry := newEntry(r, recorder.status, recorder.size, time.Since(start),
+		start, settings.Redacted)
+	var line []byte
+	if settings.Format == FormatJSON {
+		line = entry.json()
+	} else {
+		line = entry.text(settings.Format == FormatCombined)
+	}
+	if _, err := output.Write(line); err != nil {
+		lgr.Error.Println("Writing of access log has failed : ", err)
+	}
+}
+
+// responseRecorder keeps the status and size of a response
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	size        int64
+	wroteHeader bool
+}
+
+// WriteHeader records the status of the response
+func (r *responseRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status, r.wroteHeader = status, true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Write records the size of the response
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	n, err := r.ResponseWriter.Write(b)
+	r.size += int64(n)
+	return n, err
+}
+
+// Unwrap returns the wrapped writer so that http.ResponseController can
+// reach it
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
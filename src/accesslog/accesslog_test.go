@@ -0,0 +1,166 @@
+package accesslog_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/SimplyVC/oasis_api_server/src/accesslog"
+	lgr "github.com/SimplyVC/oasis_api_server/src/logger"
+)
+
+// serve configures the access log to write to a file, serves the given
+// requests and returns the lines written
+func serve(t *testing.T, section map[string]string,
+	requests ...*http.Request) []string {
+
+	path := filepath.Join(t.TempDir(), "access.log")
+	section["file"] = path
+	settings, err := accesslog.LoadSettings(
+		map[string]map[string]string{"access_log": section})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := accesslog.Configure(settings); err != nil {
+		t.Fatal(err)
+	}
+	defer accesslog.Close()
+
+	handler := accesslog.Handler(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/api/missing" {
+				http.NotFound(w, r)
+				return
+			}
+			w.Write([]byte(`{"result":"pong"}`))
+		}))
+	for _, request := range requests {
+		handler.ServeHTTP(httptest.NewRecorder(), request)
+	}
+
+	// No file is opened while the access log is disabled
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) || len(data) == 0 {
+		return nil
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+}
+
+func TestLoadSettings_Success(t *testing.T) {
+	settings, err := accesslog.LoadSettings(map[string]map[string]string{
+		"access_log": {"format": "JSON", "sample_rate": "0.5",
+			"sample_rates":  "/api/ping:0, /api/consensus/block:0.1",
+			"redact_params": "Signature"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !settings.Enabled || settings.Format != accesslog.FormatJSON ||
+		settings.SampleRate != 0.5 || settings.SampleRates["/api/ping"] != 0 ||
+		settings.SampleRates["/api/consensus/block"] != 0.1 ||
+		!settings.Redacted["signature"] || !settings.Redacted["token"] {
+		t.Errorf("Unexpected settings %+v", settings)
+	}
+}
+
+func TestLoadSettings_Failure(t *testing.T) {
+	for _, section := range []map[string]string{
+		{"enabled": "sometimes"},
+		{"format": "apache"},
+		{"sample_rate": "2"},
+		{"sample_rates": "/api/ping"},
+		{"sample_rates": "api/ping:0.5"},
+		{"sample_rates": "/api/ping:half"},
+		{"max_size_mb": "0"},
+	} {
+		if _, err := accesslog.LoadSettings(map[string]map[string]string{
+			"access_log": section}); err == nil {
+			t.Errorf("Failed to reject access_log section %v", section)
+		}
+	}
+}
+
+func TestHandler_Combined(t *testing.T) {
+	request := httptest.NewRequest(http.MethodGet,
+		"/api/pingnode?name=Oasis_Local&token=hunter2&Api%5FKey=abc", nil)
+	request.RemoteAddr = "10.0.0.7:51234"
+	request.Header.Set("User-Agent", "monitor/1.0")
+	request = request.WithContext(lgr.WithRequestID(context.Background(),
+		"4f1c2a9e"))
+
+	lines := serve(t, map[string]string{}, request)
+	if len(lines) != 1 {
+		t.Fatalf("Unexpected access log lines %q", lines)
+	}
+	expected := regexp.MustCompile(`^10\.0\.0\.7 - - \[[^\]]+\] ` +
+		`"GET /api/pingnode\?name=Oasis_Local&token=REDACTED&` +
+		`Api%5FKey=REDACTED HTTP/1\.1" 200 17 - "monitor/1\.0" ` +
+		`node=Oasis_Local request_id=4f1c2a9e latency_ms=[0-9.]+$`)
+	if !expected.MatchString(lines[0]) {
+		t.Errorf("Unexpected access log line %q", lines[0])
+	}
+}
+
+func TestHandler_NodeEscaped(t *testing.T) {
+	request := httptest.NewRequest(http.MethodGet, "/api/pingnode?name="+
+		url.QueryEscape("Oasis\n10.0.0.8 - - forged node=x"), nil)
+
+	lines := serve(t, map[string]string{"format": "common"}, request)
+	if len(lines) != 1 || !strings.Contains(lines[0],
+		`node="Oasis\n10.0.0.8 - - forged node=x" request_id=-`) {
+		t.Errorf("Failed to escape node of access log line %q", lines)
+	}
+}
+
+func TestHandler_JSON(t *testing.T) {
+	lines := serve(t, map[string]string{"format": "json"},
+		httptest.NewRequest(http.MethodGet, "/api/missing?password=x", nil))
+	if len(lines) != 1 {
+		t.Fatalf("Unexpected access log lines %q", lines)
+	}
+
+	entry := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry["method"] != "GET" || entry["path"] != "/api/missing" ||
+		entry["query"] != "password=REDACTED" ||
+		entry["status"] != float64(http.StatusNotFound) ||
+		entry["size"] == float64(0) || entry["latency_ms"] == nil ||
+		entry["remote_addr"] != "192.0.2.1" {
+		t.Errorf("Unexpected access log entry %v", entry)
+	}
+}
+
+func TestHandler_Sampling(t *testing.T) {
+	var requests []*http.Request
+	for i := 0; i < 10; i++ {
+		requests = append(requests,
+			httptest.NewRequest(http.MethodGet, "/api/ping", nil))
+	}
+	requests = append(requests,
+		httptest.NewRequest(http.MethodGet, "/api/missing", nil),
+		httptest.NewRequest(http.MethodGet, "/api/consensus/block", nil))
+
+	// Failed requests are logged even from endpoints that aren't sampled
+	lines := serve(t, map[string]string{"format": "common",
+		"sample_rates": "/api/ping:0,/api/missing:0"}, requests...)
+	if len(lines) != 2 || !strings.Contains(lines[0], "/api/missing") ||
+		!strings.Contains(lines[1], "/api/consensus/block") {
+		t.Errorf("Unexpected access log lines %q", lines)
+	}
+
+	if lines := serve(t, map[string]string{"enabled": "false"},
+		requests...); len(lines) != 0 {
+		t.Errorf("Unexpected access log lines while disabled %q", lines)
+	}
+}
@@ -0,0 +1,147 @@
+package accesslog
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	lgr "github.com/SimplyVC/oasis_api_server/src/logger"
+)
+
+// redactedValue replaces the values of redacted query parameters
+const redactedValue = "REDACTED"
+
+// commonTime is the time layout of the Common Log Format
+const commonTime = "02/Jan/2006:15:04:05 -0700"
+
+// entry is a line of the access log
+type entry struct {
+	Time       time.Time `json:"time"`
+	RemoteAddr string    `json:"remote_addr"`
+	Method     string    `json:"method"`
+	Path       string    `json:"path"`
+	Query      string    `json:"query,omitempty"`
+	Proto      string    `json:"proto"`
+	Node       string    `json:"node,omitempty"`
+	Status     int       `json:"status"`
+	Size       int64     `json:"size"`
+	LatencyMs  float64   `json:"latency_ms"`
+	Referer    string    `json:"referer,omitempty"`
+	UserAgent  string    `json:"user_agent,omitempty"`
+	RequestID  string    `json:"request_id,omitempty"`
+}
+
+// newEntry describes an answered request
+func newEntry(r *http.Request, status int, size int64,
+	latency time.Duration, start time.Time,
+	redacted map[string]bool) *entry {
+
+	remote := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(remote); err == nil {
+		remote = host
+	}
+	return &entry{
+		Time:       start,
+		RemoteAddr: remote,
+		Method:     r.Method,
+		Path:       r.URL.Path,
+		Query:      redactQuery(r.URL.RawQuery, redacted),
+		Proto:      r.Proto,
+		Node:       r.URL.Query().Get("name"),
+		Status:     status,
+		Size:       size,
+		LatencyMs:  float64(latency.Microseconds()) / 1000,
+		Referer:    r.Referer(),
+		UserAgent:  r.UserAgent(),
+		RequestID:  lgr.RequestID(r.Context()),
+	}
+}
+
+// redactQuery replaces the values of redacted parameters of a raw query,
+// keeping the order and encoding of the other parameters
+func redactQuery(rawQuery string, redacted map[string]bool) string {
+	if rawQuery == "" {
+		return ""
+	}
+	params := strings.Split(rawQuery, "&")
+	for i, param := range params {
+		key, _, _ := strings.Cut(param, "=")
+		name, err := url.QueryUnescape(key)
+		if err != nil {
+			name = key
+		}
+		if redacted[strings.ToLower(name)] {
+			params[i] = key + "=" + redactedValue
+		}
+	}
+	return strings.Join(params, "&")
+}
+
+// text returns the entry in the Common or Combined Log Format, followed by
+// the node, request ID and latency
+func (e *entry) text(combined bool) []byte {
+	target := e.Path
+	if e.Query != "" {
+		target += "?" + e.Query
+	}
+	size := "-"
+	if e.Size > 0 {
+		size = strconv.FormatInt(e.Size, 10)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s - - [%s] %s %d %s", orDash(e.RemoteAddr),
+		e.Time.Format(commonTime), strconv.Quote(e.Method+" "+target+" "+
+			e.Proto), e.Status, size)
+	if combined {
+		fmt.Fprintf(&b, " %s %s", quoteOrDash(e.Referer),
+			quoteOrDash(e.UserAgent))
+	}
+	fmt.Fprintf(&b, " node=%s request_id=%s latency_ms=%.3f\n",
+		quoteIfNeeded(orDash(e.Node)), orDash(e.RequestID), e.LatencyMs)
+	return []byte(b.String())
+}
+
+// json returns the entry as a line holding a JSON object
+func (e *entry) json() []byte {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return nil
+	}
+	return append(line, '\n')
+}
+
+// orDash returns a value or a dash if it is empty, the way missing values
+// are written in the Common Log Format
+func orDash(value string) string {
+	if value == "" || value == "@" {
+		return "-"
+	}
+	return value
+}
+
+// quoteOrDash returns a quoted value or a dash if it is empty
+func quoteOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return strconv.Quote(value)
+}
+
+// quoteIfNeeded quotes a value taken from the request if it holds spaces,
+// quotes, = or characters that aren't printable, so that it can't end the
+// line or be read as another field
+func quoteIfNeeded(value string) string {
+	for _, r := range value {
+		if r == ' ' || r == '"' || r == '=' || !unicode.IsPrint(r) {
+			return strconv.Quote(value)
+		}
+	}
+	return value
+}
@@ -1,13 +1,14 @@
 package config
 
 import (
-	"github.com/claudetech/ini"
+	"sync"
 
-	lgr "github.com/SimplyVC/oasis_api_server/src/logger"
+	"github.com/claudetech/ini"
 )
 
 // Variables to be exported and used by application, set with default values
 var (
+	mutex          sync.RWMutex
 	confMain       ini.Config
 	confNodes      ini.Config
 	confSentry     ini.Config
@@ -33,48 +34,21 @@ func SetNodesFile(newFile string) {
 
 // GetSentryData returns Sentry configuration
 func GetSentryData() map[string]map[string]string {
+	mutex.RLock()
+	defer mutex.RUnlock()
 	return confSentry
 }
 
 // GetMain returns Main API configuration
 func GetMain() map[string]map[string]string {
+	mutex.RLock()
+	defer mutex.RUnlock()
 	return confMain
 }
 
 // GetNodes returns Nodes configuration
 func GetNodes() map[string]map[string]string {
+	mutex.RLock()
+	defer mutex.RUnlock()
 	return confNodes
 }
-
-// LoadMainConfiguration loads main configuration file from config folder
-func LoadMainConfiguration() (map[string]map[string]string, error) {
-
-	// Decode and read file containing Main API information
-	if err := ini.DecodeFile(mainConfigFile, &confMain); err != nil {
-		lgr.Error.Println(err)
-		return nil, err
-	}
-	return confMain, nil
-}
-
-// LoadNodesConfiguration loads node configuration file from config folder
-func LoadNodesConfiguration() (map[string]map[string]string, error) {
-
-	// Decode and read file containing Node information
-	if err := ini.DecodeFile(nodesFile, &confNodes); err != nil {
-		lgr.Error.Println(err)
-		return nil, err
-	}
-	return confNodes, nil
-}
-
-// LoadSentryConfiguration loads sentry configuration details
-func LoadSentryConfiguration() (map[string]map[string]string, error) {
-
-	// Decode and read file containing sentry information
-	if err := ini.DecodeFile(sentryFile, &confSentry); err != nil {
-		lgr.Error.Println(err)
-		return nil, err
-	}
-	return confSentry, nil
-}
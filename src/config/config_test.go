@@ -1,20 +1,29 @@
 package config_test
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
 	"fmt"
+	"math/big"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/SimplyVC/oasis_api_server/src/config"
 	lgr "github.com/SimplyVC/oasis_api_server/src/logger"
 )
 
-// Setting data to test with, valid and invalid path locations
+// Setting data to test with, invalid path locations
 const (
-	mainConfigFile = "config/user_config_main.ini"
-	nodesFile      = "config/user_config_nodes.ini"
-	mainFileFail   = "test_config_main_fail.ini"
-	nodesFileFail  = "test_config_nodes_fail.ini"
+	mainFileFail  = "test_config_main_fail.ini"
+	nodesFileFail = "test_config_nodes_fail.ini"
 )
 
 func TestMain(m *testing.M) {
@@ -36,53 +45,710 @@ func teardown() {
 	fmt.Printf("\n")
 }
 
-func TestLoadMainConfiguration_Success_1(t *testing.T) {
-	mainConf, _ := config.LoadMainConfiguration()
-	if mainConf == nil {
-		t.Errorf("Failed to load main config file from path.")
+// writeFile writes a configuration file into a temporary directory
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
 	}
+	return path
 }
 
-func TestLoadNodesConfig_Success_1(t *testing.T) {
-	nodesConf, _ := config.LoadNodesConfiguration()
-	if nodesConf == nil {
-		t.Errorf("Failed to load node config file from path.")
+// writeCertificate writes a self signed certificate and its key into a
+// temporary directory, returning their paths
+func writeCertificate(t *testing.T) (certPath, keyPath string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
 	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "oasis-node"},
+		DNSNames:     []string{"oasis-node"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template,
+		&key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certPath = writeFile(t, "tls_cert.pem", string(pem.EncodeToMemory(
+		&pem.Block{Type: "CERTIFICATE", Bytes: der})))
+	keyPath = writeFile(t, "tls_key.pem", string(pem.EncodeToMemory(
+		&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})))
+	return certPath, keyPath
 }
-func TestLoadMainConfiguration_Success_2(t *testing.T) {
-	mainConf, _ := config.LoadNodesConfiguration()
-	if mainConf == nil {
-		t.Errorf("Failed to load main config file from path.")
+
+func TestLoad_YAML_Success_1(t *testing.T) {
+	tlsPath, _ := writeCertificate(t)
+	config.SetFile(writeFile(t, "config.yaml", `
+api_server:
+  port: 3000
+  metrics_url: http://127.0.0.1:9100/metrics
+ledger:
+  enabled: true
+  addresses: [oasis1a, oasis1b]
+nodes:
+  - node_name: Oasis_Local
+    isocket_path: unix:/serverdir/node/internal.sock
+    prometheus_url: http://127.0.0.1:3000/
+sentries:
+  - node_name: sentry_1
+    ext_url: 112.13.121.12:9009
+    tls_path: `+tlsPath+`
+`))
+	defer config.SetFile("")
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Failed to load YAML configuration : %v", err)
+	}
+	if cfg.APIServer.Port != 3000 || len(cfg.Nodes) != 1 ||
+		len(cfg.Sentries) != 1 {
+		t.Errorf("Unexpected configuration loaded : %+v", cfg)
+	}
+	if cfg.Sections["ledger"]["addresses"] != "oasis1a,oasis1b" ||
+		cfg.Sections["ledger"]["enabled"] != "true" {
+		t.Errorf("Unexpected ledger section : %v", cfg.Sections["ledger"])
 	}
-	mainConf_1 := config.GetMain()
-	if mainConf_1 == nil {
-		t.Errorf("Failed to load main config file from path.")
+	if config.GetNodes()["node_0"]["node_name"] != "Oasis_Local" {
+		t.Errorf("Failed to expose nodes loaded from YAML.")
 	}
 }
 
-func TestLoadNodesConfig_Success_2(t *testing.T) {
-	nodesConf, _ := config.LoadNodesConfiguration()
-	if nodesConf == nil {
-		t.Errorf("Failed to load node config file from path.")
+func TestLoad_TOML_Success_1(t *testing.T) {
+	config.SetFile(writeFile(t, "config.toml", `
+[api_server]
+port = 3000
+
+[[nodes]]
+node_name = "Oasis_Local"
+isocket_path = "unix:/serverdir/node/internal.sock"
+
+[[nodes]]
+node_name = "Oasis_Local_1"
+isocket_path = "127.0.0.1:42261"
+`))
+	defer config.SetFile("")
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Failed to load TOML configuration : %v", err)
+	}
+	if node, ok := cfg.Node("Oasis_Local_1"); !ok ||
+		node.ISocketPath != "127.0.0.1:42261" {
+		t.Errorf("Unexpected nodes loaded : %+v", cfg.Nodes)
 	}
-	nodesConf_1 := config.GetNodes()
-	if nodesConf_1 == nil {
-		t.Errorf("Failed to load node config file from path.")
+
+	// Nodes added through the admin API are written as an array of tables
+	if _, err := config.AddNode(config.Node{Name: "Oasis_Local_2",
+		ISocketPath: "127.0.0.1:42262"}); err != nil {
+		t.Fatalf("Failed to add node : %v", err)
+	}
+	cfg, err = config.Load()
+	if err != nil || len(cfg.Nodes) != 3 {
+		t.Errorf("Failed to persist added node : %v", err)
 	}
 }
 
-func TestLoadMainConfiguration_Failure_1(t *testing.T) {
-	config.SetMainFile(mainFileFail)
-	mainConf, _ := config.LoadMainConfiguration()
-	if mainConf != nil {
-		t.Errorf("Failed to not load main config file from path.")
+func TestLoad_Overrides_Success_1(t *testing.T) {
+	config.SetFile(writeFile(t, "config.yaml", `
+api_server:
+  port: 3000
+nodes:
+  - node_name: Oasis_Local
+    isocket_path: unix:/serverdir/node/internal.sock
+`))
+	defer config.SetFile("")
+	defer config.ClearOverrides()
+
+	t.Setenv("OASIS_API__API_SERVER__PORT", "4000")
+	t.Setenv("OASIS_API__SAMPLER__ENABLED", "true")
+	t.Setenv("OASIS_API_NODES__NODE_0__NODE_NAME", "Oasis_Env")
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Failed to load configuration : %v", err)
+	}
+	if cfg.APIServer.Port != 4000 || cfg.Nodes[0].Name != "Oasis_Env" ||
+		cfg.Sections["sampler"]["enabled"] != "true" {
+		t.Errorf("Failed to apply environment overrides : %+v", cfg)
+	}
+
+	// Command line overrides take precedence over the environment
+	if err := config.ParseOverride("api_server.port=5000"); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = config.Load()
+	if err != nil {
+		t.Fatalf("Failed to load configuration : %v", err)
+	}
+	if cfg.APIServer.Port != 5000 {
+		t.Errorf("Failed to apply command line override : %+v", cfg)
+	}
+}
+
+func TestParseOverride_Failure_1(t *testing.T) {
+	if err := config.ParseOverride("port=5000"); err == nil {
+		t.Errorf("Failed to reject override without a section.")
+	}
+}
+
+func TestLoad_Failure_1(t *testing.T) {
+	config.SetFile(writeFile(t, "config.ini", "[api_server]\nport = 3000"))
+	defer config.SetFile("")
+
+	if _, err := config.Load(); err == nil {
+		t.Errorf("Failed to reject unsupported configuration format.")
+	}
+}
+
+func TestNew_Failure_1(t *testing.T) {
+	_, err := config.New(
+		map[string]map[string]string{
+			"api_server": {"port": "70000", "metrics_url": "localhost"},
+		},
+		map[string]map[string]string{
+			"node_0": {"node_name": "Oasis_Local", "isocket_path": "unix:"},
+			"node_1": {"node_name": "Oasis_Local",
+				"isocket_path": "unix:/serverdir/node/internal.sock"},
+		},
+		map[string]map[string]string{
+			"node_0": {"node_name": "sentry_1", "ext_url": "112.13.121.12",
+				"tls_path": "/nonexistent/tls_identity_cert.pem"},
+		})
+
+	var validationErr *config.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Failed to reject invalid configuration : %v", err)
+	}
+	for _, problem := range []string{"port", "metrics_url",
+		"isocket_path", "already used", "ext_url", "tls_path"} {
+		if !strings.Contains(err.Error(), problem) {
+			t.Errorf("Failed to report %s problem : %v", problem, err)
+		}
 	}
 }
 
-func TestLoadNodesConfig_Failure_1(t *testing.T) {
+func TestLoad_INI_Failure_1(t *testing.T) {
+	config.SetMainFile(mainFileFail)
 	config.SetNodesFile(nodesFileFail)
-	nodesConf, _ := config.LoadNodesConfiguration()
-	if nodesConf != nil {
-		t.Errorf("Failed to not load nodes file from path.")
+
+	if _, err := config.Load(); err == nil {
+		t.Errorf("Failed to not load config files from path.")
+	}
+}
+
+func TestLoad_INI_Success_1(t *testing.T) {
+	config.SetMainFile(writeFile(t, "main.ini", "[api_server]\nport = 3000"))
+	config.SetNodesFile(writeFile(t, "nodes.ini", "[node_0]\n"+
+		"node_name = Oasis_Local\n"+
+		"isocket_path = unix:/serverdir/node/internal.sock"))
+	config.SetSentryFile(filepath.Join(t.TempDir(), "missing.ini"))
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Failed to load INI configuration : %v", err)
+	}
+	if len(cfg.Nodes) != 1 || len(cfg.Sentries) != 0 {
+		t.Errorf("Unexpected configuration loaded : %+v", cfg)
+	}
+}
+
+// nodesYAML returns a YAML configuration with the given node names
+func nodesYAML(names ...string) string {
+	content := "api_server:\n  port: 3000\nnodes:\n"
+	for _, name := range names {
+		content += "  - node_name: " + name + "\n" +
+			"    isocket_path: unix:/serverdir/node/internal.sock\n"
+	}
+	return content
+}
+
+func TestReload_Success_1(t *testing.T) {
+	path := writeFile(t, "config.yaml", nodesYAML("Oasis_Local"))
+	config.SetFile(path)
+	defer config.SetFile("")
+	if _, err := config.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	var notified *config.Config
+	remove := config.OnReload(func(previous, next *config.Config) {
+		notified = next
+	})
+	defer remove()
+
+	os.WriteFile(path, []byte(nodesYAML("Oasis_Local_1")), 0o600)
+	cfg, err := config.Reload(config.TriggerSignal)
+	if err != nil {
+		t.Fatalf("Failed to reload configuration : %v", err)
+	}
+	if notified != cfg {
+		t.Errorf("Failed to notify reload subscriber.")
+	}
+
+	last := config.GetReloadStatus().LastReload
+	if !last.Success || len(last.AddedNodes) != 1 ||
+		len(last.RemovedNodes) != 1 {
+		t.Errorf("Unexpected reload status : %+v", last)
+	}
+}
+
+func TestReload_Failure_1(t *testing.T) {
+	path := writeFile(t, "config.yaml", nodesYAML("Oasis_Local"))
+	config.SetFile(path)
+	defer config.SetFile("")
+	loaded, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A duplicate node name is rejected and the current configuration kept
+	os.WriteFile(path, []byte(nodesYAML("Oasis_Local", "Oasis_Local")),
+		0o600)
+	if _, err := config.Reload(config.TriggerSignal); err == nil {
+		t.Errorf("Failed to reject invalid configuration.")
+	}
+	if config.Get() != loaded {
+		t.Errorf("Failed to keep current configuration.")
+	}
+	if last := config.GetReloadStatus().LastReload; last.Success ||
+		last.Error == "" {
+		t.Errorf("Unexpected reload status : %+v", last)
+	}
+}
+
+func TestStartWatching_Success_1(t *testing.T) {
+	path := writeFile(t, "config.yaml", nodesYAML("Oasis_Local"))
+	config.SetFile(path)
+	defer config.SetFile("")
+	if _, err := config.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := config.StartWatching(); err != nil {
+		t.Fatalf("Failed to watch configuration : %v", err)
+	}
+	defer config.StopWatching()
+
+	os.WriteFile(path, []byte(nodesYAML("Oasis_Local", "Oasis_Local_1")),
+		0o600)
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if len(config.Get().Nodes) == 2 {
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Errorf("Failed to reload configuration after its file changed.")
+}
+
+func TestEditNodes_YAML_Success_1(t *testing.T) {
+	path := writeFile(t, "config.yaml", "# comment\n"+nodesYAML("Oasis_Local"))
+	config.SetFile(path)
+	defer config.SetFile("")
+	if _, err := config.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := config.AddNode(config.Node{Name: "Oasis_Local_1",
+		ISocketPath: "127.0.0.1:42261"})
+	if err != nil {
+		t.Fatalf("Failed to add node : %v", err)
+	}
+	if len(cfg.Nodes) != 2 || config.Get() != cfg {
+		t.Errorf("Failed to reload added node : %+v", cfg.Nodes)
+	}
+
+	cfg, err = config.UpdateNode("Oasis_Local", config.Node{
+		Name:        "Oasis_Local_2",
+		ISocketPath: "unix:/serverdir/node_2/internal.sock"})
+	if err != nil {
+		t.Fatalf("Failed to update node : %v", err)
+	}
+	if _, ok := cfg.Node("Oasis_Local_2"); !ok {
+		t.Errorf("Failed to rename node : %+v", cfg.Nodes)
+	}
+
+	if _, err = config.RemoveNode("Oasis_Local_1"); err != nil {
+		t.Fatalf("Failed to remove node : %v", err)
+	}
+
+	// The file itself holds the edits once they are loaded again
+	cfg, err = config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Nodes) != 1 || cfg.Nodes[0].Name != "Oasis_Local_2" ||
+		cfg.APIServer.Port != 3000 {
+		t.Errorf("Failed to persist edits : %+v", cfg)
+	}
+}
+
+func TestEditNodes_INI_Success_1(t *testing.T) {
+	nodesPath := writeFile(t, "nodes.ini", "[node_0]\n"+
+		"node_name = Oasis_Local\n"+
+		"isocket_path = unix:/serverdir/node/internal.sock")
+	config.SetMainFile(writeFile(t, "main.ini", "[api_server]\nport = 3000"))
+	config.SetNodesFile(nodesPath)
+	config.SetSentryFile(filepath.Join(t.TempDir(), "sentry.ini"))
+
+	tlsPath, _ := writeCertificate(t)
+	if _, err := config.AddSentry(config.Sentry{Name: "sentry_1",
+		ExtURL: "112.13.121.12:9009", TLSPath: tlsPath}); err != nil {
+		t.Fatalf("Failed to add sentry : %v", err)
+	}
+	if _, err := config.AddNode(config.Node{Name: "Oasis_Local_1",
+		ISocketPath: "127.0.0.1:42261"}); err != nil {
+		t.Fatalf("Failed to add node : %v", err)
+	}
+
+	data, _ := os.ReadFile(nodesPath)
+	expected := "[node_1]\nnode_name = Oasis_Local_1\n" +
+		"isocket_path = 127.0.0.1:42261\n"
+	if !strings.Contains(string(data), expected) {
+		t.Errorf("Unexpected nodes file written : %s", data)
+	}
+	if len(config.Get().Sentries) != 1 {
+		t.Errorf("Failed to reload added sentry.")
+	}
+}
+
+func TestEditNodes_INI_Success_2(t *testing.T) {
+	nodesPath := writeFile(t, "nodes.ini", "; Nodes of the API\n\n"+
+		"[node_0]\n"+
+		"node_name = Oasis_Local\n"+
+		"isocket_path = unix:/serverdir/node/internal.sock ; main node\n\n"+
+		"; Backup node\n"+
+		"[node_1]\n"+
+		"node_name = Oasis_Local_1\n"+
+		"isocket_path = unix:/serverdir/node_1/internal.sock\n\n"+
+		"[node_2]\n"+
+		"node_name = Oasis_Local_2\n"+
+		"isocket_path = unix:/serverdir/node_2/internal.sock\n")
+	config.SetMainFile(writeFile(t, "main.ini", "[api_server]\nport = 3000"))
+	config.SetNodesFile(nodesPath)
+	config.SetSentryFile(filepath.Join(t.TempDir(), "sentry.ini"))
+
+	if _, err := config.RemoveNode("Oasis_Local_1"); err != nil {
+		t.Fatalf("Failed to remove node : %v", err)
+	}
+	if _, err := config.UpdateNode("Oasis_Local_2", config.Node{
+		Name:          "Oasis_Local_2",
+		ISocketPath:   "unix:/serverdir/node_2/internal.sock",
+		PrometheusURL: "http://127.0.0.1:3002/"}); err != nil {
+		t.Fatalf("Failed to update node : %v", err)
+	}
+	cfg, err := config.AddNode(config.Node{Name: "Oasis_Local_3",
+		ISocketPath: "127.0.0.1:42261"})
+	if err != nil {
+		t.Fatalf("Failed to add node : %v", err)
+	}
+
+	// Sections keep their names and comments, new ones are numbered after
+	// the others
+	data, _ := os.ReadFile(nodesPath)
+	expected := "; Nodes of the API\n\n" +
+		"[node_0]\n" +
+		"node_name = Oasis_Local\n" +
+		"isocket_path = unix:/serverdir/node/internal.sock ; main node\n\n" +
+		"[node_2]\n" +
+		"node_name = Oasis_Local_2\n" +
+		"isocket_path = unix:/serverdir/node_2/internal.sock\n" +
+		"prometheus_url = http://127.0.0.1:3002/\n\n" +
+		"[node_3]\n" +
+		"node_name = Oasis_Local_3\n" +
+		"isocket_path = 127.0.0.1:42261\n"
+	if string(data) != expected {
+		t.Errorf("Unexpected nodes file written : %s", data)
+	}
+	if node, ok := cfg.Node("Oasis_Local_2"); !ok || node.Section != "node_2" {
+		t.Errorf("Failed to keep section of node : %+v", cfg.Nodes)
+	}
+}
+
+func TestEditNodes_INI_RoundTrip_1(t *testing.T) {
+	config.SetMainFile(writeFile(t, "main.ini", "[api_server]\nport = 3000"))
+	config.SetNodesFile(writeFile(t, "nodes.ini", ""))
+	config.SetSentryFile(filepath.Join(t.TempDir(), "sentry.ini"))
+
+	node := config.Node{Section: "node_0", Name: "Oasis Local: 1",
+		ISocketPath:   "unix:/serverdir/node/internal.sock",
+		PrometheusURL: "http://127.0.0.1:3000/metrics?node:1#main"}
+	if _, err := config.AddNode(node); err != nil {
+		t.Fatalf("Failed to add node : %v", err)
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Nodes) != 1 || cfg.Nodes[0] != node {
+		t.Errorf("Failed to read back added node %+v, got %+v", node,
+			cfg.Nodes)
+	}
+
+	// Values that would be read back differently are refused
+	var validationErr *config.ValidationError
+	for _, changed := range []config.Node{
+		{Name: "Oasis_Local", ISocketPath: node.ISocketPath,
+			PrometheusURL: "http://127.0.0.1:3000/metrics?a=b"},
+		{Name: "Oasis_Local\n[node_9]", ISocketPath: node.ISocketPath},
+		{Name: "Oasis_Local ; main", ISocketPath: node.ISocketPath},
+	} {
+		if _, err := config.UpdateNode(node.Name, changed); !errors.As(err,
+			&validationErr) {
+			t.Errorf("Failed to refuse node %+v : %v", changed, err)
+		}
+	}
+	if cfg, _ := config.Load(); len(cfg.Nodes) != 1 ||
+		cfg.Nodes[0] != node {
+		t.Errorf("Failed to leave nodes file untouched : %+v", cfg.Nodes)
+	}
+}
+
+func TestEditNodes_Failure_1(t *testing.T) {
+	content := nodesYAML("Oasis_Local")
+	path := writeFile(t, "config.yaml", content)
+	config.SetFile(path)
+	defer config.SetFile("")
+	if _, err := config.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := config.AddNode(config.Node{Name: "Oasis_Local",
+		ISocketPath: "127.0.0.1:42261"}); !errors.Is(err,
+		config.ErrNameExists) {
+		t.Errorf("Failed to reject duplicate node : %v", err)
+	}
+	if _, err := config.RemoveNode("Unicorn"); !errors.Is(err,
+		config.ErrNameNotFound) {
+		t.Errorf("Failed to reject unknown node : %v", err)
+	}
+
+	var validationErr *config.ValidationError
+	if _, err := config.AddSentry(config.Sentry{Name: "sentry_1",
+		ExtURL:  "112.13.121.12:9009",
+		TLSPath: "/nonexistent/tls_identity_cert.pem"}); !errors.As(err,
+		&validationErr) {
+		t.Errorf("Failed to reject sentry without TLS file : %v", err)
+	}
+
+	if data, _ := os.ReadFile(path); string(data) != content {
+		t.Errorf("Failed to leave file untouched : %s", data)
+	}
+}
+
+func TestNew_Failure_2(t *testing.T) {
+	tlsPath := writeFile(t, "tls_cert.pem", "")
+	_, err := config.New(
+		map[string]map[string]string{"api_server": {"port": "3000"}},
+		map[string]map[string]string{
+			"node_0": {"node_name": "Oasis_Remote",
+				"isocket_path": "10.0.0.2:42261"},
+			"node_1": {"node_name": "Oasis_Remote_1",
+				"isocket_path": "10.0.0.3:42261", "tls_cert_path": tlsPath,
+				"tls_client_cert_path": tlsPath},
+			"node_2": {"node_name": "Oasis_Local",
+				"isocket_path":  "unix:/serverdir/node/internal.sock",
+				"tls_cert_path": tlsPath},
+		}, nil)
+
+	for _, problem := range []string{
+		"[nodes.node_0] tls_cert_path is required",
+		"[nodes.node_1] tls_client_cert_path and tls_client_key_path",
+		"[nodes.node_2] tls_cert_path is only used"} {
+		if err == nil || !strings.Contains(err.Error(), problem) {
+			t.Errorf("Failed to report %s problem : %v", problem, err)
+		}
+	}
+}
+
+func TestNew_Success_1(t *testing.T) {
+	certPath, keyPath := writeCertificate(t)
+	cfg, err := config.New(
+		map[string]map[string]string{"api_server": {"port": "3000"}},
+		map[string]map[string]string{
+			"node_0": {"node_name": "Oasis_Remote",
+				"isocket_path": "10.0.0.2:42261", "tls_cert_path": certPath,
+				"tls_client_cert_path": certPath,
+				"tls_client_key_path":  keyPath},
+			"node_1": {"node_name": "Oasis_Tunnel",
+				"isocket_path": "localhost:42261"},
+		}, nil)
+	if err != nil {
+		t.Fatalf("Failed to accept remote nodes : %v", err)
+	}
+	if cfg.Nodes[0].TLSClientKeyPath != keyPath {
+		t.Errorf("Unexpected nodes loaded : %+v", cfg.Nodes)
+	}
+	if cfg.APIServer.ShutdownTimeout != 30*time.Second {
+		t.Errorf("Unexpected default shutdown timeout %s",
+			cfg.APIServer.ShutdownTimeout)
+	}
+}
+
+func TestNew_Failure_5(t *testing.T) {
+	certPath, _ := writeCertificate(t)
+	_, otherKeyPath := writeCertificate(t)
+	emptyPath := writeFile(t, "empty.pem", "")
+	_, err := config.New(
+		map[string]map[string]string{"api_server": {"port": "3000"}},
+		map[string]map[string]string{
+			"node_0": {"node_name": "Oasis_Remote",
+				"isocket_path": "10.0.0.2:42261", "tls_cert_path": emptyPath},
+			"node_1": {"node_name": "Oasis_Remote_1",
+				"isocket_path": "10.0.0.3:42261", "tls_cert_path": certPath,
+				"tls_client_cert_path": certPath,
+				"tls_client_key_path":  otherKeyPath},
+		},
+		map[string]map[string]string{
+			"node_0": {"node_name": "sentry_1", "ext_url": "10.0.0.4:9009",
+				"tls_path": otherKeyPath},
+		})
+
+	// Files that exist but can't be used to connect are refused
+	for _, problem := range []string{
+		"[nodes.node_0] tls_cert_path " + fmt.Sprintf("%q", emptyPath) +
+			" doesn't hold a PEM encoded certificate",
+		"[nodes.node_1] tls_client_cert_path",
+		"[sentries.node_0] tls_path"} {
+		if err == nil || !strings.Contains(err.Error(), problem) {
+			t.Errorf("Failed to report %s problem : %v", problem, err)
+		}
+	}
+}
+
+func TestNew_Failure_6(t *testing.T) {
+	nodes := map[string]map[string]string{
+		"node_0": {"node_name": "Oasis_Local",
+			"isocket_path": "unix:/serverdir/node/internal.sock"},
+	}
+
+	// The admin API isn't served without an admin listener
+	_, err := config.New(map[string]map[string]string{
+		"api_server": {"port": "3000"},
+		"admin":      {"token": "0123456789abcdef"},
+	}, nodes, nil)
+	if err == nil || !strings.Contains(err.Error(),
+		"[admin] token needs a listener with admin = true") {
+		t.Errorf("Failed to report missing admin listener : %v", err)
+	}
+
+	_, err = config.New(map[string]map[string]string{
+		"admin":      {"token": "0123456789abcdef"},
+		"listener_0": {"address": "127.0.0.1:3000"},
+		"listener_1": {"address": "127.0.0.1:3001", "admin": "true"},
+	}, nodes, nil)
+	if err != nil {
+		t.Errorf("Failed to accept admin listener : %v", err)
+	}
+}
+
+func TestNew_Failure_3(t *testing.T) {
+	_, err := config.New(
+		map[string]map[string]string{"api_server": {"port": "3000",
+			"tls_key_path": "/nonexistent/key.pem", "tls_min_version": "1.0",
+			"tls_client_ca_path": "/nonexistent/ca.pem",
+			"shutdown_timeout":   "-1"}},
+		map[string]map[string]string{
+			"node_0": {"node_name": "Oasis_Local",
+				"isocket_path": "unix:/serverdir/node/internal.sock"},
+		}, nil)
+
+	for _, problem := range []string{"tls_min_version",
+		"tls_cert_path and tls_key_path", "tls_client_ca_path needs",
+		"tls_key_path \"/nonexistent/key.pem\" can't be read",
+		"shutdown_timeout"} {
+		if err == nil || !strings.Contains(err.Error(), problem) {
+			t.Errorf("Failed to report %s problem : %v", problem, err)
+		}
+	}
+}
+
+func TestLoad_Listeners_Success_1(t *testing.T) {
+	config.SetFile(writeFile(t, "config.yaml", `
+api_server: {}
+listeners:
+  - address: unix:/run/oasis_api/api.sock
+    socket_mode: "0600"
+  - address: 127.0.0.1:3001
+    admin: true
+nodes:
+  - node_name: Oasis_Local
+    isocket_path: unix:/serverdir/node/internal.sock
+`))
+	defer config.SetFile("")
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Failed to load listeners : %v", err)
+	}
+	if len(cfg.Listeners) != 2 || cfg.Listeners[0].SocketMode != 0600 ||
+		!cfg.Listeners[1].Admin {
+		t.Errorf("Unexpected listeners loaded : %+v", cfg.Listeners)
+	}
+}
+
+func TestNew_Failure_4(t *testing.T) {
+	_, err := config.New(
+		map[string]map[string]string{
+			"listener_0": {"address": "localhost"},
+			"listener_1": {"address": "127.0.0.1:3001",
+				"socket_mode": "0600", "admin": "yes please"},
+			"listener_2": {"address": "unix:/run/api.sock",
+				"socket_mode": "999"},
+			"listener_3": {"address": "127.0.0.1:3001"},
+		},
+		map[string]map[string]string{
+			"node_0": {"node_name": "Oasis_Local",
+				"isocket_path": "unix:/serverdir/node/internal.sock"},
+		}, nil)
+
+	for _, problem := range []string{"[listener_0] address",
+		"[listener_1] socket_mode is only used", "[listener_1] admin",
+		"[listener_2] socket_mode needs", "[listener_3] address " +
+			"\"127.0.0.1:3001\" is already used"} {
+		if err == nil || !strings.Contains(err.Error(), problem) {
+			t.Errorf("Failed to report %s problem : %v", problem, err)
+		}
+	}
+	if err != nil && strings.Contains(err.Error(), "[api_server] port") {
+		t.Errorf("Failed to accept listeners without port : %v", err)
+	}
+}
+
+func TestNew_Validator_Failure_1(t *testing.T) {
+	remove := config.AddValidator(func(
+		main map[string]map[string]string) []string {
+
+		if main["logging"]["level"] != "info" {
+			return []string{"logging level needs to be info"}
+		}
+		return nil
+	})
+	main := map[string]map[string]string{
+		"api_server": {"port": "3000"}, "logging": {"level": "loud"}}
+	nodes := map[string]map[string]string{
+		"node_0": {"node_name": "Oasis_Local",
+			"isocket_path": "unix:/serverdir/node/internal.sock"},
+	}
+
+	var validationErr *config.ValidationError
+	if _, err := config.New(main, nodes, nil); !errors.As(err,
+		&validationErr) || !strings.Contains(err.Error(), "logging level") {
+		t.Errorf("Failed to report problem of validator : %v", err)
+	}
+
+	// The configuration is accepted again once the validator is removed
+	remove()
+	if _, err := config.New(main, nodes, nil); err != nil {
+		t.Errorf("Failed to remove validator : %v", err)
 	}
 }
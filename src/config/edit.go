@@ -0,0 +1,500 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	toml "github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// ErrNameExists is returned when adding a node or sentry whose name is
+	// already used
+	ErrNameExists = errors.New("name is already used")
+
+	// ErrNameNotFound is returned when updating or removing a node or
+	// sentry that isn't configured
+	ErrNameNotFound = errors.New("name isn't configured")
+
+	editMutex sync.Mutex
+)
+
+// keyOrder is the order in which known keys are written to INI files, other
+// keys follow in alphabetical order
+var keyOrder = []string{"node_name", "isocket_path", "prometheus_url",
+	"tls_cert_path", "tls_client_cert_path", "tls_client_key_path",
+	"tls_server_name", "ext_url", "tls_path"}
+
+// values returns the section of a node
+func (n Node) values() map[string]string {
+	return map[string]string{
+		"node_name":            n.Name,
+		"isocket_path":         n.ISocketPath,
+		"prometheus_url":       n.PrometheusURL,
+		"tls_cert_path":        n.TLSCertPath,
+		"tls_client_cert_path": n.TLSClientCertPath,
+		"tls_client_key_path":  n.TLSClientKeyPath,
+		"tls_server_name":      n.TLSServerName,
+	}
+}
+
+// values returns the section of a sentry
+func (s Sentry) values() map[string]string {
+	return map[string]string{
+		"node_name": s.Name,
+		"ext_url":   s.ExtURL,
+		"tls_path":  s.TLSPath,
+	}
+}
+
+// AddNode adds a node to the configuration file and reloads it
+func AddNode(node Node) (*Config, error) {
+	return edit("nodes", add(node.values()))
+}
+
+// UpdateNode replaces the node with the given name, which can be renamed, in
+// the configuration file and reloads it
+func UpdateNode(name string, node Node) (*Config, error) {
+	return edit("nodes", update(name, node.values()))
+}
+
+// RemoveNode removes the node with the given name from the configuration
+// file and reloads it
+func RemoveNode(name string) (*Config, error) {
+	return edit("nodes", remove(name))
+}
+
+// AddSentry adds a sentry to the configuration file and reloads it
+func AddSentry(sentry Sentry) (*Config, error) {
+	return edit("sentries", add(sentry.values()))
+}
+
+// UpdateSentry replaces the sentry with the given name, which can be
+// renamed, in the configuration file and reloads it
+func UpdateSentry(name string, sentry Sentry) (*Config, error) {
+	return edit("sentries", update(name, sentry.values()))
+}
+
+// RemoveSentry removes the sentry with the given name from the
+// configuration file and reloads it
+func RemoveSentry(name string) (*Config, error) {
+	return edit("sentries", remove(name))
+}
+
+// add returns a change adding a section
+func add(values map[string]string) func(map[string]map[string]string) error {
+	return func(sections map[string]map[string]string) error {
+		if find(sections, values["node_name"]) != "" {
+			return fmt.Errorf("%s : %w", values["node_name"], ErrNameExists)
+		}
+		sections[newSection(sections)] = withoutEmpty(values)
+		return nil
+	}
+}
+
+// update returns a change replacing the known keys of a section while
+// keeping the keys it doesn't know about
+func update(name string,
+	values map[string]string) func(map[string]map[string]string) error {
+
+	return func(sections map[string]map[string]string) error {
+		section := find(sections, name)
+		if section == "" {
+			return fmt.Errorf("%s : %w", name, ErrNameNotFound)
+		}
+		if values["node_name"] != name &&
+			find(sections, values["node_name"]) != "" {
+			return fmt.Errorf("%s : %w", values["node_name"], ErrNameExists)
+		}
+		for key, value := range values {
+			sections[section][key] = value
+		}
+		sections[section] = withoutEmpty(sections[section])
+		return nil
+	}
+}
+
+// remove returns a change removing a section
+func remove(name string) func(map[string]map[string]string) error {
+	return func(sections map[string]map[string]string) error {
+		section := find(sections, name)
+		if section == "" {
+			return fmt.Errorf("%s : %w", name, ErrNameNotFound)
+		}
+		delete(sections, section)
+		return nil
+	}
+}
+
+// find returns the section with the given name or an empty string
+func find(sections map[string]map[string]string, name string) string {
+	for section, values := range sections {
+		if values["node_name"] == name {
+			return section
+		}
+	}
+	return ""
+}
+
+// newSection returns the name of a section added after the others, numbered
+// after the highest one with the same prefix so that no existing section is
+// renamed. Sections are named node_<n> unless the file uses another prefix.
+func newSection(sections map[string]map[string]string) string {
+	prefix, next := "node_", 0
+	for _, section := range sortedSections(sections) {
+		i := strings.LastIndex(section, "_")
+		number, err := strconv.Atoi(section[i+1:])
+		if i <= 0 || err != nil {
+			continue
+		}
+		prefix = section[:i+1]
+		if number >= next {
+			next = number + 1
+		}
+	}
+	return fmt.Sprintf("%s%d", prefix, next)
+}
+
+// withoutEmpty drops the keys of a section that have no value
+func withoutEmpty(values map[string]string) map[string]string {
+	section := map[string]string{}
+	for key, value := range values {
+		if value != "" {
+			section[key] = value
+		}
+	}
+	return section
+}
+
+// edit applies a change to the nodes or sentries read from the configuration
+// files, validates the result with the environment and command line
+// overrides applied, writes it back and reloads the configuration. Sections
+// keep their names so that environment overrides keep applying to them.
+func edit(list string,
+	change func(map[string]map[string]string) error) (*Config, error) {
+
+	editMutex.Lock()
+	defer editMutex.Unlock()
+
+	main, nodes, sentries, err := readFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	edited := nodes
+	if list == "sentries" {
+		edited = sentries
+	}
+	if err := change(edited); err != nil {
+		return nil, err
+	}
+
+	// INI files can't hold every value, they are refused instead of being
+	// written and read back differently
+	if configFile == "" {
+		if problems := checkINIValues(list, edited); len(problems) > 0 {
+			return nil, &ValidationError{Problems: problems}
+		}
+	}
+
+	candidateMain, candidateNodes, candidateSentries := copySections(main),
+		copySections(nodes), copySections(sentries)
+	applyEnvironment(os.Environ(), candidateMain, candidateNodes,
+		candidateSentries)
+	for section, values := range overrides {
+		for key, value := range values {
+			setValue(candidateMain, section, key, value)
+		}
+	}
+	if _, err := New(candidateMain, candidateNodes,
+		candidateSentries); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case configFile != "":
+		err = writeStructured(configFile, list, edited)
+	case list == "sentries":
+		err = writeINI(sentryFile, edited)
+	default:
+		err = writeINI(nodesFile, edited)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to write configuration : %v", err)
+	}
+	return Reload(TriggerAdmin)
+}
+
+// copySections returns a deep copy of a configuration
+func copySections(
+	conf map[string]map[string]string) map[string]map[string]string {
+
+	copied := make(map[string]map[string]string, len(conf))
+	for section, values := range conf {
+		copied[section] = make(map[string]string, len(values))
+		for key, value := range values {
+			copied[section][key] = value
+		}
+	}
+	return copied
+}
+
+// writeStructured replaces the nodes or sentries list of a YAML or TOML
+// configuration file, leaving its other tables as they are
+func writeStructured(path, list string,
+	conf map[string]map[string]string) error {
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	yamlFile := strings.ToLower(filepath.Ext(path)) != ".toml"
+	raw := map[string]interface{}{}
+	if yamlFile {
+		err = yaml.Unmarshal(data, &raw)
+	} else {
+		err = toml.Unmarshal(data, &raw)
+	}
+	if err != nil {
+		return err
+	}
+
+	items := []interface{}{}
+	for _, section := range sortedSections(conf) {
+		item := map[string]interface{}{}
+		for key, value := range conf[section] {
+			item[key] = value
+		}
+		items = append(items, item)
+	}
+	raw[list] = items
+
+	if yamlFile {
+		data, err = yaml.Marshal(raw)
+	} else {
+		data, err = toml.Marshal(raw)
+	}
+	if err != nil {
+		return err
+	}
+	return writeAtomically(path, data)
+}
+
+// writeINI writes the sections of a nodes or sentry INI file, editing the
+// file in place so that its comments and the order of its sections and keys
+// are kept
+func writeINI(path string, conf map[string]map[string]string) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return writeAtomically(path, []byte(editINI(string(data), conf)))
+}
+
+// iniBlock holds the lines of an INI file section, starting with the
+// comments right above its header
+type iniBlock struct {
+	section string
+	lines   []string
+}
+
+// editINI replaces the values of the sections in an INI file with conf. The
+// sections that aren't in conf are dropped with the comments above them,
+// keys are replaced where they are and new keys and sections are added
+// after the others.
+func editINI(text string, conf map[string]map[string]string) string {
+	// The lines before the first section are kept as they are
+	blocks := []*iniBlock{{}}
+	if text = strings.TrimRight(text, "\r\n"); text != "" {
+		for _, line := range strings.Split(text, "\n") {
+			section, ok := iniSection(line)
+			current := blocks[len(blocks)-1]
+			if !ok {
+				current.lines = append(current.lines, line)
+				continue
+			}
+
+			// Comments and blank lines right above a header describe its
+			// section rather than the one before
+			above := len(current.lines)
+			for current.section != "" && above > 0 &&
+				!isINIKey(current.lines[above-1]) {
+				above--
+			}
+			block := &iniBlock{section: section}
+			block.lines = append(block.lines, current.lines[above:]...)
+			block.lines = append(block.lines, line)
+			current.lines = current.lines[:above]
+			blocks = append(blocks, block)
+		}
+	}
+
+	var lines []string
+	written := map[string]map[string]bool{}
+	for _, block := range blocks {
+		values, ok := conf[block.section]
+		switch {
+		case block.section == "":
+			lines = append(lines, block.lines...)
+		case ok:
+			if written[block.section] == nil {
+				written[block.section] = map[string]bool{}
+			}
+			lines = append(lines, editINISection(block.lines, values,
+				written[block.section])...)
+		}
+	}
+
+	for _, section := range sortedSections(conf) {
+		if written[section] != nil {
+			continue
+		}
+		if len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) != "" {
+			lines = append(lines, "")
+		}
+		lines = append(lines, fmt.Sprintf("[%s]", section))
+		lines = append(lines, editINISection(nil, conf[section],
+			map[string]bool{})...)
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// editINISection replaces the keys in the lines of a section with values,
+// dropping the keys that aren't in values or were already written and
+// adding the missing ones after the last key
+func editINISection(lines []string, values map[string]string,
+	written map[string]bool) []string {
+
+	var edited []string
+	last := 0
+	for _, line := range lines {
+		if _, ok := iniSection(line); ok {
+			edited = append(edited, line)
+			last = len(edited)
+			continue
+		}
+		if !isINIKey(line) {
+			edited = append(edited, line)
+			continue
+		}
+
+		key, value, _ := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if _, ok := values[key]; !ok || written[key] {
+			continue
+		}
+		written[key] = true
+
+		// Unchanged values keep their line and any comment after them
+		value, _, _ = strings.Cut(value, ";")
+		if strings.TrimSpace(value) != values[key] {
+			line = fmt.Sprintf("%s = %s", key, values[key])
+		}
+		edited = append(edited, line)
+		last = len(edited)
+	}
+
+	var missing []string
+	for _, key := range sectionKeys(values) {
+		if !written[key] {
+			written[key] = true
+			missing = append(missing, fmt.Sprintf("%s = %s", key,
+				values[key]))
+		}
+	}
+	return append(edited[:last:last], append(missing, edited[last:]...)...)
+}
+
+// iniSection returns the name of the section whose header is line
+func iniSection(line string) (string, bool) {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "[") || !strings.HasSuffix(line, "]") {
+		return "", false
+	}
+	return strings.TrimSpace(line[1 : len(line)-1]), true
+}
+
+// isINIKey checks whether line sets a key
+func isINIKey(line string) bool {
+	line = strings.TrimSpace(line)
+	return line != "" && !strings.HasPrefix(line, ";") &&
+		strings.Contains(line, "=")
+}
+
+// iniSpecialChars can't be part of an INI value, = is dropped and ; starts a
+// comment when the file is read, while line breaks and brackets could start
+// other keys or sections
+const iniSpecialChars = "=;[]\r\n"
+
+// checkINIValues returns the values of the nodes or sentries that can't be
+// written to an INI file
+func checkINIValues(list string,
+	conf map[string]map[string]string) []string {
+
+	var problems []string
+	for _, section := range sortedSections(conf) {
+		for _, key := range sectionKeys(conf[section]) {
+			if strings.ContainsAny(conf[section][key], iniSpecialChars) {
+				problems = append(problems, fmt.Sprintf("[%s.%s] %s can't "+
+					"contain =, ;, [, ] or line breaks in an INI file",
+					list, section, key))
+			}
+		}
+	}
+	return problems
+}
+
+// sectionKeys returns the keys of a section in the order they are written
+func sectionKeys(values map[string]string) []string {
+	var keys, others []string
+	known := map[string]bool{}
+	for _, key := range keyOrder {
+		known[key] = true
+		if _, ok := values[key]; ok {
+			keys = append(keys, key)
+		}
+	}
+	for key := range values {
+		if !known[key] {
+			others = append(others, key)
+		}
+	}
+	sort.Strings(others)
+	return append(keys, others...)
+}
+
+// writeAtomically replaces a file by renaming a temporary file over it, so
+// that the file is never seen half written, keeping its permissions
+func writeAtomically(path string, data []byte) error {
+	mode := os.FileMode(0600)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
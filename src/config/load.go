@@ -0,0 +1,282 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/claudetech/ini"
+	toml "github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+
+	lgr "github.com/SimplyVC/oasis_api_server/src/logger"
+)
+
+// Prefixes of the environment variables that override the configuration,
+// followed by <SECTION>__<KEY> such as OASIS_API__API_SERVER__PORT
+const (
+	envMainPrefix     = "OASIS_API__"
+	envNodesPrefix    = "OASIS_API_NODES__"
+	envSentriesPrefix = "OASIS_API_SENTRIES__"
+)
+
+var (
+	configFile string
+	overrides  = map[string]map[string]string{}
+	current    *Config
+	loadedAt   time.Time
+)
+
+// SetFile sets a YAML or TOML file holding the whole configuration, when
+// set it is read instead of the main, nodes and sentry INI files
+func SetFile(newFile string) {
+	configFile = newFile
+}
+
+// SetOverride sets a value of the main configuration that takes precedence
+// over both the configuration files and the environment
+func SetOverride(section, key, value string) {
+	setValue(overrides, section, key, value)
+}
+
+// ParseOverride parses a section.key=value override as given on the
+// command line and sets it
+func ParseOverride(override string) error {
+	name, value, ok := strings.Cut(override, "=")
+	section, key, okName := strings.Cut(name, ".")
+	if !ok || !okName || section == "" || key == "" {
+		return fmt.Errorf("override needs to be section.key=value, got %q",
+			override)
+	}
+	SetOverride(section, key, value)
+	return nil
+}
+
+// ClearOverrides removes every override set through SetOverride
+func ClearOverrides() {
+	overrides = map[string]map[string]string{}
+}
+
+// Get returns the configuration loaded by Load
+func Get() *Config {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return current
+}
+
+// Load reads the configuration files, applies the environment and command
+// line overrides on top of them and validates the result
+func Load() (*Config, error) {
+	main, nodes, sentries, err := readFiles()
+	if err != nil {
+		lgr.Error.Println(err)
+		return nil, err
+	}
+
+	applyEnvironment(os.Environ(), main, nodes, sentries)
+	for section, values := range overrides {
+		for key, value := range values {
+			setValue(main, section, key, value)
+		}
+	}
+
+	cfg, err := New(main, nodes, sentries)
+	if err != nil {
+		return nil, err
+	}
+
+	// Swap the whole configuration at once so that requests in flight
+	// keep reading either the previous or the new one, never a mix of both
+	mutex.Lock()
+	defer mutex.Unlock()
+	confMain, confNodes, confSentry = main, nodes, sentries
+	current = cfg
+	loadedAt = time.Now()
+	return cfg, nil
+}
+
+// readFiles reads either the single YAML or TOML configuration file or the
+// main, nodes and sentry INI files, the sentry file being optional
+func readFiles() (main, nodes, sentries map[string]map[string]string,
+	err error) {
+
+	if configFile != "" {
+		return decodeFile(configFile)
+	}
+
+	if err := ini.DecodeFile(mainConfigFile, &main); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to read main "+
+			"configuration %s : %v", mainConfigFile, err)
+	}
+	if err := ini.DecodeFile(nodesFile, &nodes); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to read nodes "+
+			"configuration %s : %v", nodesFile, err)
+	}
+	if err := ini.DecodeFile(sentryFile, &sentries); err != nil &&
+		!os.IsNotExist(err) {
+		return nil, nil, nil, fmt.Errorf("failed to read sentry "+
+			"configuration %s : %v", sentryFile, err)
+	}
+	if sentries == nil {
+		sentries = map[string]map[string]string{}
+	}
+	return main, nodes, sentries, nil
+}
+
+// decodeFile reads a YAML or TOML configuration file, its nodes and
+// sentries lists become node_<index> sections like in the INI files, its
+// listeners list becomes listener_<index> sections of the main
+// configuration and every other table becomes a section of it
+func decodeFile(path string) (main, nodes,
+	sentries map[string]map[string]string, err error) {
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to read configuration "+
+			"%s : %v", path, err)
+	}
+
+	raw := map[string]interface{}{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &raw)
+	case ".toml":
+		err = toml.Unmarshal(data, &raw)
+	default:
+		return nil, nil, nil, fmt.Errorf("configuration %s needs to be a "+
+			".yaml, .yml or .toml file", path)
+	}
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to parse configuration "+
+			"%s : %v", path, err)
+	}
+
+	main = map[string]map[string]string{}
+	nodes = map[string]map[string]string{}
+	sentries = map[string]map[string]string{}
+	for name, value := range raw {
+		switch name {
+		case "nodes":
+			err = decodeList(name, "node", value, nodes)
+		case "sentries":
+			err = decodeList(name, "node", value, sentries)
+		case "listeners":
+			err = decodeList(name, "listener", value, main)
+		default:
+			main[name], err = decodeSection(name, value)
+		}
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to parse "+
+				"configuration %s : %v", path, err)
+		}
+	}
+	return main, nodes, sentries, nil
+}
+
+// decodeList decodes a list of tables into <prefix>_<index> sections
+func decodeList(name, prefix string, value interface{},
+	conf map[string]map[string]string) error {
+
+	list, ok := value.([]interface{})
+	if !ok {
+		return fmt.Errorf("%s needs to be a list", name)
+	}
+	for i, item := range list {
+		section := fmt.Sprintf("%s_%d", prefix, i)
+		values, err := decodeSection(name+"."+section, item)
+		if err != nil {
+			return err
+		}
+		conf[section] = values
+	}
+	return nil
+}
+
+// decodeSection decodes a table into a section, lists are joined with
+// commas the way they are written in the INI files
+func decodeSection(name string, value interface{}) (map[string]string,
+	error) {
+
+	table, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s needs to be a table", name)
+	}
+	section := map[string]string{}
+	for key, item := range table {
+		str, err := stringValue(item)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s %v", name, key, err)
+		}
+		section[key] = str
+	}
+	return section, nil
+}
+
+// stringValue converts a decoded value into its INI string form
+func stringValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			str, err := stringValue(item)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, str)
+		}
+		return strings.Join(items, ","), nil
+	case map[string]interface{}:
+		return "", fmt.Errorf("can't be a nested table")
+	default:
+		return fmt.Sprint(v), nil
+	}
+}
+
+// applyEnvironment applies OASIS_API__<SECTION>__<KEY> variables to the main
+// configuration and OASIS_API_NODES__ and OASIS_API_SENTRIES__ variables to
+// the nodes and sentries, sections that don't exist yet are created
+func applyEnvironment(environ []string, main, nodes,
+	sentries map[string]map[string]string) {
+
+	for _, variable := range environ {
+		name, value, _ := strings.Cut(variable, "=")
+
+		var conf map[string]map[string]string
+		var rest string
+		switch {
+		case strings.HasPrefix(name, envNodesPrefix):
+			conf, rest = nodes, strings.TrimPrefix(name, envNodesPrefix)
+		case strings.HasPrefix(name, envSentriesPrefix):
+			conf, rest = sentries, strings.TrimPrefix(name,
+				envSentriesPrefix)
+		case strings.HasPrefix(name, envMainPrefix):
+			conf, rest = main, strings.TrimPrefix(name, envMainPrefix)
+		default:
+			continue
+		}
+
+		section, key, ok := strings.Cut(strings.ToLower(rest), "__")
+		if !ok || section == "" || key == "" {
+			lgr.Warning.Println("Ignoring environment variable ", name,
+				" that isn't <PREFIX><SECTION>__<KEY>")
+			continue
+		}
+		setValue(conf, section, key, value)
+	}
+}
+
+// setValue sets a key of a section, creating the section if needed
+func setValue(conf map[string]map[string]string, section, key,
+	value string) {
+
+	if conf[section] == nil {
+		conf[section] = map[string]string{}
+	}
+	conf[section][key] = value
+}
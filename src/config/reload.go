@@ -0,0 +1,273 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	lgr "github.com/SimplyVC/oasis_api_server/src/logger"
+)
+
+// Triggers of a configuration reload
+const (
+	TriggerFile   = "file"
+	TriggerSignal = "signal"
+	TriggerAdmin  = "admin"
+)
+
+// reloadDelay lets editors finish writing a file, which often takes several
+// writes or a rename, before it is read again
+const reloadDelay = 500 * time.Millisecond
+
+var (
+	reloadMutex   sync.Mutex
+	subscribers   []*func(previous, next *Config)
+	lastReload    *ReloadAttempt
+	stopWatching  context.CancelFunc
+	watchingGroup sync.WaitGroup
+)
+
+// ReloadAttempt describes the outcome of a configuration reload
+type ReloadAttempt struct {
+	Trigger      string    `json:"trigger"`
+	Time         time.Time `json:"time"`
+	Success      bool      `json:"success"`
+	Error        string    `json:"error,omitempty"`
+	AddedNodes   []string  `json:"added_nodes,omitempty"`
+	RemovedNodes []string  `json:"removed_nodes,omitempty"`
+	ChangedNodes []string  `json:"changed_nodes,omitempty"`
+}
+
+// ReloadStatus describes whether the configuration files are watched, when
+// the configuration in use was loaded and how the last reload went
+type ReloadStatus struct {
+	Watching   bool           `json:"watching"`
+	Files      []string       `json:"files"`
+	LoadedAt   time.Time      `json:"loaded_at"`
+	LastReload *ReloadAttempt `json:"last_reload"`
+}
+
+// OnReload registers a function called with the previous and the new
+// configuration after every successful reload. It returns a function
+// unregistering it again, which can't be called from a subscriber.
+func OnReload(fn func(previous, next *Config)) (remove func()) {
+	subscriber := &fn
+	reloadMutex.Lock()
+	defer reloadMutex.Unlock()
+	subscribers = append(subscribers, subscriber)
+	return func() {
+		reloadMutex.Lock()
+		defer reloadMutex.Unlock()
+		for i, other := range subscribers {
+			if other == subscriber {
+				subscribers = append(subscribers[:i], subscribers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// GetReloadStatus returns the reload status of the configuration
+func GetReloadStatus() ReloadStatus {
+	reloadMutex.Lock()
+	watching := stopWatching != nil
+	last := lastReload
+	reloadMutex.Unlock()
+
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return ReloadStatus{
+		Watching:   watching,
+		Files:      files(),
+		LoadedAt:   loadedAt,
+		LastReload: last,
+	}
+}
+
+// Reload loads the configuration again and swaps it in if it is valid, an
+// invalid configuration is reported and the current one is kept. Added and
+// removed nodes take effect through the subscribers, as there is no pool of
+// node connections or node health checker to update: requests connect to the
+// node they name when they are served.
+func Reload(trigger string) (*Config, error) {
+	reloadMutex.Lock()
+	defer reloadMutex.Unlock()
+
+	previous := Get()
+	attempt := &ReloadAttempt{Trigger: trigger, Time: time.Now()}
+	lastReload = attempt
+
+	next, err := Load()
+	if err != nil {
+		attempt.Error = err.Error()
+		lgr.Error.Println("Reload of configuration triggered by ", trigger,
+			" has failed, keeping the current configuration : ", err)
+		return nil, err
+	}
+
+	attempt.Success = true
+	attempt.AddedNodes, attempt.RemovedNodes, attempt.ChangedNodes =
+		DiffNodes(previous, next)
+	lgr.Info.Printf("Reloaded configuration triggered by %s, nodes added "+
+		"%v, removed %v, changed %v", trigger, attempt.AddedNodes,
+		attempt.RemovedNodes, attempt.ChangedNodes)
+	if previous != nil && (previous.APIServer != next.APIServer ||
+		!reflect.DeepEqual(previous.Listeners, next.Listeners)) {
+		lgr.Warning.Println("Change of api_server port, TLS settings or " +
+			"listeners only applies after a restart")
+	}
+
+	for _, fn := range subscribers {
+		(*fn)(previous, next)
+	}
+	return next, nil
+}
+
+// DiffNodes returns the names of the nodes added, removed and changed
+// between two configurations
+func DiffNodes(previous, next *Config) (added, removed, changed []string) {
+	// Sections are left out as they are numbered again on every edit
+	before := map[string]Node{}
+	if previous != nil {
+		for _, node := range previous.Nodes {
+			node.Section = ""
+			before[node.Name] = node
+		}
+	}
+	for _, node := range next.Nodes {
+		node.Section = ""
+		old, ok := before[node.Name]
+		switch {
+		case !ok:
+			added = append(added, node.Name)
+		case old != node:
+			changed = append(changed, node.Name)
+		}
+		delete(before, node.Name)
+	}
+	for name := range before {
+		removed = append(removed, name)
+	}
+	sort.Strings(removed)
+	return added, removed, changed
+}
+
+// SectionsChanged reports whether any of the given sections of the main
+// configuration differ between two configurations
+func SectionsChanged(previous, next *Config, sections ...string) bool {
+	if previous == nil {
+		return true
+	}
+	for _, section := range sections {
+		if !reflect.DeepEqual(previous.Sections[section],
+			next.Sections[section]) {
+			return true
+		}
+	}
+	return false
+}
+
+// StartWatching reloads the configuration whenever one of its files is
+// written or the process receives SIGHUP, until StopWatching is called
+func StartWatching() error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+
+	// Directories are watched rather than the files themselves, as editors
+	// and configuration management tools often replace files by renaming
+	watched := map[string]bool{}
+	for _, file := range files() {
+		if err := watcher.Add(filepath.Dir(file)); err != nil {
+			watcher.Close()
+			return err
+		}
+		watched[file] = true
+	}
+
+	signals := make(chan os.Signal, 1)
+	notifyReload(signals)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	reloadMutex.Lock()
+	stopWatching = cancel
+	reloadMutex.Unlock()
+
+	watchingGroup.Add(1)
+	go func() {
+		defer watchingGroup.Done()
+		defer watcher.Close()
+		defer stopNotifyReload(signals)
+		watch(ctx, watcher, watched, signals)
+	}()
+	lgr.Info.Println("Watching configuration files ", files(),
+		" for changes")
+	return nil
+}
+
+// StopWatching stops watching the configuration files and signals
+func StopWatching() {
+	reloadMutex.Lock()
+	if stopWatching != nil {
+		stopWatching()
+	}
+	stopWatching = nil
+	reloadMutex.Unlock()
+
+	watchingGroup.Wait()
+}
+
+// watch reloads the configuration once its files stopped changing for
+// reloadDelay, or straight away on a signal
+func watch(ctx context.Context, watcher *fsnotify.Watcher,
+	watched map[string]bool, signals <-chan os.Signal) {
+
+	timer := time.NewTimer(reloadDelay)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if watched[filepath.Clean(event.Name)] {
+				timer.Reset(reloadDelay)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			lgr.Error.Println("Watching of configuration files has "+
+				"failed : ", err)
+		case <-timer.C:
+			Reload(TriggerFile)
+		case <-signals:
+			Reload(TriggerSignal)
+		}
+	}
+}
+
+// files returns the absolute paths of the configuration files in use
+func files() []string {
+	paths := []string{configFile}
+	if configFile == "" {
+		paths = []string{mainConfigFile, nodesFile, sentryFile}
+	}
+	for i, path := range paths {
+		if abs, err := filepath.Abs(path); err == nil {
+			paths[i] = abs
+		}
+	}
+	return paths
+}
@@ -0,0 +1,20 @@
+//go:build !windows
+
+package config
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// notifyReload relays SIGHUP, the conventional signal asking a server to
+// reload its configuration
+func notifyReload(signals chan<- os.Signal) {
+	signal.Notify(signals, syscall.SIGHUP)
+}
+
+// stopNotifyReload stops relaying SIGHUP
+func stopNotifyReload(signals chan<- os.Signal) {
+	signal.Stop(signals)
+}
@@ -0,0 +1,10 @@
+package config
+
+import "os"
+
+// notifyReload does nothing as Windows has no SIGHUP, changes to the
+// configuration files are still picked up by watching them
+func notifyReload(signals chan<- os.Signal) {}
+
+// stopNotifyReload does nothing as Windows has no SIGHUP
+func stopNotifyReload(signals chan<- os.Signal) {}
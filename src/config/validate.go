@@ -0,0 +1,538 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// minTokenLength is the minimum length of the admin token, so that it
+// can't be guessed
+const minTokenLength = 16
+
+// defaultShutdownTimeout is how long requests in flight are given to finish
+// on shutdown when no shutdown_timeout is configured
+const defaultShutdownTimeout = 30 * time.Second
+
+// Config is the typed and validated configuration of the API Server
+type Config struct {
+	APIServer APIServer
+	Listeners []Listener
+	Admin     Admin
+	Nodes     []Node
+	Sentries  []Sentry
+
+	// Sections holds every section of the main configuration, including
+	// those read by the sampler, ledger and incident monitor
+	Sections map[string]map[string]string
+}
+
+// APIServer holds the [api_server] section of the main configuration, the
+// API is served over HTTPS when a certificate and key are set
+type APIServer struct {
+	Port            int
+	MetricsURL      string
+	TLSCertPath     string
+	TLSKeyPath      string
+	TLSMinVersion   uint16
+	TLSClientCAPath string
+	ShutdownTimeout time.Duration
+}
+
+// Listener is an address the API is served on, either a host:port address
+// or a unix socket. When any listener is an admin listener, the admin API
+// is only served on admin listeners and admin listeners only serve it.
+type Listener struct {
+	Section    string
+	Address    string
+	SocketMode os.FileMode
+	Admin      bool
+}
+
+// Admin holds the [admin] section of the main configuration, the admin API
+// is disabled while no token is set
+type Admin struct {
+	Token string
+}
+
+// Node is a node the API Server connects to through its internal socket,
+// or over TCP with TLS for nodes on other machines
+type Node struct {
+	Section           string `json:"-"`
+	Name              string `json:"node_name"`
+	ISocketPath       string `json:"isocket_path"`
+	PrometheusURL     string `json:"prometheus_url,omitempty"`
+	TLSCertPath       string `json:"tls_cert_path,omitempty"`
+	TLSClientCertPath string `json:"tls_client_cert_path,omitempty"`
+	TLSClientKeyPath  string `json:"tls_client_key_path,omitempty"`
+	TLSServerName     string `json:"tls_server_name,omitempty"`
+}
+
+// Sentry is a sentry node whose addresses are queried over TLS
+type Sentry struct {
+	Section string `json:"-"`
+	Name    string `json:"node_name"`
+	ExtURL  string `json:"ext_url"`
+	TLSPath string `json:"tls_path"`
+}
+
+// validators check the sections of the main configuration read by other
+// packages, see AddValidator
+var (
+	validatorsMutex sync.Mutex
+	validators      []*validator
+)
+
+// validator checks the main configuration and returns its problems
+type validator func(main map[string]map[string]string) []string
+
+// ValidationError lists every problem found in a configuration so that
+// they can all be fixed at once instead of one restart at a time
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  - " +
+		strings.Join(e.Problems, "\n  - ")
+}
+
+// Node returns the node with the given name
+func (c *Config) Node(name string) (Node, bool) {
+	for _, node := range c.Nodes {
+		if node.Name == name {
+			return node, true
+		}
+	}
+	return Node{}, false
+}
+
+// New builds a typed configuration out of the main, nodes and sentry
+// sections and validates it, returning a ValidationError if it is invalid
+func New(main, nodes, sentries map[string]map[string]string) (*Config,
+	error) {
+
+	var problems []string
+	cfg := &Config{Sections: main}
+
+	// The port is only required when no listeners are configured, as the
+	// API is then served on it
+	listeners, listenerProblems := checkListeners(main)
+	problems = append(problems, listenerProblems...)
+
+	apiServer := main["api_server"]
+	port, err := strconv.Atoi(apiServer["port"])
+	if (err != nil || port < 1 || port > 65535) &&
+		(len(listeners) == 0 || apiServer["port"] != "") {
+		problems = append(problems, fmt.Sprintf("[api_server] port needs "+
+			"to be a number between 1 and 65535, got %q", apiServer["port"]))
+	}
+	if len(listeners) == 0 {
+		listeners = []Listener{{
+			Section: "api_server",
+			Address: ":" + strconv.Itoa(port),
+		}}
+	}
+	cfg.Listeners = listeners
+	cfg.APIServer = APIServer{
+		Port:            port,
+		MetricsURL:      apiServer["metrics_url"],
+		TLSCertPath:     apiServer["tls_cert_path"],
+		TLSKeyPath:      apiServer["tls_key_path"],
+		TLSMinVersion:   tls.VersionTLS12,
+		TLSClientCAPath: apiServer["tls_client_ca_path"],
+		ShutdownTimeout: defaultShutdownTimeout,
+	}
+	if cfg.APIServer.MetricsURL != "" {
+		if err := checkURL(cfg.APIServer.MetricsURL); err != nil {
+			problems = append(problems, "[api_server] metrics_url "+
+				err.Error())
+		}
+	}
+
+	if timeout := apiServer["shutdown_timeout"]; timeout != "" {
+		seconds, err := strconv.ParseUint(timeout, 10, 32)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("[api_server] "+
+				"shutdown_timeout needs to be a number of seconds, got %q",
+				timeout))
+		}
+		cfg.APIServer.ShutdownTimeout = time.Duration(seconds) * time.Second
+	}
+
+	problems = append(problems, checkServerTLS(&cfg.APIServer,
+		apiServer["tls_min_version"])...)
+
+	cfg.Admin = Admin{Token: main["admin"]["token"]}
+	if cfg.Admin.Token != "" && len(cfg.Admin.Token) < minTokenLength {
+		problems = append(problems, fmt.Sprintf("[admin] token needs to be "+
+			"at least %d characters long", minTokenLength))
+	}
+	if cfg.Admin.Token != "" && !hasAdminListener(listeners) {
+		problems = append(problems, "[admin] token needs a listener with "+
+			"admin = true to serve the admin API on")
+	}
+
+	// Sections are sorted so that nodes keep the order of the file and
+	// problems are reported in the same order on every start
+	nodeNames := map[string]string{}
+	for _, section := range sortedSections(nodes) {
+		values := nodes[section]
+		node := Node{
+			Section:           section,
+			Name:              values["node_name"],
+			ISocketPath:       values["isocket_path"],
+			PrometheusURL:     values["prometheus_url"],
+			TLSCertPath:       values["tls_cert_path"],
+			TLSClientCertPath: values["tls_client_cert_path"],
+			TLSClientKeyPath:  values["tls_client_key_path"],
+			TLSServerName:     values["tls_server_name"],
+		}
+
+		if node.Name == "" {
+			problems = append(problems, "[nodes."+section+"] node_name "+
+				"can't be empty")
+		} else if other, ok := nodeNames[node.Name]; ok {
+			problems = append(problems, fmt.Sprintf("[nodes.%s] node_name "+
+				"%q is already used by [nodes.%s]", section, node.Name,
+				other))
+		} else {
+			nodeNames[node.Name] = section
+		}
+
+		if err := checkSocket(node.ISocketPath); err != nil {
+			problems = append(problems, "[nodes."+section+"] "+
+				"isocket_path "+err.Error())
+		}
+		for _, problem := range checkNodeTLS(node) {
+			problems = append(problems, "[nodes."+section+"] "+problem)
+		}
+		if node.PrometheusURL != "" {
+			if err := checkURL(node.PrometheusURL); err != nil {
+				problems = append(problems, "[nodes."+section+"] "+
+					"prometheus_url "+err.Error())
+			}
+		}
+		cfg.Nodes = append(cfg.Nodes, node)
+	}
+	if len(cfg.Nodes) == 0 {
+		problems = append(problems, "at least one node needs to be "+
+			"configured")
+	}
+
+	sentryNames := map[string]string{}
+	for _, section := range sortedSections(sentries) {
+		values := sentries[section]
+		sentry := Sentry{
+			Section: section,
+			Name:    values["node_name"],
+			ExtURL:  values["ext_url"],
+			TLSPath: strings.TrimSpace(values["tls_path"]),
+		}
+
+		if sentry.Name == "" {
+			problems = append(problems, "[sentries."+section+"] node_name "+
+				"can't be empty")
+		} else if other, ok := sentryNames[sentry.Name]; ok {
+			problems = append(problems, fmt.Sprintf("[sentries.%s] "+
+				"node_name %q is already used by [sentries.%s]", section,
+				sentry.Name, other))
+		} else {
+			sentryNames[sentry.Name] = section
+		}
+
+		if _, _, err := net.SplitHostPort(sentry.ExtURL); err != nil {
+			problems = append(problems, fmt.Sprintf("[sentries.%s] "+
+				"ext_url needs to be a host:port address, got %q", section,
+				sentry.ExtURL))
+		}
+		if sentry.TLSPath == "" {
+			problems = append(problems, "[sentries."+section+"] tls_path "+
+				"can't be empty")
+		} else if err := checkCertificate(sentry.TLSPath); err != nil {
+			problems = append(problems, fmt.Sprintf("[sentries.%s] "+
+				"tls_path %q %v", section, sentry.TLSPath, err))
+		}
+		cfg.Sentries = append(cfg.Sentries, sentry)
+	}
+
+	// Sections of the features configured by their own packages are
+	// checked by them, in the order they were added
+	validatorsMutex.Lock()
+	for _, check := range validators {
+		problems = append(problems, (*check)(main)...)
+	}
+	validatorsMutex.Unlock()
+
+	if len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+	return cfg, nil
+}
+
+// AddValidator adds a check of the main configuration run by New, the
+// problems it returns make the configuration invalid. It returns a function
+// removing the check again. Checks need to be added before the configuration
+// is loaded.
+func AddValidator(check func(
+	main map[string]map[string]string) []string) (remove func()) {
+
+	added := (*validator)(&check)
+	validatorsMutex.Lock()
+	defer validatorsMutex.Unlock()
+	validators = append(validators, added)
+	return func() {
+		validatorsMutex.Lock()
+		defer validatorsMutex.Unlock()
+		for i, other := range validators {
+			if other == added {
+				validators = append(validators[:i], validators[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// checkURL checks that a URL is an absolute http or https URL
+func checkURL(value string) error {
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") ||
+		u.Host == "" {
+		return fmt.Errorf("needs to be an http or https URL, got %q", value)
+	}
+	return nil
+}
+
+// checkSocket checks that an internal socket path is either a unix socket
+// or a host:port address
+func checkSocket(value string) error {
+	if value == "" {
+		return fmt.Errorf("can't be empty")
+	}
+	if path := strings.TrimPrefix(value, "unix:"); path != value {
+		if path == "" {
+			return fmt.Errorf("needs a path after unix:, got %q", value)
+		}
+		return nil
+	}
+	if _, _, err := net.SplitHostPort(value); err != nil {
+		return fmt.Errorf("needs to be unix:<path> or host:port, got %q",
+			value)
+	}
+	return nil
+}
+
+// defaultSocketMode lets the owner and group of the API use its sockets
+const defaultSocketMode = 0660
+
+// checkListeners builds the listeners configured in the listener_<index>
+// sections of the main configuration
+func checkListeners(main map[string]map[string]string) ([]Listener,
+	[]string) {
+
+	var listeners []Listener
+	var problems []string
+	addresses := map[string]string{}
+	for _, section := range sortedSections(main) {
+		if !strings.HasPrefix(section, "listener_") {
+			continue
+		}
+		values := main[section]
+		listener := Listener{
+			Section:    section,
+			Address:    values["address"],
+			SocketMode: defaultSocketMode,
+		}
+
+		if err := checkSocket(listener.Address); err != nil {
+			problems = append(problems, "["+section+"] address "+
+				err.Error())
+		} else if other, ok := addresses[listener.Address]; ok {
+			problems = append(problems, fmt.Sprintf("[%s] address %q is "+
+				"already used by [%s]", section, listener.Address, other))
+		}
+		addresses[listener.Address] = section
+
+		if mode := values["socket_mode"]; mode != "" {
+			value, err := strconv.ParseUint(mode, 8, 32)
+			if err != nil || value > 0777 {
+				problems = append(problems, fmt.Sprintf("[%s] socket_mode "+
+					"needs to be octal permissions such as 0660, got %q",
+					section, mode))
+			} else if !strings.HasPrefix(listener.Address, "unix:") {
+				problems = append(problems, "["+section+"] socket_mode "+
+					"is only used for unix sockets")
+			}
+			listener.SocketMode = os.FileMode(value)
+		}
+		if admin := values["admin"]; admin != "" {
+			value, err := strconv.ParseBool(admin)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("[%s] admin needs "+
+					"to be true or false, got %q", section, admin))
+			}
+			listener.Admin = value
+		}
+		listeners = append(listeners, listener)
+	}
+	return listeners, problems
+}
+
+// hasAdminListener checks whether one of the listeners serves the admin API
+func hasAdminListener(listeners []Listener) bool {
+	for _, listener := range listeners {
+		if listener.Admin {
+			return true
+		}
+	}
+	return false
+}
+
+// tlsVersions are the minimum TLS versions the API can be served with
+var tlsVersions = map[string]uint16{
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// checkServerTLS checks the TLS settings the API is served with and sets
+// the minimum TLS version
+func checkServerTLS(apiServer *APIServer, minVersion string) []string {
+	var problems []string
+	if minVersion != "" {
+		version, ok := tlsVersions[minVersion]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("[api_server] "+
+				"tls_min_version needs to be 1.2 or 1.3, got %q", minVersion))
+		}
+		apiServer.TLSMinVersion = version
+	}
+
+	if (apiServer.TLSCertPath == "") != (apiServer.TLSKeyPath == "") {
+		problems = append(problems, "[api_server] tls_cert_path and "+
+			"tls_key_path need to be set together")
+	}
+	if apiServer.TLSClientCAPath != "" && apiServer.TLSCertPath == "" {
+		problems = append(problems, "[api_server] tls_client_ca_path "+
+			"needs tls_cert_path and tls_key_path to be set")
+	}
+	files := []struct{ key, path string }{
+		{"tls_cert_path", apiServer.TLSCertPath},
+		{"tls_key_path", apiServer.TLSKeyPath},
+		{"tls_client_ca_path", apiServer.TLSClientCAPath},
+	}
+	for _, file := range files {
+		if file.path == "" {
+			continue
+		}
+		if _, err := os.Stat(file.path); err != nil {
+			problems = append(problems, fmt.Sprintf("[api_server] %s %q "+
+				"can't be read : %v", file.key, file.path, err))
+		}
+	}
+	return problems
+}
+
+// checkNodeTLS checks the TLS settings of a node, which are required to
+// reach a node on another machine over TCP and only used over TCP
+func checkNodeTLS(node Node) []string {
+	var problems []string
+	files := map[string]string{
+		"tls_cert_path":        node.TLSCertPath,
+		"tls_client_cert_path": node.TLSClientCertPath,
+		"tls_client_key_path":  node.TLSClientKeyPath,
+	}
+
+	if strings.HasPrefix(node.ISocketPath, "unix:") {
+		for _, key := range []string{"tls_cert_path",
+			"tls_client_cert_path", "tls_client_key_path"} {
+			if files[key] != "" {
+				problems = append(problems, key+" is only used for "+
+					"host:port addresses, not unix sockets")
+			}
+		}
+		return problems
+	}
+
+	host, _, err := net.SplitHostPort(node.ISocketPath)
+	if err != nil {
+		return nil
+	}
+	if node.TLSCertPath == "" && !isLoopback(host) {
+		problems = append(problems, fmt.Sprintf("tls_cert_path is "+
+			"required to connect to %s over TCP, only loopback addresses "+
+			"can be reached without TLS", node.ISocketPath))
+	}
+	if (node.TLSClientCertPath == "") != (node.TLSClientKeyPath == "") {
+		problems = append(problems, "tls_client_cert_path and "+
+			"tls_client_key_path need to be set together")
+	}
+	if node.TLSCertPath != "" {
+		if err := checkCertificate(node.TLSCertPath); err != nil {
+			problems = append(problems, fmt.Sprintf("tls_cert_path %q %v",
+				node.TLSCertPath, err))
+		}
+	}
+
+	// The client certificate and key are loaded the way they are when
+	// connecting, so that a node can't be configured with a pair that
+	// doesn't match
+	if node.TLSClientCertPath != "" && node.TLSClientKeyPath != "" {
+		if _, err := tls.LoadX509KeyPair(node.TLSClientCertPath,
+			node.TLSClientKeyPath); err != nil {
+			problems = append(problems, fmt.Sprintf("tls_client_cert_path "+
+				"%q and tls_client_key_path %q can't be loaded : %v",
+				node.TLSClientCertPath, node.TLSClientKeyPath, err))
+		}
+	}
+	return problems
+}
+
+// checkCertificate checks that a file holds PEM encoded certificates, which
+// are trusted when connecting to a node or sentry
+func checkCertificate(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("can't be read : %v", err)
+	}
+	if !x509.NewCertPool().AppendCertsFromPEM(data) {
+		return fmt.Errorf("doesn't hold a PEM encoded certificate")
+	}
+	return nil
+}
+
+// isLoopback reports whether a host only refers to the local machine
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// sortedSections returns the section names of a configuration ordered by
+// the number that follows their prefix, so that node_10 comes after node_9
+func sortedSections(conf map[string]map[string]string) []string {
+	sections := make([]string, 0, len(conf))
+	for section := range conf {
+		sections = append(sections, section)
+	}
+	sort.Slice(sections, func(i, j int) bool {
+		a, errA := strconv.Atoi(sections[i][strings.LastIndex(
+			sections[i], "_")+1:])
+		b, errB := strconv.Atoi(sections[j][strings.LastIndex(
+			sections[j], "_")+1:])
+		if errA == nil && errB == nil && a != b {
+			return a < b
+		}
+		return sections[i] < sections[j]
+	})
+	return sections
+}
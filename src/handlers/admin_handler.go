@@ -0,0 +1,294 @@
+package handlers
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/SimplyVC/oasis_api_server/src/config"
+	lgr "github.com/SimplyVC/oasis_api_server/src/logger"
+	"github.com/SimplyVC/oasis_api_server/src/responses"
+)
+
+// maxAdminBodySize limits the size of node and sentry definitions
+const maxAdminBodySize = 1 << 20
+
+// RequireAdminToken only lets requests through that carry the admin token
+// of the configuration as a bearer token. The admin API is disabled while
+// no token is configured.
+func RequireAdminToken(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := ""
+		if cfg := config.Get(); cfg != nil {
+			token = cfg.Admin.Token
+		}
+		if len(token) == 0 {
+			adminError(w, http.StatusForbidden, "Admin API is disabled, "+
+				"a token needs to be set in the admin section!")
+			return
+		}
+
+		given, ok := strings.CutPrefix(r.Header.Get("Authorization"),
+			"Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(given),
+			[]byte(token)) != 1 {
+			lgr.FromContext(r.Context()).Warning.Println("Request at ",
+				r.URL.Path, " from ", r.RemoteAddr, " has been refused, "+
+					"admin token is invalid")
+			adminError(w, http.StatusUnauthorized, "Admin token is missing "+
+				"or invalid!")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// GetReloadStatus returns whether the configuration files are watched,
+// when the configuration in use was loaded and the outcome of the last
+// reload.
+func GetReloadStatus(w http.ResponseWriter, r *http.Request) {
+
+	// Add header so that received knows they're receiving JSON
+	w.Header().Add("Content-Type", "application/json")
+
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
+	status := config.GetReloadStatus()
+	log.Debug.Println("Request at /api/admin/reload responding with " +
+		"configuration reload status!")
+	encodeResponse(ctx, w, responses.ReloadStatusResponse{
+		Status: status})
+}
+
+// GetAdminNodes returns the nodes that are configured.
+func GetAdminNodes(w http.ResponseWriter, r *http.Request) {
+
+	// Add header so that received knows they're receiving JSON
+	w.Header().Add("Content-Type", "application/json")
+
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
+	log.Debug.Println("Request at /api/admin/nodes responding with " +
+		"configured nodes!")
+	respondNodes(ctx, w, "list nodes", config.Get(), nil)
+}
+
+// AddAdminNode adds the node in the request body to the configuration.
+func AddAdminNode(w http.ResponseWriter, r *http.Request) {
+
+	// Add header so that received knows they're receiving JSON
+	w.Header().Add("Content-Type", "application/json")
+
+	ctx := r.Context()
+
+	var node config.Node
+	if !decodeDefinition(w, r, &node) {
+		return
+	}
+	cfg, err := config.AddNode(node)
+	respondNodes(ctx, w, "add node", cfg, err)
+}
+
+// UpdateAdminNode replaces the node with the requested name by the node in
+// the request body.
+func UpdateAdminNode(w http.ResponseWriter, r *http.Request) {
+
+	// Add header so that received knows they're receiving JSON
+	w.Header().Add("Content-Type", "application/json")
+
+	ctx := r.Context()
+
+	name := r.URL.Query().Get("name")
+	if len(name) == 0 {
+		adminError(w, http.StatusBadRequest, "Node name can't be empty!")
+		return
+	}
+	var node config.Node
+	if !decodeDefinition(w, r, &node) {
+		return
+	}
+	cfg, err := config.UpdateNode(name, node)
+	respondNodes(ctx, w, "update node", cfg, err)
+}
+
+// RemoveAdminNode removes the node with the requested name from the
+// configuration.
+func RemoveAdminNode(w http.ResponseWriter, r *http.Request) {
+
+	// Add header so that received knows they're receiving JSON
+	w.Header().Add("Content-Type", "application/json")
+
+	ctx := r.Context()
+
+	name := r.URL.Query().Get("name")
+	if len(name) == 0 {
+		adminError(w, http.StatusBadRequest, "Node name can't be empty!")
+		return
+	}
+	cfg, err := config.RemoveNode(name)
+	respondNodes(ctx, w, "remove node", cfg, err)
+}
+
+// GetAdminSentries returns the sentries that are configured.
+func GetAdminSentries(w http.ResponseWriter, r *http.Request) {
+
+	// Add header so that received knows they're receiving JSON
+	w.Header().Add("Content-Type", "application/json")
+
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
+	log.Debug.Println("Request at /api/admin/sentries responding with " +
+		"configured sentries!")
+	respondSentries(ctx, w, "list sentries", config.Get(), nil)
+}
+
+// AddAdminSentry adds the sentry in the request body to the configuration.
+func AddAdminSentry(w http.ResponseWriter, r *http.Request) {
+
+	// Add header so that received knows they're receiving JSON
+	w.Header().Add("Content-Type", "application/json")
+
+	ctx := r.Context()
+
+	var sentry config.Sentry
+	if !decodeDefinition(w, r, &sentry) {
+		return
+	}
+	cfg, err := config.AddSentry(sentry)
+	respondSentries(ctx, w, "add sentry", cfg, err)
+}
+
+// UpdateAdminSentry replaces the sentry with the requested name by the
+// sentry in the request body.
+func UpdateAdminSentry(w http.ResponseWriter, r *http.Request) {
+
+	// Add header so that received knows they're receiving JSON
+	w.Header().Add("Content-Type", "application/json")
+
+	ctx := r.Context()
+
+	name := r.URL.Query().Get("name")
+	if len(name) == 0 {
+		adminError(w, http.StatusBadRequest, "Sentry name can't be empty!")
+		return
+	}
+	var sentry config.Sentry
+	if !decodeDefinition(w, r, &sentry) {
+		return
+	}
+	cfg, err := config.UpdateSentry(name, sentry)
+	respondSentries(ctx, w, "update sentry", cfg, err)
+}
+
+// RemoveAdminSentry removes the sentry with the requested name from the
+// configuration.
+func RemoveAdminSentry(w http.ResponseWriter, r *http.Request) {
+
+	// Add header so that received knows they're receiving JSON
+	w.Header().Add("Content-Type", "application/json")
+
+	ctx := r.Context()
+
+	name := r.URL.Query().Get("name")
+	if len(name) == 0 {
+		adminError(w, http.StatusBadRequest, "Sentry name can't be empty!")
+		return
+	}
+	cfg, err := config.RemoveSentry(name)
+	respondSentries(ctx, w, "remove sentry", cfg, err)
+}
+
+// decodeDefinition decodes a node or sentry definition from the request
+// body, responding with an error if it isn't valid JSON
+func decodeDefinition(w http.ResponseWriter, r *http.Request,
+	definition interface{}) bool {
+
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body,
+		maxAdminBodySize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(definition); err != nil {
+		log.Error.Println("Request at ", r.URL.Path, " failed to decode "+
+			"definition : ", err)
+		adminError(w, http.StatusBadRequest, "Failed to decode definition, "+
+			"body needs to be a JSON object!")
+		return false
+	}
+	return true
+}
+
+// respondNodes responds with the configured nodes after an action, or with
+// the error that made it fail
+func respondNodes(ctx context.Context, w http.ResponseWriter, action string,
+	cfg *config.Config, err error) {
+
+	log := lgr.FromContext(ctx)
+
+	if err != nil {
+		log.Error.Println("Request at /api/admin/nodes failed to "+action+
+			" : ", err)
+		adminError(w, editStatus(err), "Failed to "+action+" : "+
+			err.Error())
+		return
+	}
+
+	nodes := []config.Node{}
+	if cfg != nil {
+		nodes = append(nodes, cfg.Nodes...)
+	}
+	encodeResponse(ctx, w, responses.AdminNodesResponse{Nodes: nodes})
+}
+
+// respondSentries responds with the configured sentries after an action, or
+// with the error that made it fail
+func respondSentries(ctx context.Context, w http.ResponseWriter,
+	action string, cfg *config.Config, err error) {
+
+	log := lgr.FromContext(ctx)
+
+	if err != nil {
+		log.Error.Println("Request at /api/admin/sentries failed to "+
+			action+" : ", err)
+		adminError(w, editStatus(err), "Failed to "+action+" : "+
+			err.Error())
+		return
+	}
+
+	sentries := []config.Sentry{}
+	if cfg != nil {
+		sentries = append(sentries, cfg.Sentries...)
+	}
+	encodeResponse(ctx, w, responses.AdminSentriesResponse{
+		Sentries: sentries})
+}
+
+// editStatus returns the status code matching a failed configuration edit
+func editStatus(err error) int {
+	var validationErr *config.ValidationError
+	switch {
+	case errors.Is(err, config.ErrNameNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, config.ErrNameExists):
+		return http.StatusConflict
+	case errors.As(err, &validationErr):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// adminError responds with an error and the status code matching it, as
+// provisioning systems using the admin API rely on status codes
+func adminError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(responses.ErrorResponse{Error: message})
+}
@@ -0,0 +1,175 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	conf "github.com/SimplyVC/oasis_api_server/src/config"
+	hdl "github.com/SimplyVC/oasis_api_server/src/handlers"
+)
+
+// adminToken is the admin token of the configuration loaded by adminSetup
+const adminToken = "0123456789abcdef"
+
+// adminSetup loads a configuration with an admin token from a temporary
+// file and restores the configuration files of the other tests afterwards
+func adminSetup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	os.WriteFile(path, []byte("api_server:\n  port: 3000\n"+
+		"listeners:\n  - address: 127.0.0.1:3000\n"+
+		"  - address: 127.0.0.1:3001\n    admin: true\n"+
+		"admin:\n  token: "+adminToken+"\n"+
+		"nodes:\n  - node_name: Oasis_Local\n"+
+		"    isocket_path: unix:/serverdir/node/internal.sock\n"), 0o600)
+	conf.SetFile(path)
+	if _, err := conf.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		conf.SetFile("")
+		conf.Load()
+	})
+}
+
+// adminRequest serves a request through the admin token check
+func adminRequest(method, path, token, body string,
+	handlerFunc http.HandlerFunc) *httptest.ResponseRecorder {
+
+	req, _ := http.NewRequest(method, path, strings.NewReader(body))
+	if len(token) != 0 {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	rr := httptest.NewRecorder()
+	hdl.RequireAdminToken(handlerFunc).ServeHTTP(rr, req)
+	return rr
+}
+
+func Test_RequireAdminToken_Disabled(t *testing.T) {
+	rr := adminRequest("GET", "/api/admin/nodes", adminToken, "",
+		hdl.GetAdminNodes)
+	if status := rr.Code; status != http.StatusForbidden {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusForbidden)
+	}
+}
+
+func Test_RequireAdminToken_InvalidToken(t *testing.T) {
+	adminSetup(t)
+
+	rr := adminRequest("GET", "/api/admin/nodes", "Unicorn", "",
+		hdl.GetAdminNodes)
+	if status := rr.Code; status != http.StatusUnauthorized {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusUnauthorized)
+	}
+
+	expected := `{"error":"Admin token is missing or invalid!"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetReloadStatus(t *testing.T) {
+	adminSetup(t)
+
+	rr := adminRequest("GET", "/api/admin/reload", adminToken, "",
+		hdl.GetReloadStatus)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `"watching":false`
+
+	if strings.Contains(strings.TrimSpace(rr.Body.String()), expected) != true {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_AddAdminNode(t *testing.T) {
+	adminSetup(t)
+
+	rr := adminRequest("POST", "/api/admin/nodes", adminToken,
+		`{"node_name":"Oasis_Local_1","isocket_path":"127.0.0.1:42261"}`,
+		hdl.AddAdminNode)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `"node_name":"Oasis_Local_1"`
+
+	if strings.Contains(strings.TrimSpace(rr.Body.String()), expected) != true {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_AddAdminNode_Duplicate(t *testing.T) {
+	adminSetup(t)
+
+	rr := adminRequest("POST", "/api/admin/nodes", adminToken,
+		`{"node_name":"Oasis_Local","isocket_path":"127.0.0.1:42261"}`,
+		hdl.AddAdminNode)
+	if status := rr.Code; status != http.StatusConflict {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusConflict)
+	}
+}
+
+func Test_AddAdminNode_InvalidBody(t *testing.T) {
+	adminSetup(t)
+
+	rr := adminRequest("POST", "/api/admin/nodes", adminToken,
+		`{"name":"Oasis_Local_1"}`, hdl.AddAdminNode)
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusBadRequest)
+	}
+
+	expected := `{"error":"Failed to decode definition, body needs to be a JSON object!"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_UpdateAdminNode_NotFound(t *testing.T) {
+	adminSetup(t)
+
+	rr := adminRequest("PUT", "/api/admin/nodes?name=Unicorn", adminToken,
+		`{"node_name":"Unicorn","isocket_path":"127.0.0.1:42261"}`,
+		hdl.UpdateAdminNode)
+	if status := rr.Code; status != http.StatusNotFound {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusNotFound)
+	}
+}
+
+func Test_RemoveAdminSentry_EmptyName(t *testing.T) {
+	adminSetup(t)
+
+	rr := adminRequest("DELETE", "/api/admin/sentries", adminToken, "",
+		hdl.RemoveAdminSentry)
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusBadRequest)
+	}
+
+	expected := `{"error":"Sentry name can't be empty!"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
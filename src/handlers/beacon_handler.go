@@ -0,0 +1,556 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	lgr "github.com/SimplyVC/oasis_api_server/src/logger"
+	"github.com/SimplyVC/oasis_api_server/src/responses"
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+)
+
+// epochTimeSampleBlocks is the number of recent blocks whose average block
+// time is used to extrapolate the start time of future epochs
+const epochTimeSampleBlocks = 1000
+
+// errEpochTooFar is returned for epochs whose start height or time can't be
+// represented, as they are too far in the future
+var errEpochTooFar = errors.New("epoch is too far in the future to estimate")
+
+// epochInterval returns the number of blocks per epoch of the beacon
+// backend in use
+func epochInterval(params *beacon.ConsensusParameters) (int64, error) {
+	switch {
+	case params.Backend == beacon.BackendInsecure &&
+		params.InsecureParameters != nil:
+		return params.InsecureParameters.Interval, nil
+	case params.Backend == beacon.BackendVRF && params.VRFParameters != nil:
+		return params.VRFParameters.Interval, nil
+	}
+	return 0, fmt.Errorf("unknown beacon backend %s", params.Backend)
+}
+
+// estimateEpochTime returns the start height and time of an epoch. Epochs
+// that already started are looked up, future epochs are extrapolated from
+// the average block time of recent blocks.
+func estimateEpochTime(ctx context.Context, co consensus.ClientBackend,
+	epoch beacon.EpochTime) (*responses.EpochTimeEstimate, error) {
+
+	status, err := co.GetStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+	params, err := co.Beacon().ConsensusParameters(ctx, status.LatestHeight)
+	if err != nil {
+		return nil, err
+	}
+	interval, err := epochInterval(params)
+	if err != nil {
+		return nil, err
+	}
+
+	estimate := &responses.EpochTimeEstimate{
+		Epoch:          epoch,
+		CurrentEpoch:   status.LatestEpoch,
+		BlocksPerEpoch: interval,
+	}
+
+	if epoch <= status.LatestEpoch {
+		height, err := co.Beacon().GetEpochBlock(ctx, epoch)
+		if err != nil {
+			return nil, err
+		}
+		block, err := co.GetBlock(ctx, height)
+		if err != nil {
+			return nil, err
+		}
+		estimate.StartHeight = height
+		estimate.StartTime = block.Time
+		return estimate, nil
+	}
+
+	currentStart, err := co.Beacon().GetEpochBlock(ctx, status.LatestEpoch)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sample as many recent blocks as the node still retains
+	oldest := status.LastRetainedHeight
+	if oldest < status.GenesisHeight {
+		oldest = status.GenesisHeight
+	}
+	sampled := status.LatestHeight - oldest
+	if sampled > epochTimeSampleBlocks {
+		sampled = epochTimeSampleBlocks
+	}
+	if sampled <= 0 {
+		return nil, fmt.Errorf("not enough blocks to estimate block time")
+	}
+	sampleBlock, err := co.GetBlock(ctx, status.LatestHeight-sampled)
+	if err != nil {
+		return nil, err
+	}
+	blockTime := status.LatestTime.Sub(sampleBlock.Time) /
+		time.Duration(sampled)
+
+	estimate.StartHeight, estimate.StartTime, err = extrapolateEpochStart(
+		currentStart, status.LatestHeight, status.LatestTime,
+		uint64(epoch-status.LatestEpoch), interval, blockTime)
+	if err != nil {
+		return nil, err
+	}
+	estimate.Estimated = true
+	estimate.AverageBlockTime = blockTime.Seconds()
+	estimate.SampledBlocks = sampled
+	return estimate, nil
+}
+
+// extrapolateEpochStart returns the start height and time of the epoch that
+// is distance epochs after the current one, which started at currentStart,
+// assuming blocks keep being produced every blockTime. errEpochTooFar is
+// returned when the height or time would overflow.
+func extrapolateEpochStart(currentStart, latestHeight int64,
+	latestTime time.Time, distance uint64, interval int64,
+	blockTime time.Duration) (int64, time.Time, error) {
+
+	if interval <= 0 {
+		return 0, time.Time{}, fmt.Errorf("invalid epoch interval %d",
+			interval)
+	}
+	if distance > uint64(math.MaxInt64-currentStart)/uint64(interval) {
+		return 0, time.Time{}, errEpochTooFar
+	}
+	height := currentStart + int64(distance)*interval
+
+	blocks := height - latestHeight
+	if blockTime > 0 && blocks > math.MaxInt64/int64(blockTime) {
+		return 0, time.Time{}, errEpochTooFar
+	}
+	return height, latestTime.Add(blockTime * time.Duration(blocks)), nil
+}
+
+// GetBaseEpoch returns the base epoch of the chain.
+func GetBaseEpoch(w http.ResponseWriter, r *http.Request) {
+
+	// Add header so that received knows they're receiving JSON
+	w.Header().Add("Content-Type", "application/json")
+
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
+	// Retrieving name of node from query request
+	nodeName := r.URL.Query().Get("name")
+	confirmation, socket := checkNodeName(ctx, nodeName)
+	if !confirmation {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Node name requested doesn't exist"})
+		return
+	}
+
+	// Attempt to load connection with consensus client
+	connection, co := loadConsensusClient(ctx, socket)
+
+	// Close connection once code underneath executes
+	defer closeConnection(connection)
+
+	// If null object was retrieved send response
+	if co == nil {
+
+		// Stop code here faild to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to establish connection using socket: " +
+				socket})
+		return
+	}
+
+	// Return base epoch from beacon backend
+	epoch, err := co.Beacon().GetBaseEpoch(ctx)
+	if err != nil {
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to retrieve Base Epoch!"})
+		log.Error.Println("Request at /api/beacon/baseepoch failed to "+
+			"retrieve Base Epoch : ", err)
+		return
+	}
+
+	// Respond with retrieved base epoch
+	log.Debug.Println("Request at /api/beacon/baseepoch responding with " +
+		"Base Epoch!")
+	encodeResponse(ctx, w, responses.EpochResponse{Ep: epoch})
+}
+
+// GetEpochBlock returns the height of the first block of an epoch.
+func GetEpochBlock(w http.ResponseWriter, r *http.Request) {
+
+	// Add header so that received knows they're receiving JSON
+	w.Header().Add("Content-Type", "application/json")
+
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
+	// Retrieving name of node from query request
+	nodeName := r.URL.Query().Get("name")
+	confirmation, socket := checkNodeName(ctx, nodeName)
+	if !confirmation {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Node name requested doesn't exist"})
+		return
+	}
+
+	// Retrieving epoch from query request, it is required
+	epoch, ok := checkEpoch(ctx, r.URL.Query().Get("epoch"))
+	if !ok || epoch == beacon.EpochInvalid {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Unexpected value found, epoch needs to be " +
+				"a string representing an unsigned int!"})
+		return
+	}
+
+	// Attempt to load connection with consensus client
+	connection, co := loadConsensusClient(ctx, socket)
+
+	// Close connection once code underneath executes
+	defer closeConnection(connection)
+
+	// If null object was retrieved send response
+	if co == nil {
+
+		// Stop code here faild to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to establish connection using socket: " +
+				socket})
+		return
+	}
+
+	// Return height at which epoch started
+	height, err := co.Beacon().GetEpochBlock(ctx, epoch)
+	if err != nil {
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to retrieve Epoch Block!"})
+		log.Error.Println("Request at /api/beacon/epochblock failed to "+
+			"retrieve Epoch Block : ", err)
+		return
+	}
+
+	// Respond with retrieved height
+	log.Debug.Println("Request at /api/beacon/epochblock responding with " +
+		"Epoch Block!")
+	encodeResponse(ctx, w, responses.HeightResponse{Height: height})
+}
+
+// GetFutureEpoch returns the future epoch scheduled at a block height, if
+// there is one.
+func GetFutureEpoch(w http.ResponseWriter, r *http.Request) {
+
+	// Add header so that received knows they're receiving JSON
+	w.Header().Add("Content-Type", "application/json")
+
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
+	// Retrieving name of node from query request
+	nodeName := r.URL.Query().Get("name")
+	confirmation, socket := checkNodeName(ctx, nodeName)
+	if !confirmation {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Node name requested doesn't exist"})
+		return
+	}
+
+	// Retrieving height from query request
+	recvHeight := r.URL.Query().Get("height")
+	height := checkHeight(ctx, recvHeight)
+	if height == -1 {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Unexpected value found, height needs to be " +
+				"a string representing an int!"})
+		return
+	}
+
+	// Attempt to load connection with consensus client
+	connection, co := loadConsensusClient(ctx, socket)
+
+	// Close connection once code underneath executes
+	defer closeConnection(connection)
+
+	// If null object was retrieved send response
+	if co == nil {
+
+		// Stop code here faild to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to establish connection using socket: " +
+				socket})
+		return
+	}
+
+	// Return future epoch scheduled at given block height
+	future, err := co.Beacon().GetFutureEpoch(ctx, height)
+	if err != nil {
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to retrieve Future Epoch!"})
+		log.Error.Println("Request at /api/beacon/futureepoch failed to "+
+			"retrieve Future Epoch : ", err)
+		return
+	}
+
+	// Respond with retrieved future epoch
+	log.Debug.Println("Request at /api/beacon/futureepoch responding with " +
+		"Future Epoch!")
+	encodeResponse(ctx, w, responses.FutureEpochResponse{
+		FutureEpoch: future})
+}
+
+// GetBeaconParameters returns the beacon consensus parameters at a block
+// height.
+func GetBeaconParameters(w http.ResponseWriter, r *http.Request) {
+
+	// Add header so that received knows they're receiving JSON
+	w.Header().Add("Content-Type", "application/json")
+
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
+	// Retrieving name of node from query request
+	nodeName := r.URL.Query().Get("name")
+	confirmation, socket := checkNodeName(ctx, nodeName)
+	if !confirmation {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Node name requested doesn't exist"})
+		return
+	}
+
+	// Retrieving height from query request
+	recvHeight := r.URL.Query().Get("height")
+	height := checkHeight(ctx, recvHeight)
+	if height == -1 {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Unexpected value found, height needs to be " +
+				"a string representing an int!"})
+		return
+	}
+
+	// Attempt to load connection with consensus client
+	connection, co := loadConsensusClient(ctx, socket)
+
+	// Close connection once code underneath executes
+	defer closeConnection(connection)
+
+	// If null object was retrieved send response
+	if co == nil {
+
+		// Stop code here faild to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to establish connection using socket: " +
+				socket})
+		return
+	}
+
+	// Return beacon parameters at given block height
+	params, err := co.Beacon().ConsensusParameters(ctx,
+		height)
+	if err != nil {
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to retrieve Beacon Parameters!"})
+		log.Error.Println("Request at /api/beacon/parameters failed to "+
+			"retrieve Beacon Parameters : ", err)
+		return
+	}
+
+	// Respond with retrieved beacon parameters
+	log.Debug.Println("Request at /api/beacon/parameters responding with " +
+		"Beacon Parameters!")
+	encodeResponse(ctx, w, responses.BeaconParametersResponse{
+		BeaconParameters: params})
+}
+
+// GetBeaconState returns the random beacon at a block height together with
+// its epoch and beacon backend.
+func GetBeaconState(w http.ResponseWriter, r *http.Request) {
+
+	// Add header so that received knows they're receiving JSON
+	w.Header().Add("Content-Type", "application/json")
+
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
+	// Retrieving name of node from query request
+	nodeName := r.URL.Query().Get("name")
+	confirmation, socket := checkNodeName(ctx, nodeName)
+	if !confirmation {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Node name requested doesn't exist"})
+		return
+	}
+
+	// Retrieving height from query request
+	recvHeight := r.URL.Query().Get("height")
+	height := checkHeight(ctx, recvHeight)
+	if height == -1 {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Unexpected value found, height needs to be " +
+				"a string representing an int!"})
+		return
+	}
+
+	// Attempt to load connection with consensus client
+	connection, co := loadConsensusClient(ctx, socket)
+
+	// Close connection once code underneath executes
+	defer closeConnection(connection)
+
+	// If null object was retrieved send response
+	if co == nil {
+
+		// Stop code here faild to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to establish connection using socket: " +
+				socket})
+		return
+	}
+
+	// Resolve latest height so that beacon and epoch refer to one block
+	if height == consensus.HeightLatest {
+		block, err := co.GetBlock(ctx, height)
+		if err != nil {
+			encodeResponse(ctx, w, responses.ErrorResponse{
+				Error: "Failed to retrieve Block!"})
+			log.Error.Println("Request at /api/beacon/state failed to "+
+				"retrieve Block : ", err)
+			return
+		}
+		height = block.Height
+	}
+
+	// Return beacon, epoch and beacon parameters at given block height
+	value, err := co.Beacon().GetBeacon(ctx, height)
+	if err != nil {
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to retrieve Beacon!"})
+		log.Error.Println("Request at /api/beacon/state failed to "+
+			"retrieve Beacon : ", err)
+		return
+	}
+	epoch, err := co.Beacon().GetEpoch(ctx, height)
+	if err != nil {
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to retrieve Epoch!"})
+		log.Error.Println("Request at /api/beacon/state failed to "+
+			"retrieve Epoch : ", err)
+		return
+	}
+	params, err := co.Beacon().ConsensusParameters(ctx,
+		height)
+	if err != nil {
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to retrieve Beacon Parameters!"})
+		log.Error.Println("Request at /api/beacon/state failed to "+
+			"retrieve Beacon Parameters : ", err)
+		return
+	}
+
+	// Respond with beacon state
+	log.Debug.Println("Request at /api/beacon/state responding with " +
+		"Beacon State!")
+	encodeResponse(ctx, w, responses.BeaconStateResponse{
+		BeaconState: &responses.BeaconState{
+			Height:  height,
+			Epoch:   epoch,
+			Backend: params.Backend,
+			Beacon:  value,
+		}})
+}
+
+// GetEpochTime returns the start height and time of an epoch, estimated
+// from recent block times when the epoch hasn't started yet.
+func GetEpochTime(w http.ResponseWriter, r *http.Request) {
+
+	// Add header so that received knows they're receiving JSON
+	w.Header().Add("Content-Type", "application/json")
+
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
+	// Retrieving name of node from query request
+	nodeName := r.URL.Query().Get("name")
+	confirmation, socket := checkNodeName(ctx, nodeName)
+	if !confirmation {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Node name requested doesn't exist"})
+		return
+	}
+
+	// Retrieving epoch from query request, it is required
+	epoch, ok := checkEpoch(ctx, r.URL.Query().Get("epoch"))
+	if !ok || epoch == beacon.EpochInvalid {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Unexpected value found, epoch needs to be " +
+				"a string representing an unsigned int!"})
+		return
+	}
+
+	// Attempt to load connection with consensus client
+	connection, co := loadConsensusClient(ctx, socket)
+
+	// Close connection once code underneath executes
+	defer closeConnection(connection)
+
+	// If null object was retrieved send response
+	if co == nil {
+
+		// Stop code here faild to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to establish connection using socket: " +
+				socket})
+		return
+	}
+
+	// Look up or estimate start of epoch
+	estimate, err := estimateEpochTime(ctx, co, epoch)
+	if errors.Is(err, errEpochTooFar) {
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Epoch is too far in the future to estimate!"})
+		log.Error.Println("Request at /api/beacon/epochtime failed to "+
+			"estimate Epoch Time : ", err)
+		return
+	}
+	if err != nil {
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to estimate Epoch Time!"})
+		log.Error.Println("Request at /api/beacon/epochtime failed to "+
+			"estimate Epoch Time : ", err)
+		return
+	}
+
+	// Respond with epoch time
+	log.Debug.Println("Request at /api/beacon/epochtime responding with " +
+		"Epoch Time!")
+	encodeResponse(ctx, w, responses.EpochTimeResponse{
+		EpochTime: estimate})
+}
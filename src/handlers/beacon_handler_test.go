@@ -0,0 +1,474 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	hdl "github.com/SimplyVC/oasis_api_server/src/handlers"
+	"github.com/SimplyVC/oasis_api_server/src/responses"
+	beacon_api "github.com/oasisprotocol/oasis-core/go/beacon/api"
+)
+
+func Test_GetBaseEpoch_BadNode(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/beacon/baseepoch", nil)
+	q := req.URL.Query()
+	q.Add("name", "Unicorn")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetBaseEpoch)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Node name requested doesn't exist"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetBaseEpoch(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/beacon/baseepoch", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetBaseEpoch)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := "result"
+
+	response := &responses.EpochResponse{
+		Ep: 0,
+	}
+
+	err := json.Unmarshal([]byte(rr.Body.String()), response)
+	if err != nil {
+		t.Errorf("Failed to unmarshall data")
+	}
+
+	if strings.Contains(strings.TrimSpace(rr.Body.String()), expected) != true {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			strings.TrimSpace(rr.Body.String()), expected)
+	}
+}
+
+func Test_GetEpochBlock_BadNode(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/beacon/epochblock", nil)
+	q := req.URL.Query()
+	q.Add("name", "Unicorn")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetEpochBlock)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Node name requested doesn't exist"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetEpochBlock_InvalidEpoch(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/beacon/epochblock", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	q.Add("epoch", "Unicorn")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetEpochBlock)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Unexpected value found, epoch needs to be a string representing an unsigned int!"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetEpochBlock(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/beacon/epochblock", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	q.Add("epoch", "1")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetEpochBlock)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := "result"
+
+	response := &responses.HeightResponse{
+		Height: 0,
+	}
+
+	err := json.Unmarshal([]byte(rr.Body.String()), response)
+	if err != nil {
+		t.Errorf("Failed to unmarshall data")
+	}
+
+	if strings.Contains(strings.TrimSpace(rr.Body.String()), expected) != true {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			strings.TrimSpace(rr.Body.String()), expected)
+	}
+}
+
+func Test_GetFutureEpoch_BadNode(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/beacon/futureepoch", nil)
+	q := req.URL.Query()
+	q.Add("name", "Unicorn")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetFutureEpoch)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Node name requested doesn't exist"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetFutureEpoch_InvalidHeight(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/beacon/futureepoch", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	q.Add("height", "Unicorn")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetFutureEpoch)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Unexpected value found, height needs to be a string representing an int!"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetFutureEpoch(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/beacon/futureepoch", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetFutureEpoch)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := "result"
+
+	response := &responses.FutureEpochResponse{
+		FutureEpoch: &beacon_api.EpochTimeState{},
+	}
+
+	err := json.Unmarshal([]byte(rr.Body.String()), response)
+	if err != nil {
+		t.Errorf("Failed to unmarshall data")
+	}
+
+	if strings.Contains(strings.TrimSpace(rr.Body.String()), expected) != true {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			strings.TrimSpace(rr.Body.String()), expected)
+	}
+}
+
+func Test_GetBeaconParameters_BadNode(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/beacon/parameters", nil)
+	q := req.URL.Query()
+	q.Add("name", "Unicorn")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetBeaconParameters)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Node name requested doesn't exist"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetBeaconParameters_InvalidHeight(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/beacon/parameters", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	q.Add("height", "Unicorn")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetBeaconParameters)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Unexpected value found, height needs to be a string representing an int!"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetBeaconParameters(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/beacon/parameters", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetBeaconParameters)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := "result"
+
+	response := &responses.BeaconParametersResponse{
+		BeaconParameters: &beacon_api.ConsensusParameters{},
+	}
+
+	err := json.Unmarshal([]byte(rr.Body.String()), response)
+	if err != nil {
+		t.Errorf("Failed to unmarshall data")
+	}
+
+	if strings.Contains(strings.TrimSpace(rr.Body.String()), expected) != true {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			strings.TrimSpace(rr.Body.String()), expected)
+	}
+}
+
+func Test_GetBeaconState_BadNode(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/beacon/state", nil)
+	q := req.URL.Query()
+	q.Add("name", "Unicorn")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetBeaconState)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Node name requested doesn't exist"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetBeaconState_InvalidHeight(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/beacon/state", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	q.Add("height", "Unicorn")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetBeaconState)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Unexpected value found, height needs to be a string representing an int!"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetBeaconState(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/beacon/state", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetBeaconState)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := "result"
+
+	response := &responses.BeaconStateResponse{
+		BeaconState: &responses.BeaconState{},
+	}
+
+	err := json.Unmarshal([]byte(rr.Body.String()), response)
+	if err != nil {
+		t.Errorf("Failed to unmarshall data")
+	}
+
+	if strings.Contains(strings.TrimSpace(rr.Body.String()), expected) != true {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			strings.TrimSpace(rr.Body.String()), expected)
+	}
+}
+
+func Test_GetEpochTime_BadNode(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/beacon/epochtime", nil)
+	q := req.URL.Query()
+	q.Add("name", "Unicorn")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetEpochTime)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Node name requested doesn't exist"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetEpochTime_InvalidEpoch(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/beacon/epochtime", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	q.Add("epoch", "Unicorn")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetEpochTime)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Unexpected value found, epoch needs to be a string representing an unsigned int!"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetEpochTime(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/beacon/epochtime", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	q.Add("epoch", "1")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetEpochTime)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := "result"
+
+	response := &responses.EpochTimeResponse{
+		EpochTime: &responses.EpochTimeEstimate{},
+	}
+
+	err := json.Unmarshal([]byte(rr.Body.String()), response)
+	if err != nil {
+		t.Errorf("Failed to unmarshall data")
+	}
+
+	if strings.Contains(strings.TrimSpace(rr.Body.String()), expected) != true {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			strings.TrimSpace(rr.Body.String()), expected)
+	}
+}
+
+func Test_ExtrapolateEpochStart(t *testing.T) {
+	latest := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Epochs of 600 blocks, the current one started 100 blocks ago
+	height, start, err := hdl.ExtrapolateEpochStart(1000, 1100, latest, 2,
+		600, 6*time.Second)
+	if err != nil || height != 2200 ||
+		!start.Equal(latest.Add(1100*6*time.Second)) {
+		t.Errorf("Unexpected estimate %d %v %v", height, start, err)
+	}
+
+	// Epochs whose height or time can't be represented are refused
+	for _, distance := range []uint64{math.MaxUint64 - 1,
+		math.MaxInt64 / 600, 1 << 40} {
+		_, _, err := hdl.ExtrapolateEpochStart(1000, 1100, latest,
+			distance, 600, 6*time.Second)
+		if !errors.Is(err, hdl.ErrEpochTooFar) {
+			t.Errorf("Failed to refuse epoch distance %d got %v",
+				distance, err)
+		}
+	}
+}
@@ -2,7 +2,6 @@ package handlers
 
 import (
 	"context"
-	"encoding/json"
 	"net/http"
 
 	"google.golang.org/grpc"
@@ -18,13 +17,17 @@ import (
 )
 
 // loadConsensusClient loads consensus client and returns it
-func loadConsensusClient(socket string) (*grpc.ClientConn,
-	consensus.ClientBackend) {
+func loadConsensusClient(ctx context.Context, socket string) (
+	*grpc.ClientConn, consensus.ClientBackend) {
+
+	log := lgr.FromContext(ctx)
 
 	// Attempt to load connection with consensus client
+	span := startConnect(ctx, "consensus", socket)
 	connection, consensusClient, err := rpc.ConsensusClient(socket)
+	endConnect(ctx, span, connection, err)
 	if err != nil {
-		lgr.Error.Println("Failed to establish connection to consensus"+
+		log.Error.Println("Failed to establish connection to consensus"+
 			" client : ", err)
 		return nil, nil
 	}
@@ -38,60 +41,63 @@ func GetConsensusStateToGenesis(w http.ResponseWriter, r *http.Request) {
 	// Add header so that received knows they're receiving JSON
 	w.Header().Add("Content-Type", "application/json")
 
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
 	// Retrieving name of node from query request
 	nodeName := r.URL.Query().Get("name")
-	confirmation, socket := checkNodeName(nodeName)
+	confirmation, socket := checkNodeName(ctx, nodeName)
 	if !confirmation {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Node name requested doesn't exist"})
 		return
 	}
 
 	// Retrieve height from query
 	recvHeight := r.URL.Query().Get("height")
-	height := checkHeight(recvHeight)
+	height := checkHeight(ctx, recvHeight)
 	if height == -1 {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Unexpected value found, height needs to be " +
 				"a string representing an int!"})
 		return
 	}
 
 	// Attempt to load connection with consensus client
-	connection, co := loadConsensusClient(socket)
+	connection, co := loadConsensusClient(ctx, socket)
 
 	// Close connection once code underneath executes
-	defer connection.Close()
+	defer closeConnection(connection)
 
 	// If null object was retrieved send response
 	if co == nil {
 
 		// Stop code here faild to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to establish connection using socket: " +
 				socket})
 		return
 	}
 
 	// Retrieving genesis state of consensus object at specified height
-	consensusGenesis, err := co.StateToGenesis(context.Background(), height)
+	consensusGenesis, err := co.StateToGenesis(ctx, height)
 	if err != nil {
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to get Genesis file of Block!"})
 
-		lgr.Error.Println("Request at /api/consensus/genesis failed "+
+		log.Error.Println("Request at /api/consensus/genesis failed "+
 			"to retrieve genesis file : ", err)
 		return
 	}
 
 	// Responding with consensus genesis state object, retrieved above.
-	lgr.Info.Println("Request at /api/consensus/genesis responding with" +
+	log.Debug.Println("Request at /api/consensus/genesis responding with" +
 		" genesis file!")
-	json.NewEncoder(w).Encode(responses.ConsensusGenesisResponse{
+	encodeResponse(ctx, w, responses.ConsensusGenesisResponse{
 		GenJSON: consensusGenesis})
 }
 
@@ -101,39 +107,42 @@ func GetEpoch(w http.ResponseWriter, r *http.Request) {
 	// Add header so that received knows they're receiving JSON
 	w.Header().Add("Content-Type", "application/json")
 
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
 	// Retrieving name of node from query request
 	nodeName := r.URL.Query().Get("name")
-	confirmation, socket := checkNodeName(nodeName)
+	confirmation, socket := checkNodeName(ctx, nodeName)
 	if !confirmation {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Node name requested doesn't exist"})
 		return
 	}
 
 	// Retrieve height from query
 	recvHeight := r.URL.Query().Get("height")
-	height := checkHeight(recvHeight)
+	height := checkHeight(ctx, recvHeight)
 	if height == -1 {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Unexpected value found, height needs to be " +
 				"a string representing an int!"})
 		return
 	}
 
 	// Attempt to load connection with consensus client
-	connection, co := loadConsensusClient(socket)
+	connection, co := loadConsensusClient(ctx, socket)
 
 	// Close connection once code underneath executes
-	defer connection.Close()
+	defer closeConnection(connection)
 
 	// If null object was retrieved send response
 	if co == nil {
 		// Stop code here faild to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to establish connection using socket: " +
 				socket})
 		return
@@ -143,20 +152,20 @@ func GetEpoch(w http.ResponseWriter, r *http.Request) {
 	beacon := co.Beacon()
 
 	// Return Epoch at current block height
-	epoch, err := beacon.GetEpoch(context.Background(), height)
+	epoch, err := beacon.GetEpoch(ctx, height)
 	if err != nil {
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to retrieve Epoch of Block!"})
 
-		lgr.Error.Println("Request at /api/consensus/epoch failed to"+
+		log.Error.Println("Request at /api/consensus/epoch failed to"+
 			" retrieve Epoch : ", err)
 		return
 	}
 
 	// Respond with retrieved epoch above
-	lgr.Info.Println("Request at /api/consensus/epoch responding" +
+	log.Debug.Println("Request at /api/consensus/epoch responding" +
 		" with an Epoch!")
-	json.NewEncoder(w).Encode(responses.EpochResponse{Ep: epoch})
+	encodeResponse(ctx, w, responses.EpochResponse{Ep: epoch})
 }
 
 // PingNode returns consensus block at specific height
@@ -165,15 +174,18 @@ func PingNode(w http.ResponseWriter, r *http.Request) {
 
 	// Add header so that received knows they're receiving JSON
 	w.Header().Add("Content-Type", "application/json")
-	lgr.Info.Println("Received request for /api/pingnode")
+
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+	log.Debug.Println("Received request for /api/pingnode")
 
 	// Retrieving name of node from query request
 	nodeName := r.URL.Query().Get("name")
-	confirmation, socket := checkNodeName(nodeName)
+	confirmation, socket := checkNodeName(ctx, nodeName)
 	if !confirmation {
-		lgr.Info.Println("Node name requested doesn't exist")
+		log.Info.Println("Node name requested doesn't exist")
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Node name requested doesn't exist"})
 		return
 	}
@@ -182,16 +194,16 @@ func PingNode(w http.ResponseWriter, r *http.Request) {
 	height := consensus.HeightLatest
 
 	// Attempt to load connection with consensus client
-	connection, co := loadConsensusClient(socket)
+	connection, co := loadConsensusClient(ctx, socket)
 
 	// Close connection once code underneath executes
-	defer connection.Close()
+	defer closeConnection(connection)
 
 	// If null object was retrieved send response
 	if co == nil {
 
 		// Stop code here faild to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to establish connection using socket: " +
 				socket})
 		return
@@ -199,20 +211,20 @@ func PingNode(w http.ResponseWriter, r *http.Request) {
 
 	// Making sure that the error being retrieved is nill, meaning that API
 	// is pingable
-	_, err := co.GetBlock(context.Background(), height)
+	_, err := co.GetBlock(ctx, height)
 	if err != nil {
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to ping node by retrieving highest " +
 				"block height!"})
 
-		lgr.Error.Println("Request at /api/pingnode failed to ping"+
+		log.Error.Println("Request at /api/pingnode failed to ping"+
 			" node : ", err)
 		return
 	}
 
 	// Responding with Pong response
-	lgr.Info.Println("Request at /api/pingnode responding with Pong!")
-	json.NewEncoder(w).Encode(responses.SuccessResponsed)
+	log.Debug.Println("Request at /api/pingnode responding with Pong!")
+	encodeResponse(ctx, w, responses.SuccessResponsed)
 }
 
 // GetBlock returns consensus block at specific height.
@@ -221,59 +233,62 @@ func GetBlock(w http.ResponseWriter, r *http.Request) {
 	// Add header so that received knows they're receiving JSON
 	w.Header().Add("Content-Type", "application/json")
 
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
 	// Retrieving name of node from query request
 	nodeName := r.URL.Query().Get("name")
-	confirmation, socket := checkNodeName(nodeName)
+	confirmation, socket := checkNodeName(ctx, nodeName)
 	if !confirmation {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Node name requested doesn't exist"})
 		return
 	}
 
 	// Retrieve height from query
 	recvHeight := r.URL.Query().Get("height")
-	height := checkHeight(recvHeight)
+	height := checkHeight(ctx, recvHeight)
 	if height == -1 {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Unexpected value found, height needs to be " +
 				"a string representing an int!"})
 		return
 	}
 
 	// Attempt to load connection with consensus client
-	connection, co := loadConsensusClient(socket)
+	connection, co := loadConsensusClient(ctx, socket)
 
 	// Close connection once code underneath executes
-	defer connection.Close()
+	defer closeConnection(connection)
 
 	// If null object was retrieved send response
 	if co == nil {
 
 		// Stop code here faild to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to establish connection using socket: " +
 				socket})
 		return
 	}
 
 	// Retrieve block at specific height from consensus client
-	blk, err := co.GetBlock(context.Background(), height)
+	blk, err := co.GetBlock(ctx, height)
 	if err != nil {
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to retrieve Block!"})
 
-		lgr.Error.Println("Request at /api/consensus/block failed "+
+		log.Error.Println("Request at /api/consensus/block failed "+
 			"to retrieve Block : ", err)
 		return
 	}
 
 	// Responding with retrieved block
-	lgr.Info.Println("Request at /api/consensus/block responding with Block!")
-	json.NewEncoder(w).Encode(responses.BlockResponse{Blk: blk})
+	log.Debug.Println("Request at /api/consensus/block responding with Block!")
+	encodeResponse(ctx, w, responses.BlockResponse{Blk: blk})
 }
 
 // GetStatus returns the current status overview.
@@ -282,47 +297,50 @@ func GetStatus(w http.ResponseWriter, r *http.Request) {
 	// Add header so that received knows they're receiving JSON
 	w.Header().Add("Content-Type", "application/json")
 
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
 	// Retrieving name of node from query request
 	nodeName := r.URL.Query().Get("name")
-	confirmation, socket := checkNodeName(nodeName)
+	confirmation, socket := checkNodeName(ctx, nodeName)
 	if !confirmation {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Node name requested doesn't exist"})
 		return
 	}
 
 	// Attempt to load connection with consensus client
-	connection, co := loadConsensusClient(socket)
+	connection, co := loadConsensusClient(ctx, socket)
 
 	// Close connection once code underneath executes
-	defer connection.Close()
+	defer closeConnection(connection)
 
 	// If null object was retrieved send response
 	if co == nil {
 
 		// Stop code here faild to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to establish connection using socket: " +
 				socket})
 		return
 	}
 
 	// Retrieve the current status overview
-	status, err := co.GetStatus(context.Background())
+	status, err := co.GetStatus(ctx)
 	if err != nil {
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to retrieve Status!"})
 
-		lgr.Error.Println("Request at /api/consensus/status failed "+
+		log.Error.Println("Request at /api/consensus/status failed "+
 			"to retrieve Status : ", err)
 		return
 	}
 
 	// Responding with retrieved block
-	lgr.Info.Println("Request at /api/consensus/status responding with Status!")
-	json.NewEncoder(w).Encode(responses.StatusResponse{Status: status})
+	log.Debug.Println("Request at /api/consensus/status responding with Status!")
+	encodeResponse(ctx, w, responses.StatusResponse{Status: status})
 }
 
 // GetGenesisDocument returns the original genesis document.
@@ -331,49 +349,52 @@ func GetGenesisDocument(w http.ResponseWriter, r *http.Request) {
 	// Add header so that received knows they're receiving JSON
 	w.Header().Add("Content-Type", "application/json")
 
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
 	// Retrieving name of node from query request
 	nodeName := r.URL.Query().Get("name")
-	confirmation, socket := checkNodeName(nodeName)
+	confirmation, socket := checkNodeName(ctx, nodeName)
 	if !confirmation {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Node name requested doesn't exist"})
 		return
 	}
 
 	// Attempt to load connection with consensus client
-	connection, co := loadConsensusClient(socket)
+	connection, co := loadConsensusClient(ctx, socket)
 
 	// Close connection once code underneath executes
-	defer connection.Close()
+	defer closeConnection(connection)
 
 	// If null object was retrieved send response
 	if co == nil {
 
 		// Stop code here faild to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to establish connection using socket: " +
 				socket})
 		return
 	}
 
 	// Retrieve the current status overview
-	genesisDocument, err := co.GetGenesisDocument(context.Background())
+	genesisDocument, err := co.GetGenesisDocument(ctx)
 	if err != nil {
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to retrieve Status!"})
 
-		lgr.Error.Println("Request at /api/consensus/genesisdocument failed "+
+		log.Error.Println("Request at /api/consensus/genesisdocument failed "+
 			"to retrieve Genesis Document : ", err)
 		return
 	}
 
 	// Responding with retrieved block
-	lgr.Info.Println(
+	log.Debug.Println(
 		"Request at /api/consensus/genesisdocument responding with Genesis " +
 			"Document!")
-	json.NewEncoder(w).Encode(responses.GenesisDocumentResponse{GenesisDocument: genesisDocument})
+	encodeResponse(ctx, w, responses.GenesisDocumentResponse{GenesisDocument: genesisDocument})
 }
 
 // GetBlockHeader returns consensus block header at specific height
@@ -382,52 +403,55 @@ func GetBlockHeader(w http.ResponseWriter, r *http.Request) {
 	// Add header so that received knows they're receiving JSON
 	w.Header().Add("Content-Type", "application/json")
 
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
 	// Retrieving name of node from query request
 	nodeName := r.URL.Query().Get("name")
-	confirmation, socket := checkNodeName(nodeName)
+	confirmation, socket := checkNodeName(ctx, nodeName)
 	if !confirmation {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Node name requested doesn't exist"})
 		return
 	}
 
 	// Retrieving height from query
 	recvHeight := r.URL.Query().Get("height")
-	height := checkHeight(recvHeight)
+	height := checkHeight(ctx, recvHeight)
 	if height == -1 {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Unexpected value found, height needs to be " +
 				"a string representing an int!"})
 		return
 	}
 
 	// Attempt to load connection with consensus client
-	connection, co := loadConsensusClient(socket)
+	connection, co := loadConsensusClient(ctx, socket)
 
 	// Close connection once code underneath executes
-	defer connection.Close()
+	defer closeConnection(connection)
 
 	// If null object was retrieved send response
 	if co == nil {
 
 		// Stop code here faild to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to establish connection using socket: " +
 				socket})
 		return
 	}
 
 	// Retriving Block at specific height using Consensus client
-	blk, err := co.GetBlock(context.Background(), height)
+	blk, err := co.GetBlock(ctx, height)
 	if err != nil {
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to retrieve Block!"})
 
-		lgr.Error.Println("Request at /api/consensus/blockheader "+
+		log.Error.Println("Request at /api/consensus/blockheader "+
 			"failed to retrieve Block : ", err)
 		return
 	}
@@ -435,18 +459,18 @@ func GetBlockHeader(w http.ResponseWriter, r *http.Request) {
 	// Creating BlockMeta object
 	var meta mint_api.BlockMeta
 	if err := cbor.Unmarshal(blk.Meta, &meta); err != nil {
-		lgr.Error.Println("Request at /api/consensus/blockheader "+
+		log.Error.Println("Request at /api/consensus/blockheader "+
 			"failed to Unmarshal Block Metadata : ", err)
 
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to Unmarshal Block Metadata!"})
 		return
 	}
 
 	// Responds with block header retrieved above
-	lgr.Info.Println("Request at /api/consensus/blockheader responding " +
+	log.Debug.Println("Request at /api/consensus/blockheader responding " +
 		"with Block Header!")
-	json.NewEncoder(w).Encode(responses.BlockHeaderResponse{
+	encodeResponse(ctx, w, responses.BlockHeaderResponse{
 		BlkHeader: meta.Header})
 }
 
@@ -456,52 +480,55 @@ func GetBlockLastCommit(w http.ResponseWriter, r *http.Request) {
 	// Add header so that received knows they're receiving JSON
 	w.Header().Add("Content-Type", "application/json")
 
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
 	// Retrieving name of node from query request
 	nodeName := r.URL.Query().Get("name")
-	confirmation, socket := checkNodeName(nodeName)
+	confirmation, socket := checkNodeName(ctx, nodeName)
 	if !confirmation {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Node name requested doesn't exist"})
 		return
 	}
 
 	// Retrieving height from query
 	recvHeight := r.URL.Query().Get("height")
-	height := checkHeight(recvHeight)
+	height := checkHeight(ctx, recvHeight)
 	if height == -1 {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Unexpected value found, height needs to be " +
 				"a string representing an int!"})
 		return
 	}
 
 	// Attempt to load connection with consensus client
-	connection, co := loadConsensusClient(socket)
+	connection, co := loadConsensusClient(ctx, socket)
 
 	// Close connection once code underneath executes
-	defer connection.Close()
+	defer closeConnection(connection)
 
 	// If null object was retrieved send response
 	if co == nil {
 
 		// Stop code here faild to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to establish connection using socket: " +
 				socket})
 		return
 	}
 
 	// Retrieve block at specific height from consensus client
-	blk, err := co.GetBlock(context.Background(), height)
+	blk, err := co.GetBlock(ctx, height)
 	if err != nil {
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to retrieve Block!"})
 
-		lgr.Error.Println("Request at /api/consensus/blocklastcommit "+
+		log.Error.Println("Request at /api/consensus/blocklastcommit "+
 			"failed to retrieve Block : ", err)
 		return
 	}
@@ -509,16 +536,16 @@ func GetBlockLastCommit(w http.ResponseWriter, r *http.Request) {
 	// Creating BlockMeta object
 	var meta mint_api.BlockMeta
 	if err := cbor.Unmarshal(blk.Meta, &meta); err != nil {
-		lgr.Error.Println("Request at /api/consensus/blocklastcommit "+
+		log.Error.Println("Request at /api/consensus/blocklastcommit "+
 			"failed Unmarshal Block Metadata : ", err)
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to Unmarshal Block Metadata!"})
 		return
 	}
 	// Responds with Block Last commit retrieved above
-	lgr.Info.Println("Request at /api/consensus/blocklastcommit " +
+	log.Debug.Println("Request at /api/consensus/blocklastcommit " +
 		"responding with Block Last Commit!")
-	json.NewEncoder(w).Encode(responses.BlockLastCommitResponse{
+	encodeResponse(ctx, w, responses.BlockLastCommitResponse{
 		BlkLastCommit: meta.LastCommit})
 }
 
@@ -530,11 +557,14 @@ func PublicKeyToAddress(w http.ResponseWriter, r *http.Request) {
 	// Add header so that received knows they're receiving JSON
 	w.Header().Add("Content-Type", "application/json")
 
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
 	// Retrieving consensus public key from the query
 	consensusKey := r.URL.Query().Get("consensus_public_key")
 	if consensusKey == "" {
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "No Consensus Key Provided"})
 		return
 	}
@@ -542,9 +572,9 @@ func PublicKeyToAddress(w http.ResponseWriter, r *http.Request) {
 
 	err := consensusPublicKey.UnmarshalText([]byte(consensusKey))
 	if err != nil {
-		lgr.Error.Println("Request at /api/consensus/pubkeyaddress "+
+		log.Error.Println("Request at /api/consensus/pubkeyaddress "+
 			"failed to Unmarshal Consensus PublicKey : ", err)
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to Unmarshal Public Key!"})
 		return
 	}
@@ -552,9 +582,9 @@ func PublicKeyToAddress(w http.ResponseWriter, r *http.Request) {
 	tendermintKey := crypto.PublicKeyToCometBFT(consensusPublicKey)
 	cryptoAddress := tendermintKey.Address()
 	// Responds with transactions retrieved above
-	lgr.Info.Println("Request at /api/consensus/pubkeyaddress responding " +
+	log.Debug.Println("Request at /api/consensus/pubkeyaddress responding " +
 		"with Tendermint Public Key Address!")
-	json.NewEncoder(w).Encode(responses.TendermintAddress{
+	encodeResponse(ctx, w, responses.TendermintAddress{
 		TendermintAddress: &cryptoAddress})
 }
 
@@ -564,40 +594,43 @@ func GetTransactions(w http.ResponseWriter, r *http.Request) {
 	// Add header so that received knows they're receiving JSON
 	w.Header().Add("Content-Type", "application/json")
 
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
 	// Retrieving name of node from query request
 	nodeName := r.URL.Query().Get("name")
-	confirmation, socket := checkNodeName(nodeName)
+	confirmation, socket := checkNodeName(ctx, nodeName)
 	if !confirmation {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Node name requested doesn't exist"})
 		return
 	}
 
 	// Retrieving height from query
 	recvHeight := r.URL.Query().Get("height")
-	height := checkHeight(recvHeight)
+	height := checkHeight(ctx, recvHeight)
 	if height == -1 {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Unexpected value found, height needs to be " +
 				"a string representing an int!"})
 		return
 	}
 
 	// Attempt to load connection with consensus client
-	connection, co := loadConsensusClient(socket)
+	connection, co := loadConsensusClient(ctx, socket)
 
 	// Close connection once code underneath executes
-	defer connection.Close()
+	defer closeConnection(connection)
 
 	// If null object was retrieved send response
 	if co == nil {
 
 		// Stop code here faild to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to establish connection using socket: " +
 				socket})
 		return
@@ -605,19 +638,19 @@ func GetTransactions(w http.ResponseWriter, r *http.Request) {
 
 	// Use consensus client to retrieve transactions at specific block
 	// height
-	transactions, err := co.GetTransactions(context.Background(), height)
+	transactions, err := co.GetTransactions(ctx, height)
 	if err != nil {
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to retrieve Transactions!"})
 
-		lgr.Error.Println("Request at /api/consensus/transactions "+
+		log.Error.Println("Request at /api/consensus/transactions "+
 			"failed to retrieve Transactions : ", err)
 		return
 	}
 
 	// Responds with transactions retrieved above
-	lgr.Info.Println("Request at /api/consensus/transactions responding" +
+	log.Debug.Println("Request at /api/consensus/transactions responding" +
 		"with all transactions in specified Block!")
-	json.NewEncoder(w).Encode(responses.TransactionsResponse{
+	encodeResponse(ctx, w, responses.TransactionsResponse{
 		Transactions: transactions})
 }
@@ -34,9 +34,7 @@ func setup() {
 	// And Load all configuration that need to be used by router
 	os.Chdir("../")
 	lgr.SetLogger(os.Stdout, os.Stdout, os.Stderr)
-	conf.LoadMainConfiguration()
-	conf.LoadNodesConfiguration()
-	conf.LoadSentryConfiguration()
+	conf.Load()
 }
 
 func Test_GetConsensusStateToGenesis_BadNode(t *testing.T) {
@@ -0,0 +1,277 @@
+package handlers
+
+import (
+	"context"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	lgr "github.com/SimplyVC/oasis_api_server/src/logger"
+	"github.com/SimplyVC/oasis_api_server/src/responses"
+	"github.com/oasisprotocol/oasis-core/go/common/prettyprint"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+// unitsBase is the units query value that keeps quantities in base units
+const unitsBase = "base"
+
+var (
+	quantityType       = reflect.TypeOf(quantity.Quantity{})
+	jsonMarshalerType  = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType  = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	emptyInterfaceType = reflect.TypeOf((*interface{})(nil)).Elem()
+)
+
+// denomination holds the token symbol and value exponent reported by the
+// staking backend of a node
+type denomination struct {
+	symbol   string
+	exponent uint8
+}
+
+// notAmounts lists the quantity fields of every struct that aren't amounts
+// of tokens, such as shares, commission rates, reward scales and fee split
+// weights, which must not be converted. Any other quantity is an amount.
+var notAmounts = map[reflect.Type]map[string]bool{
+	reflect.TypeOf(staking.SharePool{}):  {"TotalShares": true},
+	reflect.TypeOf(staking.Delegation{}): {"Shares": true},
+	reflect.TypeOf(staking.DebondingDelegation{}): {
+		"Shares": true},
+	reflect.TypeOf(staking.AddEscrowEvent{}): {"NewShares": true},
+	reflect.TypeOf(staking.DebondingStartEscrowEvent{}): {
+		"ActiveShares": true, "DebondingShares": true},
+	reflect.TypeOf(staking.ReclaimEscrowEvent{}): {"Shares": true},
+	reflect.TypeOf(staking.ReclaimEscrow{}):      {"Shares": true},
+	reflect.TypeOf(staking.AddEscrowResult{}):    {"NewShares": true},
+	reflect.TypeOf(staking.ReclaimEscrowResult{}): {
+		"DebondingShares": true, "RemainingShares": true},
+	reflect.TypeOf(staking.CommissionScheduleRules{}): {
+		"MinCommissionRate": true},
+	reflect.TypeOf(staking.CommissionRateStep{}): {"Rate": true},
+	reflect.TypeOf(staking.CommissionRateBoundStep{}): {
+		"RateMin": true, "RateMax": true},
+	reflect.TypeOf(staking.RewardStep{}): {"Scale": true},
+	reflect.TypeOf(staking.ConsensusParameters{}): {
+		"FeeSplitWeightPropose":     true,
+		"FeeSplitWeightVote":        true,
+		"FeeSplitWeightNextPropose": true,
+		"RewardFactorEpochSigned":   true,
+		"RewardFactorBlockProposed": true,
+	},
+	reflect.TypeOf(staking.ConsensusParameterChanges{}): {
+		"MinCommissionRate":         true,
+		"FeeSplitWeightPropose":     true,
+		"FeeSplitWeightVote":        true,
+		"FeeSplitWeightNextPropose": true,
+		"RewardFactorEpochSigned":   true,
+		"RewardFactorBlockProposed": true,
+	},
+	reflect.TypeOf(responses.CommissionOverview{}): {
+		"RateDenominator": true, "CurrentRate": true},
+}
+
+// loadDenomination retrieves the token symbol and value exponent from the
+// staking client and checks that units matches the token symbol.
+func loadDenomination(ctx context.Context, so staking.Backend,
+	units string) (*denomination, error) {
+
+	symbol, err := so.TokenSymbol(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve token symbol : %w", err)
+	}
+	exponent, err := so.TokenValueExponent(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve token value exponent "+
+			": %w", err)
+	}
+	if !strings.EqualFold(units, symbol) {
+		return nil, fmt.Errorf("units needs to be %s or %s", unitsBase,
+			symbol)
+	}
+	return &denomination{symbol: symbol, exponent: exponent}, nil
+}
+
+// respondWithUnits encodes response as is when units requests base units,
+// otherwise every token amount in result is rendered in both base units and
+// the token denomination of the node.
+func respondWithUnits(ctx context.Context, w http.ResponseWriter,
+	so staking.Backend, units string, result interface{},
+	response interface{}) {
+
+	log := lgr.FromContext(ctx)
+
+	if len(units) == 0 || strings.EqualFold(units, unitsBase) {
+		encodeResponse(ctx, w, response)
+		return
+	}
+
+	denom, err := loadDenomination(ctx, so, units)
+	if err != nil {
+		log.Error.Println("Failed to denominate response : ", err)
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to denominate response, " + err.Error() + "!"})
+		return
+	}
+	encodeResponse(ctx, w, responses.DenominatedResponse{
+		Result: denom.convert(reflect.ValueOf(result), true)})
+}
+
+// convert walks v the same way encoding/json would and replaces every token
+// amount with a DenominatedQuantity. amount tells whether quantities found
+// in v are token amounts, which is decided by the closest enclosing struct
+// field.
+func (d *denomination) convert(v reflect.Value, amount bool) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+
+	// Quantities are checked before marshalers since they implement
+	// encoding.TextMarshaler themselves
+	if v.Type() == quantityType || (v.Kind() == reflect.Ptr &&
+		v.Type().Elem() == quantityType) {
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return nil
+			}
+			v = v.Elem()
+		}
+		q := v.Interface().(quantity.Quantity)
+		if !amount {
+			return q
+		}
+		return d.denominate(q)
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+	}
+
+	// Types with their own encoding, such as addresses and hashes, are
+	// left for encoding/json to handle
+	if v.Type().Implements(jsonMarshalerType) ||
+		v.Type().Implements(textMarshalerType) {
+		return v.Interface()
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return d.convert(v.Elem(), amount)
+	case reflect.Struct:
+		return d.convertStruct(v)
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Interface()
+		}
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		converted := make([]interface{}, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			converted[i] = d.convert(v.Index(i), amount)
+		}
+		return converted
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		converted := reflect.MakeMapWithSize(
+			reflect.MapOf(v.Type().Key(), emptyInterfaceType), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			value := d.convert(iter.Value(), amount)
+			if value == nil {
+				converted.SetMapIndex(iter.Key(),
+					reflect.Zero(emptyInterfaceType))
+				continue
+			}
+			converted.SetMapIndex(iter.Key(), reflect.ValueOf(value))
+		}
+		return converted.Interface()
+	}
+	return v.Interface()
+}
+
+// convertStruct converts the exported fields of a struct keeping their JSON
+// names, order and omitempty behaviour.
+func (d *denomination) convertStruct(v reflect.Value) interface{} {
+	fields := responses.OrderedFields{}
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+
+		// Embedded structs without a name have their fields promoted
+		if field.Anonymous && name == "" {
+			embedded := v.Field(i)
+			if embedded.Kind() == reflect.Ptr {
+				if embedded.IsNil() {
+					continue
+				}
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				if promoted, ok := d.convertStruct(
+					embedded).(responses.OrderedFields); ok {
+					fields = append(fields, promoted...)
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if strings.Contains(options, "omitempty") &&
+			isEmptyValue(v.Field(i)) {
+			continue
+		}
+		amount := !notAmounts[v.Type()][field.Name]
+		fields = append(fields, responses.OrderedField{
+			Name:  name,
+			Value: d.convert(v.Field(i), amount),
+		})
+	}
+	return fields
+}
+
+// denominate renders a quantity in base units and in the token denomination
+func (d *denomination) denominate(q quantity.Quantity) *responses.DenominatedQuantity {
+	return &responses.DenominatedQuantity{
+		Base:   q,
+		Amount: prettyprint.QuantityFrac(q, d.exponent),
+		Symbol: d.symbol,
+	}
+}
+
+// isEmptyValue mirrors the omitempty rules of encoding/json
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	hdl "github.com/SimplyVC/oasis_api_server/src/handlers"
+	common_quantity "github.com/oasisprotocol/oasis-core/go/common/quantity"
+	staking_api "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+// denominate converts result for a node with 9 decimals and returns it as
+// JSON
+func denominate(t *testing.T, result interface{}) string {
+	data, err := json.Marshal(hdl.Denominate(result, "ROSE", 9))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func Test_Denominate_Account(t *testing.T) {
+	account := &staking_api.Account{}
+	account.General.Balance = *common_quantity.NewFromUint64(1500000000)
+	account.Escrow.Active.Balance = *common_quantity.NewFromUint64(
+		2000000000)
+	account.Escrow.Active.TotalShares = *common_quantity.NewFromUint64(
+		2000000000)
+	account.Escrow.CommissionSchedule.Rates = []staking_api.
+		CommissionRateStep{{Start: 10,
+		Rate: *common_quantity.NewFromUint64(5000)}}
+
+	body := denominate(t, account)
+	for _, expected := range []string{
+		`"general":{"balance":{"base":"1500000000","amount":"1.5",` +
+			`"symbol":"ROSE"}`,
+		`"active":{"balance":{"base":"2000000000","amount":"2.0",` +
+			`"symbol":"ROSE"},"total_shares":"2000000000"}`,
+		`"rates":[{"start":10,"rate":"5000"}]`,
+	} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("Failed to find %s in %s", expected, body)
+		}
+	}
+}
+
+func Test_Denominate_Delegations(t *testing.T) {
+	delegations := map[staking_api.Address]*staking_api.Delegation{
+		{}: {Shares: *common_quantity.NewFromUint64(700)}}
+	parameters := &staking_api.ConsensusParameters{
+		MinDelegationAmount: *common_quantity.NewFromUint64(100000000),
+		FeeSplitWeightVote:  *common_quantity.NewFromUint64(1),
+	}
+
+	// Shares and weights are left as they are, amounts are denominated
+	if body := denominate(t, delegations); !strings.Contains(body,
+		`{"shares":"700"}`) {
+		t.Errorf("Unexpected delegations %s", body)
+	}
+	body := denominate(t, parameters)
+	for _, expected := range []string{
+		`"min_delegation":{"base":"100000000","amount":"0.1",` +
+			`"symbol":"ROSE"}`,
+		`"fee_split_weight_vote":"1"`,
+	} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("Failed to find %s in %s", expected, body)
+		}
+	}
+
+	// Quantities returned on their own, such as the total supply, are
+	// amounts
+	var supply interface{} = common_quantity.NewFromUint64(42000000000)
+	if body := denominate(t, supply); body !=
+		`{"base":"42000000000","amount":"42.0","symbol":"ROSE"}` {
+		t.Errorf("Unexpected total supply %s", body)
+	}
+}
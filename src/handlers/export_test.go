@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"reflect"
+
+	common_signature "github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	common_node "github.com/oasisprotocol/oasis-core/go/common/node"
+)
+
+// Internal functions exported for the tests of the handlers_test package,
+// so that their logic can be tested without a node
+var (
+	CommissionOverview    = commissionOverview
+	ExtrapolateEpochStart = extrapolateEpochStart
+	ErrEpochTooFar        = errEpochTooFar
+	StartConnect          = startConnect
+	EndConnect            = endConnect
+	RankValidatorEntities = rankValidatorEntities
+)
+
+// Denominate converts the token amounts of result the way respondWithUnits
+// does for a node with the given token symbol and value exponent
+func Denominate(result interface{}, symbol string,
+	exponent uint8) interface{} {
+
+	d := &denomination{symbol: symbol, exponent: exponent}
+	return d.convert(reflect.ValueOf(result), true)
+}
+
+// LookupNodeKey returns the node known by a public key and which of its keys
+// matched, the way the node lookup resolves keys
+func LookupNodeKey(nodes []*common_node.Node,
+	key common_signature.PublicKey) (*common_node.Node, string, bool) {
+
+	match, ok := nodeKeyIndex(nodes)[key]
+	return match.node, match.matchedBy, ok
+}
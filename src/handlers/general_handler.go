@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"encoding/json"
 	"net/http"
 	"sync"
 
@@ -15,8 +14,11 @@ func Pong(w http.ResponseWriter, r *http.Request) {
 
 	// Add header so that received knows they're receiving JSON
 	w.Header().Add("Content-Type", "application/json")
-	lgr.Info.Println("Received request for /api/ping")
-	json.NewEncoder(w).Encode(responses.SuccessResponsed)
+
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+	log.Debug.Println("Received request for /api/ping")
+	encodeResponse(ctx, w, responses.SuccessResponsed)
 }
 
 // GetConnections retrieves the node names that are configured in the API
@@ -24,7 +26,10 @@ func GetConnections(w http.ResponseWriter, r *http.Request) {
 
 	// Add header so that received knows they're receiving JSON
 	w.Header().Add("Content-Type", "application/json")
-	lgr.Info.Println("Received request for /api/getconnectionslist")
+
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+	log.Debug.Println("Received request for /api/getconnectionslist")
 
 	mutex := &sync.RWMutex{}
 	mutex.Lock()
@@ -33,15 +38,15 @@ func GetConnections(w http.ResponseWriter, r *http.Request) {
 	connectionsResponse := []string{}
 	allSockets := config.GetNodes()
 
-	lgr.Info.Println("Iterating through all socket connections.")
+	log.Debug.Println("Iterating through all socket connections.")
 	for _, socket := range allSockets {
-		lgr.Info.Printf("Node: %s has socket %s \n",
+		log.Debug.Printf("Node: %s has socket %s \n",
 			socket["node_name"], socket["isocket_path"])
 		connectionsResponse = append(connectionsResponse,
 			socket["node_name"])
 	}
 	// Encode object and send it using predefind response
-	json.NewEncoder(w).Encode(responses.ConnectionsResponse{
+	encodeResponse(ctx, w, responses.ConnectionsResponse{
 		Results: connectionsResponse})
 	mutex.Unlock()
 }
@@ -0,0 +1,450 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"google.golang.org/grpc"
+
+	lgr "github.com/SimplyVC/oasis_api_server/src/logger"
+	"github.com/SimplyVC/oasis_api_server/src/responses"
+	"github.com/SimplyVC/oasis_api_server/src/rpc"
+	governance "github.com/oasisprotocol/oasis-core/go/governance/api"
+)
+
+// loadGovernanceClient loads governance client and returns it
+func loadGovernanceClient(ctx context.Context, socket string) (
+	*grpc.ClientConn, governance.Backend) {
+
+	log := lgr.FromContext(ctx)
+
+	// Attempt to load connection with governance client
+	span := startConnect(ctx, "governance", socket)
+	connection, governanceClient, err := rpc.GovernanceClient(socket)
+	endConnect(ctx, span, connection, err)
+	if err != nil {
+		log.Error.Println(
+			"Failed to establish connection to governance client : ",
+			err)
+		return nil, nil
+	}
+	return connection, governanceClient
+}
+
+// GetActiveProposals returns the governance proposals that are still open
+// for voting at a block height.
+func GetActiveProposals(w http.ResponseWriter, r *http.Request) {
+
+	// Add header so that received knows they're receiving JSON
+	w.Header().Add("Content-Type", "application/json")
+
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
+	// Retrieving name of node from query request
+	nodeName := r.URL.Query().Get("name")
+	confirmation, socket := checkNodeName(ctx, nodeName)
+	if !confirmation {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Node name requested doesn't exist"})
+		return
+	}
+
+	// Retrieving height from query request
+	recvHeight := r.URL.Query().Get("height")
+	height := checkHeight(ctx, recvHeight)
+	if height == -1 {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Unexpected value found, height needs to be " +
+				"a string representing an int!"})
+		return
+	}
+
+	// Attempt to load connection with governance client
+	connection, gc := loadGovernanceClient(ctx, socket)
+
+	// Close connection once code underneath executes
+	defer closeConnection(connection)
+
+	// If null object was retrieved send response
+	if gc == nil {
+
+		// Stop code here faild to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to establish connection using socket : " +
+				socket})
+		return
+	}
+
+	// Retrieve active proposals at given block height
+	proposals, err := gc.ActiveProposals(ctx, height)
+	if err != nil {
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to get Active Proposals!"})
+		log.Error.Println("Request at /api/governance/activeproposals "+
+			"failed to retrieve Active Proposals : ", err)
+		return
+	}
+
+	// Responding with active proposals retrieved from governance client
+	log.Debug.Println("Request at /api/governance/activeproposals " +
+		"responding with Active Proposals!")
+	encodeResponse(ctx, w, responses.ProposalsResponse{
+		Proposals: proposals})
+}
+
+// GetProposal returns a single governance proposal at a block height.
+func GetProposal(w http.ResponseWriter, r *http.Request) {
+
+	// Add header so that received knows they're receiving JSON
+	w.Header().Add("Content-Type", "application/json")
+
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
+	// Retrieving name of node from query request
+	nodeName := r.URL.Query().Get("name")
+	confirmation, socket := checkNodeName(ctx, nodeName)
+	if !confirmation {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Node name requested doesn't exist"})
+		return
+	}
+
+	// Retrieving height from query request
+	recvHeight := r.URL.Query().Get("height")
+	height := checkHeight(ctx, recvHeight)
+	if height == -1 {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Unexpected value found, height needs to be " +
+				"a string representing an int!"})
+		return
+	}
+
+	// Retrieving proposal ID from query request
+	proposalID, ok := checkProposalID(ctx, r.URL.Query().Get("id"))
+	if !ok {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Unexpected value found, id needs to be " +
+				"a string representing an unsigned int!"})
+		return
+	}
+
+	// Attempt to load connection with governance client
+	connection, gc := loadGovernanceClient(ctx, socket)
+
+	// Close connection once code underneath executes
+	defer closeConnection(connection)
+
+	// If null object was retrieved send response
+	if gc == nil {
+
+		// Stop code here faild to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to establish connection using socket : " +
+				socket})
+		return
+	}
+
+	// Retrieve proposal at given block height
+	query := governance.ProposalQuery{Height: height, ProposalID: proposalID}
+	proposal, err := gc.Proposal(ctx, &query)
+	if err != nil {
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to get Proposal!"})
+		log.Error.Println("Request at /api/governance/proposal "+
+			"failed to retrieve Proposal : ", err)
+		return
+	}
+
+	// Responding with proposal retrieved from governance client
+	log.Debug.Println("Request at /api/governance/proposal responding " +
+		"with Proposal!")
+	encodeResponse(ctx, w, responses.ProposalResponse{
+		Proposal: proposal})
+}
+
+// GetVotes returns the votes cast for a governance proposal at a block
+// height.
+func GetVotes(w http.ResponseWriter, r *http.Request) {
+
+	// Add header so that received knows they're receiving JSON
+	w.Header().Add("Content-Type", "application/json")
+
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
+	// Retrieving name of node from query request
+	nodeName := r.URL.Query().Get("name")
+	confirmation, socket := checkNodeName(ctx, nodeName)
+	if !confirmation {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Node name requested doesn't exist"})
+		return
+	}
+
+	// Retrieving height from query request
+	recvHeight := r.URL.Query().Get("height")
+	height := checkHeight(ctx, recvHeight)
+	if height == -1 {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Unexpected value found, height needs to be " +
+				"a string representing an int!"})
+		return
+	}
+
+	// Retrieving proposal ID from query request
+	proposalID, ok := checkProposalID(ctx, r.URL.Query().Get("id"))
+	if !ok {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Unexpected value found, id needs to be " +
+				"a string representing an unsigned int!"})
+		return
+	}
+
+	// Attempt to load connection with governance client
+	connection, gc := loadGovernanceClient(ctx, socket)
+
+	// Close connection once code underneath executes
+	defer closeConnection(connection)
+
+	// If null object was retrieved send response
+	if gc == nil {
+
+		// Stop code here faild to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to establish connection using socket : " +
+				socket})
+		return
+	}
+
+	// Retrieve votes of proposal at given block height
+	query := governance.ProposalQuery{Height: height, ProposalID: proposalID}
+	votes, err := gc.Votes(ctx, &query)
+	if err != nil {
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to get Votes!"})
+		log.Error.Println("Request at /api/governance/votes "+
+			"failed to retrieve Votes : ", err)
+		return
+	}
+
+	// Responding with votes retrieved from governance client
+	log.Debug.Println("Request at /api/governance/votes responding " +
+		"with Votes!")
+	encodeResponse(ctx, w, responses.VotesResponse{Votes: votes})
+}
+
+// GetPendingUpgrades returns the upgrades accepted by governance that are
+// pending execution at a block height.
+func GetPendingUpgrades(w http.ResponseWriter, r *http.Request) {
+
+	// Add header so that received knows they're receiving JSON
+	w.Header().Add("Content-Type", "application/json")
+
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
+	// Retrieving name of node from query request
+	nodeName := r.URL.Query().Get("name")
+	confirmation, socket := checkNodeName(ctx, nodeName)
+	if !confirmation {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Node name requested doesn't exist"})
+		return
+	}
+
+	// Retrieving height from query request
+	recvHeight := r.URL.Query().Get("height")
+	height := checkHeight(ctx, recvHeight)
+	if height == -1 {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Unexpected value found, height needs to be " +
+				"a string representing an int!"})
+		return
+	}
+
+	// Attempt to load connection with governance client
+	connection, gc := loadGovernanceClient(ctx, socket)
+
+	// Close connection once code underneath executes
+	defer closeConnection(connection)
+
+	// If null object was retrieved send response
+	if gc == nil {
+
+		// Stop code here faild to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to establish connection using socket : " +
+				socket})
+		return
+	}
+
+	// Retrieve pending upgrades at given block height
+	upgrades, err := gc.PendingUpgrades(ctx, height)
+	if err != nil {
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to get Pending Upgrades!"})
+		log.Error.Println("Request at /api/governance/pendingupgrades "+
+			"failed to retrieve Pending Upgrades : ", err)
+		return
+	}
+
+	// Responding with pending upgrades retrieved from governance client
+	log.Debug.Println("Request at /api/governance/pendingupgrades " +
+		"responding with Pending Upgrades!")
+	encodeResponse(ctx, w, responses.PendingUpgradesResponse{
+		PendingUpgrades: upgrades})
+}
+
+// GetGovernanceEvents returns the governance events at a block height.
+func GetGovernanceEvents(w http.ResponseWriter, r *http.Request) {
+
+	// Add header so that received knows they're receiving JSON
+	w.Header().Add("Content-Type", "application/json")
+
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
+	// Retrieving name of node from query request
+	nodeName := r.URL.Query().Get("name")
+	confirmation, socket := checkNodeName(ctx, nodeName)
+	if !confirmation {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Node name requested doesn't exist"})
+		return
+	}
+
+	// Retrieving height from query request
+	recvHeight := r.URL.Query().Get("height")
+	height := checkHeight(ctx, recvHeight)
+	if height == -1 {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Unexpected value found, height needs to be " +
+				"a string representing an int!"})
+		return
+	}
+
+	// Attempt to load connection with governance client
+	connection, gc := loadGovernanceClient(ctx, socket)
+
+	// Close connection once code underneath executes
+	defer closeConnection(connection)
+
+	// If null object was retrieved send response
+	if gc == nil {
+
+		// Stop code here faild to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to establish connection using socket : " +
+				socket})
+		return
+	}
+
+	// Retrieve governance events at given block height
+	events, err := gc.GetEvents(ctx, height)
+	if err != nil {
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to get Events!"})
+		log.Error.Println("Request at /api/governance/events "+
+			"failed to retrieve Events : ", err)
+		return
+	}
+
+	// Responding with events retrieved from governance client
+	log.Debug.Println("Request at /api/governance/events responding " +
+		"with Events!")
+	encodeResponse(ctx, w, responses.GovernanceEventsResponse{
+		Events: events})
+}
+
+// GetGovernanceConsensusParameters returns the governance consensus
+// parameters at a block height.
+func GetGovernanceConsensusParameters(w http.ResponseWriter, r *http.Request) {
+
+	// Add header so that received knows they're receiving JSON
+	w.Header().Add("Content-Type", "application/json")
+
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
+	// Retrieving name of node from query request
+	nodeName := r.URL.Query().Get("name")
+	confirmation, socket := checkNodeName(ctx, nodeName)
+	if !confirmation {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Node name requested doesn't exist"})
+		return
+	}
+
+	// Retrieving height from query request
+	recvHeight := r.URL.Query().Get("height")
+	height := checkHeight(ctx, recvHeight)
+	if height == -1 {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Unexpected value found, height needs to be " +
+				"a string representing an int!"})
+		return
+	}
+
+	// Attempt to load connection with governance client
+	connection, gc := loadGovernanceClient(ctx, socket)
+
+	// Close connection once code underneath executes
+	defer closeConnection(connection)
+
+	// If null object was retrieved send response
+	if gc == nil {
+
+		// Stop code here faild to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to establish connection using socket : " +
+				socket})
+		return
+	}
+
+	// Retrieve consensus parameters at given block height
+	params, err := gc.ConsensusParameters(ctx, height)
+	if err != nil {
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to get Consensus Parameters!"})
+		log.Error.Println("Request at /api/governance/consensusparameters "+
+			"failed to retrieve Consensus Parameters : ", err)
+		return
+	}
+
+	// Responding with consensus parameters retrieved from governance client
+	log.Debug.Println("Request at /api/governance/consensusparameters " +
+		"responding with Consensus Parameters!")
+	encodeResponse(ctx, w,
+		responses.GovernanceConsensusParametersResponse{
+			ConsensusParameters: params})
+}
@@ -0,0 +1,520 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	hdl "github.com/SimplyVC/oasis_api_server/src/handlers"
+	"github.com/SimplyVC/oasis_api_server/src/responses"
+	governance_api "github.com/oasisprotocol/oasis-core/go/governance/api"
+	upgrade_api "github.com/oasisprotocol/oasis-core/go/upgrade/api"
+)
+
+func Test_GetActiveProposals_BadNode(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/governance/activeproposals", nil)
+	q := req.URL.Query()
+	q.Add("name", "Unicorn")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetActiveProposals)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Node name requested doesn't exist"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetActiveProposals_InvalidHeight(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/governance/activeproposals", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	q.Add("height", "Unicorn")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetActiveProposals)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Unexpected value found, height needs to be a string representing an int!"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetActiveProposals(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/governance/activeproposals", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetActiveProposals)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := "result"
+
+	response := &responses.ProposalsResponse{
+		Proposals: []*governance_api.Proposal{},
+	}
+
+	err := json.Unmarshal([]byte(rr.Body.String()), response)
+	if err != nil {
+		t.Errorf("Failed to unmarshall data")
+	}
+
+	if strings.Contains(strings.TrimSpace(rr.Body.String()), expected) != true {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			strings.TrimSpace(rr.Body.String()), expected)
+	}
+}
+
+func Test_GetProposal_BadNode(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/governance/proposal", nil)
+	q := req.URL.Query()
+	q.Add("name", "Unicorn")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetProposal)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Node name requested doesn't exist"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetProposal_InvalidHeight(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/governance/proposal", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	q.Add("height", "Unicorn")
+	q.Add("id", "1")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetProposal)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Unexpected value found, height needs to be a string representing an int!"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetProposal_InvalidID(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/governance/proposal", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	q.Add("id", "Unicorn")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetProposal)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Unexpected value found, id needs to be a string representing an unsigned int!"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetProposal(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/governance/proposal", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	q.Add("id", "1")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetProposal)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := "result"
+
+	response := &responses.ProposalResponse{
+		Proposal: &governance_api.Proposal{},
+	}
+
+	err := json.Unmarshal([]byte(rr.Body.String()), response)
+	if err != nil {
+		t.Errorf("Failed to unmarshall data")
+	}
+
+	if strings.Contains(strings.TrimSpace(rr.Body.String()), expected) != true {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			strings.TrimSpace(rr.Body.String()), expected)
+	}
+}
+
+func Test_GetVotes_BadNode(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/governance/votes", nil)
+	q := req.URL.Query()
+	q.Add("name", "Unicorn")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetVotes)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Node name requested doesn't exist"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetVotes_InvalidHeight(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/governance/votes", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	q.Add("height", "Unicorn")
+	q.Add("id", "1")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetVotes)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Unexpected value found, height needs to be a string representing an int!"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetVotes_InvalidID(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/governance/votes", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	q.Add("id", "Unicorn")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetVotes)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Unexpected value found, id needs to be a string representing an unsigned int!"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetVotes(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/governance/votes", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	q.Add("id", "1")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetVotes)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := "result"
+
+	response := &responses.VotesResponse{
+		Votes: []*governance_api.VoteEntry{},
+	}
+
+	err := json.Unmarshal([]byte(rr.Body.String()), response)
+	if err != nil {
+		t.Errorf("Failed to unmarshall data")
+	}
+
+	if strings.Contains(strings.TrimSpace(rr.Body.String()), expected) != true {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			strings.TrimSpace(rr.Body.String()), expected)
+	}
+}
+
+func Test_GetPendingUpgrades_BadNode(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/governance/pendingupgrades", nil)
+	q := req.URL.Query()
+	q.Add("name", "Unicorn")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetPendingUpgrades)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Node name requested doesn't exist"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetPendingUpgrades_InvalidHeight(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/governance/pendingupgrades", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	q.Add("height", "Unicorn")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetPendingUpgrades)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Unexpected value found, height needs to be a string representing an int!"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetPendingUpgrades(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/governance/pendingupgrades", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetPendingUpgrades)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := "result"
+
+	response := &responses.PendingUpgradesResponse{
+		PendingUpgrades: []*upgrade_api.Descriptor{},
+	}
+
+	err := json.Unmarshal([]byte(rr.Body.String()), response)
+	if err != nil {
+		t.Errorf("Failed to unmarshall data")
+	}
+
+	if strings.Contains(strings.TrimSpace(rr.Body.String()), expected) != true {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			strings.TrimSpace(rr.Body.String()), expected)
+	}
+}
+
+func Test_GetGovernanceEvents_BadNode(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/governance/events", nil)
+	q := req.URL.Query()
+	q.Add("name", "Unicorn")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetGovernanceEvents)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Node name requested doesn't exist"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetGovernanceEvents_InvalidHeight(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/governance/events", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	q.Add("height", "Unicorn")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetGovernanceEvents)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Unexpected value found, height needs to be a string representing an int!"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetGovernanceEvents(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/governance/events", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetGovernanceEvents)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := "result"
+
+	response := &responses.GovernanceEventsResponse{
+		Events: []*governance_api.Event{},
+	}
+
+	err := json.Unmarshal([]byte(rr.Body.String()), response)
+	if err != nil {
+		t.Errorf("Failed to unmarshall data")
+	}
+
+	if strings.Contains(strings.TrimSpace(rr.Body.String()), expected) != true {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			strings.TrimSpace(rr.Body.String()), expected)
+	}
+}
+
+func Test_GetGovernanceConsensusParameters_BadNode(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/governance/consensusparameters", nil)
+	q := req.URL.Query()
+	q.Add("name", "Unicorn")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetGovernanceConsensusParameters)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Node name requested doesn't exist"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetGovernanceConsensusParameters_InvalidHeight(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/governance/consensusparameters", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	q.Add("height", "Unicorn")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetGovernanceConsensusParameters)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Unexpected value found, height needs to be a string representing an int!"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetGovernanceConsensusParameters(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/governance/consensusparameters", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetGovernanceConsensusParameters)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := "result"
+
+	response := &responses.GovernanceConsensusParametersResponse{
+		ConsensusParameters: &governance_api.ConsensusParameters{},
+	}
+
+	err := json.Unmarshal([]byte(rr.Body.String()), response)
+	if err != nil {
+		t.Errorf("Failed to unmarshall data")
+	}
+
+	if strings.Contains(strings.TrimSpace(rr.Body.String()), expected) != true {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			strings.TrimSpace(rr.Body.String()), expected)
+	}
+}
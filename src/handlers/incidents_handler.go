@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/SimplyVC/oasis_api_server/src/incidents"
+	lgr "github.com/SimplyVC/oasis_api_server/src/logger"
+	"github.com/SimplyVC/oasis_api_server/src/responses"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+)
+
+// incidentTypes lists the types of incidents that can be filtered on
+var incidentTypes = map[string]bool{
+	incidents.TypeSlashing:             true,
+	incidents.TypeFreeze:               true,
+	incidents.TypeUnfreeze:             true,
+	incidents.TypeRegistrationExpiring: true,
+	incidents.TypeRegistrationExpired:  true,
+}
+
+// GetIncidents returns the slashing, freeze and registration expiry
+// incidents recorded for the monitored entities and their nodes.
+func GetIncidents(w http.ResponseWriter, r *http.Request) {
+
+	// Add header so that received knows they're receiving JSON
+	w.Header().Add("Content-Type", "application/json")
+
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
+	// Retrieving height range from query request, missing heights leave
+	// the range open
+	fromHeight := checkHeight(ctx, r.URL.Query().Get("from_height"))
+	toHeight := checkHeight(ctx, r.URL.Query().Get("to_height"))
+	if fromHeight == -1 || toHeight == -1 {
+
+		// Stop code here no need to look up incidents and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Unexpected value found, height needs to be a string representing an int!"})
+		return
+	}
+
+	// Retrieving type of incidents from query request
+	incidentType := r.URL.Query().Get("type")
+	if len(incidentType) != 0 && !incidentTypes[incidentType] {
+
+		// Stop code here no need to look up incidents and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Unexpected value found, type needs to be one of slashing, freeze, unfreeze, registration_expiring or registration_expired!"})
+		return
+	}
+
+	query := incidents.Query{
+		Type:       incidentType,
+		FromHeight: fromHeight,
+		ToHeight:   toHeight,
+	}
+
+	// Retrieving entity and node from query request, each narrows the
+	// incidents down when given
+	if entity := r.URL.Query().Get("entity"); len(entity) != 0 {
+		var entityID signature.PublicKey
+		if err := entityID.UnmarshalText([]byte(entity)); err != nil {
+			log.Error.Println("Failed to UnmarshalText into Public Key", err)
+			encodeResponse(ctx, w, responses.ErrorResponse{
+				Error: "Failed to UnmarshalText into Public Key."})
+			return
+		}
+		query.EntityID = &entityID
+	}
+	if node := r.URL.Query().Get("nodeID"); len(node) != 0 {
+		var nodeID signature.PublicKey
+		if err := nodeID.UnmarshalText([]byte(node)); err != nil {
+			log.Error.Println("Failed to UnmarshalText into Public Key", err)
+			encodeResponse(ctx, w, responses.ErrorResponse{
+				Error: "Failed to UnmarshalText into Public Key."})
+			return
+		}
+		query.NodeID = &nodeID
+	}
+
+	store, ok := incidents.GetStore()
+	if !ok {
+		log.Warning.Println("Request at /api/incidents failed, incidents " +
+			"aren't being monitored")
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Incidents aren't being monitored, check if the incident monitor is enabled!"})
+		return
+	}
+
+	// Respond with the incidents matching the filters
+	log.Debug.Println("Request at /api/incidents responding with Incidents!")
+	encodeResponse(ctx, w, responses.IncidentsResponse{
+		Incidents: store.Range(query)})
+}
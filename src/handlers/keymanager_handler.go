@@ -0,0 +1,339 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc"
+
+	"github.com/SimplyVC/oasis_api_server/src/config"
+	lgr "github.com/SimplyVC/oasis_api_server/src/logger"
+	"github.com/SimplyVC/oasis_api_server/src/responses"
+	"github.com/SimplyVC/oasis_api_server/src/rpc"
+	common_namespace "github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	keymanager "github.com/oasisprotocol/oasis-core/go/keymanager/api"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+)
+
+// loadKeyManagerClient loads key manager client and returns it
+func loadKeyManagerClient(ctx context.Context, socket string) (
+	*grpc.ClientConn, *keymanager.KeymanagerClient) {
+
+	log := lgr.FromContext(ctx)
+
+	// Attempt to load connection with key manager client
+	span := startConnect(ctx, "key manager", socket)
+	connection, keyManagerClient, err := rpc.KeyManagerClient(socket)
+	endConnect(ctx, span, connection, err)
+	if err != nil {
+		log.Error.Println(
+			"Failed to establish connection to key manager client : ",
+			err)
+		return nil, nil
+	}
+	return connection, keyManagerClient
+}
+
+// GetKeyManagerStatuses returns the statuses of all key managers at a block
+// height.
+func GetKeyManagerStatuses(w http.ResponseWriter, r *http.Request) {
+
+	// Add header so that received knows they're receiving JSON
+	w.Header().Add("Content-Type", "application/json")
+
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
+	// Retrieving name of node from query request
+	nodeName := r.URL.Query().Get("name")
+	confirmation, socket := checkNodeName(ctx, nodeName)
+	if !confirmation {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Node name requested doesn't exist"})
+		return
+	}
+
+	// Retrieve height from query
+	recvHeight := r.URL.Query().Get("height")
+	height := checkHeight(ctx, recvHeight)
+	if height == -1 {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Unexpected value found, height needs to be " +
+				"a string representing an int!"})
+		return
+	}
+
+	// Attempt to load connection with key manager client
+	connection, km := loadKeyManagerClient(ctx, socket)
+
+	// Close connection once code underneath executes
+	defer closeConnection(connection)
+
+	// If null object was retrieved send response
+	if km == nil {
+
+		// Stop code here faild to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to establish connection using socket: " +
+				socket})
+		return
+	}
+
+	// Retrieve statuses of all key managers at given height
+	statuses, err := km.GetStatuses(ctx, height)
+	if err != nil {
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to get Key Manager Statuses!"})
+		log.Error.Println("Request at /api/keymanager/statuses failed "+
+			"to retrieve Key Manager Statuses : ", err)
+		return
+	}
+
+	// Responding with key manager statuses retrieved above
+	log.Debug.Println("Request at /api/keymanager/statuses responding " +
+		"with Key Manager Statuses!")
+	encodeResponse(ctx, w, responses.KeyManagerStatusesResponse{
+		Statuses: statuses})
+}
+
+// GetKeyManagerStatus returns the status of the key manager with the given
+// runtime ID at a block height.
+func GetKeyManagerStatus(w http.ResponseWriter, r *http.Request) {
+
+	// Add header so that received knows they're receiving JSON
+	w.Header().Add("Content-Type", "application/json")
+
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
+	status, ok := keyManagerStatus(w, r, "/api/keymanager/status")
+	if !ok {
+		return
+	}
+
+	// Responding with key manager status retrieved above
+	log.Debug.Println("Request at /api/keymanager/status responding " +
+		"with Key Manager Status!")
+	encodeResponse(ctx, w, responses.KeyManagerStatusResponse{
+		Status: status})
+}
+
+// GetKeyManagerPolicy returns the signed SGX policy of the key manager with
+// the given runtime ID at a block height.
+func GetKeyManagerPolicy(w http.ResponseWriter, r *http.Request) {
+
+	// Add header so that received knows they're receiving JSON
+	w.Header().Add("Content-Type", "application/json")
+
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
+	status, ok := keyManagerStatus(w, r, "/api/keymanager/policy")
+	if !ok {
+		return
+	}
+
+	// Responding with key manager policy retrieved above, a key manager
+	// without a policy responds with null
+	log.Debug.Println("Request at /api/keymanager/policy responding " +
+		"with Key Manager Policy!")
+	encodeResponse(ctx, w, responses.KeyManagerPolicyResponse{
+		Policy: status.Policy})
+}
+
+// GetKeyManagerNodeCheck checks whether node IDs appear in the node set of
+// the key manager with the given runtime ID at a block height. Node IDs are
+// given as a comma separated list, without them the identities of all the
+// configured nodes are checked.
+func GetKeyManagerNodeCheck(w http.ResponseWriter, r *http.Request) {
+
+	// Add header so that received knows they're receiving JSON
+	w.Header().Add("Content-Type", "application/json")
+
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
+	// Retrieving node IDs from query request
+	nodes := []responses.KeyManagerNodeMembership{}
+	for _, field := range strings.Split(r.URL.Query().Get("nodeIDs"), ",") {
+		field = strings.TrimSpace(field)
+		if len(field) == 0 {
+			continue
+		}
+		var nodeID signature.PublicKey
+		if err := nodeID.UnmarshalText([]byte(field)); err != nil {
+			log.Error.Println("Failed to UnmarshalText into Public Key", err)
+			encodeResponse(ctx, w, responses.ErrorResponse{
+				Error: "Failed to UnmarshalText into Public Key."})
+			return
+		}
+		nodes = append(nodes, responses.KeyManagerNodeMembership{
+			NodeID: &nodeID})
+	}
+
+	status, ok := keyManagerStatus(w, r, "/api/keymanager/nodecheck")
+	if !ok {
+		return
+	}
+
+	// Without node IDs check the identities of the configured nodes
+	if len(nodes) == 0 {
+		nodes = configuredNodeIdentities(ctx)
+	}
+
+	inNodeSet := map[signature.PublicKey]bool{}
+	for _, nodeID := range status.Nodes {
+		inNodeSet[nodeID] = true
+	}
+
+	check := responses.KeyManagerNodeCheck{
+		RuntimeID:  status.ID,
+		AllPresent: len(nodes) != 0,
+		Nodes:      nodes,
+	}
+	for i := range check.Nodes {
+		node := &check.Nodes[i]
+		if node.NodeID != nil {
+			node.InNodeSet = inNodeSet[*node.NodeID]
+		}
+		if !node.InNodeSet {
+			check.AllPresent = false
+		}
+	}
+
+	// Responding with the membership of the node IDs
+	log.Debug.Println("Request at /api/keymanager/nodecheck responding " +
+		"with Key Manager Node Check!")
+	encodeResponse(ctx, w, responses.KeyManagerNodeCheckResponse{
+		NodeCheck: &check})
+}
+
+// keyManagerStatus retrieves the status of the key manager requested by the
+// name, height and namespace of a query. Errors are replied to and false is
+// returned so that handlers can stop.
+func keyManagerStatus(w http.ResponseWriter, r *http.Request,
+	endpoint string) (*keymanager.Status, bool) {
+
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
+	// Retrieving name of node from query request
+	nodeName := r.URL.Query().Get("name")
+	confirmation, socket := checkNodeName(ctx, nodeName)
+	if !confirmation {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Node name requested doesn't exist"})
+		return nil, false
+	}
+
+	// Retrieve height from query
+	recvHeight := r.URL.Query().Get("height")
+	height := checkHeight(ctx, recvHeight)
+	if height == -1 {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Unexpected value found, height needs to be " +
+				"a string representing an int!"})
+		return nil, false
+	}
+
+	// Note Make sure that namespace that is being sent is coded properly
+	// Example A1X90rT/WK4AOTh/dJsUlOqNDV/nXM6ZU+h+blS9pto= should be
+	// A1X90rT/WK4AOTh/dJsUlOqNDV/nXM6ZU%2Bh%2BblS9pto=
+	var nameSpace common_namespace.Namespace
+	nmspace := r.URL.Query().Get("namespace")
+	if len(nmspace) == 0 {
+		// Stop code here no need to establish connection and reply
+		log.Warning.Println("Request at " + endpoint + " failed" +
+			", namespace can't be empty!")
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "namespace can't be empty!"})
+		return nil, false
+	}
+
+	// Unmarshal received text into namespace object
+	err := nameSpace.UnmarshalText([]byte(nmspace))
+	if err != nil {
+		log.Error.Println("Failed to UnmarshalText into Namespace", err)
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to UnmarshalText into Namespace."})
+		return nil, false
+	}
+
+	// Attempt to load connection with key manager client
+	connection, km := loadKeyManagerClient(ctx, socket)
+
+	// Close connection once code underneath executes
+	defer closeConnection(connection)
+
+	// If null object was retrieved send response
+	if km == nil {
+
+		// Stop code here faild to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to establish connection using socket: " +
+				socket})
+		return nil, false
+	}
+
+	// Retrieve status of key manager at given height
+	query := registry.NamespaceQuery{ID: nameSpace, Height: height}
+	status, err := km.GetStatus(ctx, &query)
+	if err != nil {
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to get Key Manager Status!"})
+		log.Error.Println("Request at "+endpoint+" failed to retrieve "+
+			"Key Manager Status : ", err)
+		return nil, false
+	}
+	return status, true
+}
+
+// configuredNodeIdentities retrieves the node identity of every configured
+// node, nodes that can't be reached are returned with the error instead
+func configuredNodeIdentities(
+	ctx context.Context) []responses.KeyManagerNodeMembership {
+	log := lgr.FromContext(ctx)
+
+	nodes := []responses.KeyManagerNodeMembership{}
+	cfg := config.Get()
+	if cfg == nil {
+		return nodes
+	}
+	for _, configured := range cfg.Nodes {
+		node := responses.KeyManagerNodeMembership{
+			NodeName: configured.Name}
+
+		connection, nc := loadNodeControllerClient(ctx,
+			configured.ISocketPath)
+		if nc == nil {
+			node.Error = "Failed to establish connection using socket: " +
+				configured.ISocketPath
+			nodes = append(nodes, node)
+			continue
+		}
+
+		status, err := nc.GetStatus(ctx)
+		connection.Close()
+		if err != nil {
+			log.Error.Printf("Failed to retrieve identity of node %s : %s",
+				configured.Name, err)
+			node.Error = "Failed to get Node Identity!"
+			nodes = append(nodes, node)
+			continue
+		}
+		nodeID := status.Identity.Node
+		node.NodeID = &nodeID
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
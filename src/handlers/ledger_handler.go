@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/SimplyVC/oasis_api_server/src/ledger"
+	lgr "github.com/SimplyVC/oasis_api_server/src/logger"
+	"github.com/SimplyVC/oasis_api_server/src/responses"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+// formatCSV is the format query value that exports the ledger as CSV
+const formatCSV = "csv"
+
+// ledgerKinds lists the kinds of entries that can be filtered on
+var ledgerKinds = map[string]bool{
+	ledger.KindTransfer:        true,
+	ledger.KindFee:             true,
+	ledger.KindReward:          true,
+	ledger.KindBurn:            true,
+	ledger.KindEscrowAdd:       true,
+	ledger.KindDebondingStart:  true,
+	ledger.KindEscrowReclaim:   true,
+	ledger.KindSlashing:        true,
+	ledger.KindAllowanceChange: true,
+}
+
+// GetLedger returns the balance changes recorded for the watched addresses,
+// optionally exported as CSV.
+func GetLedger(w http.ResponseWriter, r *http.Request) {
+
+	// Add header so that received knows they're receiving JSON
+	w.Header().Add("Content-Type", "application/json")
+
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
+	// Retrieving height range from query request, missing heights leave
+	// the range open
+	fromHeight := checkHeight(ctx, r.URL.Query().Get("from_height"))
+	toHeight := checkHeight(ctx, r.URL.Query().Get("to_height"))
+	if fromHeight == -1 || toHeight == -1 {
+
+		// Stop code here no need to look up entries and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Unexpected value found, height needs to be a string representing an int!"})
+		return
+	}
+
+	// Retrieving kind of entries from query request
+	kind := r.URL.Query().Get("kind")
+	if len(kind) != 0 && !ledgerKinds[kind] {
+
+		// Stop code here no need to look up entries and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Unexpected value found, kind needs to be one of transfer, fee, reward, burn, escrow_add, debonding_start, escrow_reclaim, slashing or allowance_change!"})
+		return
+	}
+
+	// Retrieving format from query request
+	format := r.URL.Query().Get("format")
+	if len(format) != 0 && format != formatCSV && format != "json" {
+
+		// Stop code here no need to look up entries and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Unexpected value found, format needs to be json or csv!"})
+		return
+	}
+
+	store, ok := ledger.GetStore()
+	if !ok {
+		log.Warning.Println("Request at /api/staking/ledger failed, ledger " +
+			"isn't being kept")
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Ledger isn't being kept, check if the ledger is enabled!"})
+		return
+	}
+
+	query := ledger.Query{
+		Kind:       kind,
+		FromHeight: fromHeight,
+		ToHeight:   toHeight,
+	}
+
+	// Retrieving address from query request, without one the entries of
+	// all watched addresses are returned
+	if addressQuery := r.URL.Query().Get("address"); len(addressQuery) != 0 {
+		var address staking.Address
+		err := address.UnmarshalText([]byte(addressQuery))
+		if err != nil {
+			log.Error.Println("Failed to UnmarshalText into Address", err)
+			encodeResponse(ctx, w, responses.ErrorResponse{
+				Error: "Failed to UnmarshalText into Address."})
+			return
+		}
+		if !ledger.IsWatched(address) {
+			encodeResponse(ctx, w, responses.ErrorResponse{
+				Error: "Address isn't watched by the ledger!"})
+			return
+		}
+		query.Address = &address
+	}
+
+	entries := store.Range(query)
+
+	if format == formatCSV {
+		// Replace the JSON header so that the export is saved as a file
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition",
+			"attachment; filename=\"ledger.csv\"")
+		if err := ledger.WriteCSV(w, entries); err != nil {
+			log.Error.Println("Request at /api/staking/ledger failed to "+
+				"write CSV : ", err)
+			return
+		}
+		log.Debug.Println("Request at /api/staking/ledger responding with " +
+			"Ledger CSV!")
+		return
+	}
+
+	// Respond with the ledger entries
+	log.Debug.Println("Request at /api/staking/ledger responding with " +
+		"Ledger!")
+	encodeResponse(ctx, w, responses.LedgerResponse{Ledger: entries})
+}
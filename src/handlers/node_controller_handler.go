@@ -2,7 +2,6 @@ package handlers
 
 import (
 	"context"
-	"encoding/json"
 	"net/http"
 
 	"google.golang.org/grpc"
@@ -14,14 +13,18 @@ import (
 )
 
 // loadNodeControllerClient loads node controller client and returns it
-func loadNodeControllerClient(socket string) (*grpc.ClientConn,
-	control.NodeController) {
+func loadNodeControllerClient(ctx context.Context, socket string) (
+	*grpc.ClientConn, control.NodeController) {
+
+	log := lgr.FromContext(ctx)
 
 	// Attempt to load connection with staking client
+	span := startConnect(ctx, "node controller", socket)
 	connection, nodeControllerClient, err := rpc.
 		NodeControllerClient(socket)
+	endConnect(ctx, span, connection, err)
 	if err != nil {
-		lgr.Error.Println("Failed to establish connection to "+
+		log.Error.Println("Failed to establish connection to "+
 			"NodeController client : ", err)
 		return nil, nil
 	}
@@ -34,45 +37,48 @@ func GetIsSynced(w http.ResponseWriter, r *http.Request) {
 	// Add header so that received knows they're receiving JSON
 	w.Header().Add("Content-Type", "application/json")
 
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
 	// Retrieving name of node from query request
 	nodeName := r.URL.Query().Get("name")
-	confirmation, socket := checkNodeName(nodeName)
+	confirmation, socket := checkNodeName(ctx, nodeName)
 	if !confirmation  {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Node name requested doesn't exist"})
 		return
 	}
 
 	// Attempt to load connection with staking client
-	connection, nc := loadNodeControllerClient(socket)
+	connection, nc := loadNodeControllerClient(ctx, socket)
 
 	// Close connection once code underneath executes
-	defer connection.Close()
+	defer closeConnection(connection)
 
 	// If null object was retrieved send response
 	if nc == nil {
 
 		// Stop code here faild to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to establish connection using socket: " +
 				socket})
 		return
 	}
 
 	// Retrieving synchronized state from node controller client
-	synced, err := nc.IsSynced(context.Background())
+	synced, err := nc.IsSynced(ctx)
 	if err != nil {
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to get IsSynced!"})
-		lgr.Error.Println("Request at /api/nodecontroller/synced "+
+		log.Error.Println("Request at /api/nodecontroller/synced "+
 			"failed to get IsSynced : ", err)
 		return
 	}
 
 	// Responding with retrieved synchronizatio state above
-	lgr.Info.Println("Request at /api/nodecontroller/synced sending with" +
+	log.Debug.Println("Request at /api/nodecontroller/synced sending with" +
 		" IsSynced State!")
-	json.NewEncoder(w).Encode(responses.IsSyncedResponse{Synced: synced})
+	encodeResponse(ctx, w, responses.IsSyncedResponse{Synced: synced})
 }
@@ -2,7 +2,6 @@ package handlers
 
 import (
 	"bytes"
-	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -16,18 +15,21 @@ import (
 // NodeExporterQueryGauge to retrieve exporter data.
 func NodeExporterQueryGauge(w http.ResponseWriter, r *http.Request) {
 
-	lgr.Info.Println("Received request for /api/exporter/gauge")
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
+	log.Debug.Println("Received request for /api/exporter/gauge")
 
 	// Adding header so that receiver knows they are receiving JSON
 	// structure
 	w.Header().Add("Content-Type", "application/json")
 
 	//Get Node Exporter Metrics URl
-	confirmation, exporterConfig := getNodeExporter()
+	confirmation, exporterConfig := getNodeExporter(ctx)
 	if !confirmation  {
 
 		// Stop the code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Node Exporter is not configured!"})
 		return
 	}
@@ -35,18 +37,18 @@ func NodeExporterQueryGauge(w http.ResponseWriter, r *http.Request) {
 	// Setting the gauge query
 	gaugeName := r.URL.Query().Get("gauge")
 	if gaugeName == "" {
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to retrieve gauge name!"})
-		lgr.Error.Println(
+		log.Error.Println(
 			"Failed to retrieve gauge name, not specified!")
 		return
 	}
 
-	resp, err := http.Get(exporterConfig)
+	resp, err := httpGet(ctx, exporterConfig)
 	if err != nil {
-		lgr.Error.Println(
+		log.Error.Println(
 			"Failed to retrieve Prometheus data from Node Exporter")
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to retrieve Prometheus data check if " +
 				"Node Exporter is enabled!"})
 		return
@@ -57,7 +59,7 @@ func NodeExporterQueryGauge(w http.ResponseWriter, r *http.Request) {
 	// Read the body response from the Node Exporter
 	body, err1 := ioutil.ReadAll(resp.Body)
 	if err1 != nil {
-		lgr.Error.Println(
+		log.Error.Println(
 			"Failed to read the Node Exporter response")
 	}
 	//This Parser needs to be declared inside the function handler
@@ -68,16 +70,16 @@ func NodeExporterQueryGauge(w http.ResponseWriter, r *http.Request) {
 	parsed, err2 := parser.TextToMetricFamilies(bytes.NewReader(body))
 	mutex.Unlock()
 	if err2 != nil {
-		lgr.Error.Println("Failed to Parse the Node Exporter response")
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		log.Error.Println("Failed to Parse the Node Exporter response")
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to read Node Exporter response."})
 		return
 	}
 
 	if len(parsed[gaugeName].GetMetric()) <= 0 {
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Metric name doesn't exist!"})
-		lgr.Info.Println("Received request for /api/exporter/gauge " +
+		log.Debug.Println("Received request for /api/exporter/gauge " +
 			"but Metric name doesn't exit!")
 		return
 	}
@@ -85,26 +87,29 @@ func NodeExporterQueryGauge(w http.ResponseWriter, r *http.Request) {
 	output := parsed[gaugeName].GetMetric()[0].GetGauge().GetValue()
 	s := fmt.Sprintf("%f", output)
 
-	json.NewEncoder(w).Encode(responses.SuccessResponse{Result: s})
-	lgr.Info.Println("Received request for /api/exporter/gauge responding "+
+	encodeResponse(ctx, w, responses.SuccessResponse{Result: s})
+	log.Debug.Println("Received request for /api/exporter/gauge responding "+
 		"with : ", s)
 }
 
 // NodeExporterQueryCounter to retrieve exporter data.
 func NodeExporterQueryCounter(w http.ResponseWriter, r *http.Request) {
 
-	lgr.Info.Println("Received request for /api/exporter/counter")
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
+	log.Debug.Println("Received request for /api/exporter/counter")
 
 	// Adding header so that receiver knows they are receiving JSON
 	// structure
 	w.Header().Add("Content-Type", "application/json")
 
 	//Get Node Exporter Metrics URl
-	confirmation, exporterConfig := getNodeExporter()
+	confirmation, exporterConfig := getNodeExporter(ctx)
 	if !confirmation  {
 
 		// Stop the code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Node Exporter is not configured!"})
 		return
 	}
@@ -112,17 +117,17 @@ func NodeExporterQueryCounter(w http.ResponseWriter, r *http.Request) {
 	// Setting the counter query
 	counterName := r.URL.Query().Get("counter")
 	if counterName == "" {
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to retrieve counter name!"})
-		lgr.Error.Println(
+		log.Error.Println(
 			"Failed to retrieve counter name, not specified!")
 		return
 	}
 
-	resp, err := http.Get(exporterConfig)
+	resp, err := httpGet(ctx, exporterConfig)
 	if err != nil {
-		lgr.Error.Println("Failed to retrieve Node Exporter data")
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		log.Error.Println("Failed to retrieve Node Exporter data")
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to retrieve Prometheus data check if " +
 				"Node Exporter is enabled!"})
 		return
@@ -133,8 +138,8 @@ func NodeExporterQueryCounter(w http.ResponseWriter, r *http.Request) {
 	// Read the body response of the Node Exporter
 	body, err1 := ioutil.ReadAll(resp.Body)
 	if err1 != nil {
-		lgr.Error.Println("Failed to read the Node Exporter response")
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		log.Error.Println("Failed to read the Node Exporter response")
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to read Node Exporter response."})
 		return
 	}
@@ -148,16 +153,16 @@ func NodeExporterQueryCounter(w http.ResponseWriter, r *http.Request) {
 	mutex.Unlock()
 
 	if err2 != nil {
-		lgr.Error.Println("Failed to Parse the Node Exporter response")
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		log.Error.Println("Failed to Parse the Node Exporter response")
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to Parse Node Exporter response."})
 		return
 	}
 
 	if len(parsed[counterName].GetMetric()) <= 0 {
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Metric name doesn't exist!"})
-		lgr.Info.Println("Received request for /api/exporter/counter " +
+		log.Debug.Println("Received request for /api/exporter/counter " +
 			"but Metric name doesn't exit!")
 		return
 	}
@@ -165,7 +170,7 @@ func NodeExporterQueryCounter(w http.ResponseWriter, r *http.Request) {
 	output := parsed[counterName].GetMetric()[0].GetCounter().GetValue()
 	s := fmt.Sprintf("%f", output)
 
-	json.NewEncoder(w).Encode(responses.SuccessResponse{Result: s})
-	lgr.Info.Println("Received request for /api/exporter/counter "+
+	encodeResponse(ctx, w, responses.SuccessResponse{Result: s})
+	log.Debug.Println("Received request for /api/exporter/counter "+
 		"responding with : ", s)
 }
@@ -2,7 +2,6 @@ package handlers
 
 import (
 	"bytes"
-	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -15,18 +14,21 @@ import (
 
 // PrometheusQueryGauge to retrieve prometheus data.
 func PrometheusQueryGauge(w http.ResponseWriter, r *http.Request) {
-	lgr.Info.Println("Received request for /api/prometheus/gauge")
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
+	log.Debug.Println("Received request for /api/prometheus/gauge")
 
 	// Add header so that received knows they're receiving JSON
 	w.Header().Add("Content-Type", "application/json")
 
 	// Retrieving name of node from query request
 	nodeName := r.URL.Query().Get("name")
-	confirmation, prometheusConfig := checkNodeNamePrometheus(nodeName)
+	confirmation, prometheusConfig := checkNodeNamePrometheus(ctx, nodeName)
 	if !confirmation  {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Node name requested doesn't exist"})
 		return
 	}
@@ -34,18 +36,18 @@ func PrometheusQueryGauge(w http.ResponseWriter, r *http.Request) {
 	// Setting gauge query
 	gaugeName := r.URL.Query().Get("gauge")
 	if gaugeName == "" {
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to retrieve gauge name, please " +
 				"specify!"})
-		lgr.Error.Println("Failed to retrieve gauge name, not " +
+		log.Error.Println("Failed to retrieve gauge name, not " +
 			"specified!")
 		return
 	}
 
-	resp, err := http.Get(prometheusConfig)
+	resp, err := httpGet(ctx, prometheusConfig)
 	if err != nil {
-		lgr.Error.Println("Failed to retrieve Prometheus data")
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		log.Error.Println("Failed to retrieve Prometheus data")
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to retrieve Prometheus data check if " +
 				"Prometheus is enabled!"})
 		return
@@ -56,8 +58,8 @@ func PrometheusQueryGauge(w http.ResponseWriter, r *http.Request) {
 	// Read body response of Prometheus Configuration
 	body, err1 := ioutil.ReadAll(resp.Body)
 	if err1 != nil {
-		lgr.Error.Println("Failed to read Prometheus response")
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		log.Error.Println("Failed to read Prometheus response")
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to read Prometheus response."})
 		return
 	}
@@ -69,9 +71,9 @@ func PrometheusQueryGauge(w http.ResponseWriter, r *http.Request) {
 	parsed, err2 := parser.TextToMetricFamilies(bytes.NewReader(body))
 	mutex.Unlock()
 	if err2 != nil {
-		lgr.Error.Println("Failed to Parse Prometheus response for " +
+		log.Error.Println("Failed to Parse Prometheus response for " +
 			"Gauge : " + gaugeName)
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to Parse Prometheus response."})
 		return
 	}
@@ -79,9 +81,9 @@ func PrometheusQueryGauge(w http.ResponseWriter, r *http.Request) {
 	// Check the length of the metric if it's less than 0 or equal to then
 	// it doesn't exist.
 	if len(parsed[gaugeName].GetMetric()) <= 0 {
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Metric name doesn't exist!"})
-		lgr.Info.Println("Received request for /api/prometheus/gauge " +
+		log.Debug.Println("Received request for /api/prometheus/gauge " +
 			"but Metric name doesn't exit!")
 		return
 	}
@@ -89,26 +91,29 @@ func PrometheusQueryGauge(w http.ResponseWriter, r *http.Request) {
 	output := parsed[gaugeName].GetMetric()[0].GetGauge().GetValue()
 	s := fmt.Sprintf("%f", output)
 
-	json.NewEncoder(w).Encode(responses.SuccessResponse{Result: s})
-	lgr.Info.Println("Received request for /api/prometheus/gauge "+
+	encodeResponse(ctx, w, responses.SuccessResponse{Result: s})
+	log.Debug.Println("Received request for /api/prometheus/gauge "+
 		"responding with : ", s)
 }
 
 // PrometheusQueryCounter to retrieve prometheus data.
 func PrometheusQueryCounter(w http.ResponseWriter, r *http.Request) {
 
-	lgr.Info.Println("Received request for /api/prometheus/counter")
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
+	log.Debug.Println("Received request for /api/prometheus/counter")
 
 	// Add header so that received knows they're receiving JSON
 	w.Header().Add("Content-Type", "application/json")
 
 	// Retrieving name of node from query request
 	nodeName := r.URL.Query().Get("name")
-	confirmation, prometheusConfig := checkNodeNamePrometheus(nodeName)
+	confirmation, prometheusConfig := checkNodeNamePrometheus(ctx, nodeName)
 	if !confirmation  {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Node name requested doesn't exist"})
 		return
 	}
@@ -116,18 +121,18 @@ func PrometheusQueryCounter(w http.ResponseWriter, r *http.Request) {
 	// Setting counter query
 	counterName := r.URL.Query().Get("counter")
 	if counterName == "" {
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to retrieve counter name, please " +
 				"specify!"})
-		lgr.Error.Println("Failed to retrieve counter name, not " +
+		log.Error.Println("Failed to retrieve counter name, not " +
 			"specified!")
 		return
 	}
 
-	resp, err := http.Get(prometheusConfig)
+	resp, err := httpGet(ctx, prometheusConfig)
 	if err != nil {
-		lgr.Error.Println("Failed to retrieve Prometheus data")
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		log.Error.Println("Failed to retrieve Prometheus data")
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to retrieve Prometheus data check if " +
 				"Prometheus is enabled!"})
 		return
@@ -138,8 +143,8 @@ func PrometheusQueryCounter(w http.ResponseWriter, r *http.Request) {
 	// Read body response of Prometheus Configuration
 	body, err1 := ioutil.ReadAll(resp.Body)
 	if err1 != nil {
-		lgr.Error.Println("Failed to read Prometheus response")
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		log.Error.Println("Failed to read Prometheus response")
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to read Prometheus response."})
 		return
 	}
@@ -152,16 +157,16 @@ func PrometheusQueryCounter(w http.ResponseWriter, r *http.Request) {
 	parsed, err2 := parser.TextToMetricFamilies(bytes.NewReader(body))
 	mutex.Unlock()
 	if err2 != nil {
-		lgr.Error.Println("Failed to Parse Prometheus response for " +
+		log.Error.Println("Failed to Parse Prometheus response for " +
 			"Counter : " + counterName)
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to Parse Prometheus response."})
 	}
 
 	if len(parsed[counterName].GetMetric()) <= 0 {
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Metric name doesn't exist!"})
-		lgr.Info.Println(
+		log.Debug.Println(
 			"Received request for /api/prometheus/counter but " +
 				"Metric name doesn't exit!")
 		return
@@ -170,7 +175,7 @@ func PrometheusQueryCounter(w http.ResponseWriter, r *http.Request) {
 	output := parsed[counterName].GetMetric()[0].GetCounter().GetValue()
 	s := fmt.Sprintf("%f", output)
 
-	json.NewEncoder(w).Encode(responses.SuccessResponse{Result: s})
-	lgr.Info.Println("Received request for /api/prometheus/counter "+
+	encodeResponse(ctx, w, responses.SuccessResponse{Result: s})
+	log.Debug.Println("Received request for /api/prometheus/counter "+
 		"responding with : ", s)
 }
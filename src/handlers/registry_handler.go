@@ -2,27 +2,40 @@ package handlers
 
 import (
 	"context"
-	"encoding/json"
+	"encoding/hex"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"sync"
 
 	"google.golang.org/grpc"
 
 	lgr "github.com/SimplyVC/oasis_api_server/src/logger"
 	"github.com/SimplyVC/oasis_api_server/src/responses"
 	"github.com/SimplyVC/oasis_api_server/src/rpc"
+	tmcrypto "github.com/cometbft/cometbft/crypto"
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
 	common_namespace "github.com/oasisprotocol/oasis-core/go/common"
 	common_signature "github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	common_node "github.com/oasisprotocol/oasis-core/go/common/node"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
 	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
 )
 
 // loadRegistryClient loads registry client and returns it
-func loadRegistryClient(socket string) (*grpc.ClientConn, registry.Backend) {
+func loadRegistryClient(ctx context.Context, socket string) (
+	*grpc.ClientConn, registry.Backend) {
+
+	log := lgr.FromContext(ctx)
 
 	// Attempt to load connection with registry client
+	span := startConnect(ctx, "registry", socket)
 	connection, registryClient, err := rpc.RegistryClient(socket)
+	endConnect(ctx, span, connection, err)
 	if err != nil {
-		lgr.Error.Println("Failed to establish connection to registry"+
+		log.Error.Println("Failed to establish connection to registry"+
 			" client: ", err)
 		return nil, nil
 	}
@@ -35,59 +48,62 @@ func GetEntities(w http.ResponseWriter, r *http.Request) {
 	// Add header so that received knows they're receiving JSON
 	w.Header().Add("Content-Type", "application/json")
 
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
 	// Retrieving name of node from query request
 	nodeName := r.URL.Query().Get("name")
-	confirmation, socket := checkNodeName(nodeName)
+	confirmation, socket := checkNodeName(ctx, nodeName)
 	if !confirmation {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Node name requested doesn't exist"})
 		return
 	}
 
 	// Retrieving height from query request
 	recvHeight := r.URL.Query().Get("height")
-	height := checkHeight(recvHeight)
+	height := checkHeight(ctx, recvHeight)
 	if height == -1 {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Unexpected value found, height needs to be " +
 				"a string representing an int!"})
 		return
 	}
 
 	// Attempt to load connection with registry client
-	connection, ro := loadRegistryClient(socket)
+	connection, ro := loadRegistryClient(ctx, socket)
 
 	// Close connection once code underneath executes
-	defer connection.Close()
+	defer closeConnection(connection)
 
 	// If null object was retrieved send response
 	if ro == nil {
 
 		// Stop code here faild to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to establish connection using socket: " +
 				socket})
 		return
 	}
 
 	// Retrieve entities at specific block height
-	entities, err := ro.GetEntities(context.Background(), height)
+	entities, err := ro.GetEntities(ctx, height)
 	if err != nil {
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to get entities!"})
-		lgr.Error.Println("Request at /api/registry/entities failed "+
+		log.Error.Println("Request at /api/registry/entities failed "+
 			"to retrieve entities : ", err)
 		return
 	}
 
 	// Responding with retrieved entities
-	lgr.Info.Println("Request at /api/registry/entities responding with" +
+	log.Debug.Println("Request at /api/registry/entities responding with" +
 		" entities!")
-	json.NewEncoder(w).Encode(responses.EntitiesResponse{
+	encodeResponse(ctx, w, responses.EntitiesResponse{
 		Entities: entities})
 }
 
@@ -97,60 +113,63 @@ func GetNodes(w http.ResponseWriter, r *http.Request) {
 	// Add header so that received knows they're receiving JSON
 	w.Header().Add("Content-Type", "application/json")
 
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
 	// Retrieving name of node from query request
 	nodeName := r.URL.Query().Get("name")
-	confirmation, socket := checkNodeName(nodeName)
+	confirmation, socket := checkNodeName(ctx, nodeName)
 	if !confirmation {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Node name requested doesn't exist"})
 		return
 	}
 
 	// Retrieving height from query
 	recvHeight := r.URL.Query().Get("height")
-	height := checkHeight(recvHeight)
+	height := checkHeight(ctx, recvHeight)
 	if height == -1 {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Unexpected value found, height needs to be " +
 				"a string representing an int!"})
 		return
 	}
 
 	// Attempt to load connection with registry client
-	connection, ro := loadRegistryClient(socket)
+	connection, ro := loadRegistryClient(ctx, socket)
 
 	// Close connection once code underneath executes
-	defer connection.Close()
+	defer closeConnection(connection)
 
 	// If null object was retrieved send response
 	if ro == nil {
 
 		// Stop code here faild to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to establish connection using socket: " +
 				socket})
 		return
 	}
 
 	// Retrieve nodes from Registry object at specific height
-	nodes, err := ro.GetNodes(context.Background(), height)
+	nodes, err := ro.GetNodes(ctx, height)
 	if err != nil {
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to get Nodes!"})
-		lgr.Error.Println(
+		log.Error.Println(
 			"Request at /api/registry/nodes failed to retrieve "+
 				"nodes : ", err)
 		return
 	}
 
 	// Respond with all nodes retrieved above
-	lgr.Info.Println(
+	log.Debug.Println(
 		"Request at /api/registry/nodes responding with Nodes!")
-	json.NewEncoder(w).Encode(responses.NodesResponse{Nodes: nodes})
+	encodeResponse(ctx, w, responses.NodesResponse{Nodes: nodes})
 }
 
 // GetRegistryEvents returns the events at specified block height.
@@ -159,60 +178,63 @@ func GetRegistryEvents(w http.ResponseWriter, r *http.Request) {
 	// Add header so that received knows they're receiving JSON
 	w.Header().Add("Content-Type", "application/json")
 
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
 	// Retrieving name of node from query request
 	nodeName := r.URL.Query().Get("name")
-	confirmation, socket := checkNodeName(nodeName)
+	confirmation, socket := checkNodeName(ctx, nodeName)
 	if !confirmation {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Node name requested doesn't exist"})
 		return
 	}
 
 	// Retrieving height from query
 	recvHeight := r.URL.Query().Get("height")
-	height := checkHeight(recvHeight)
+	height := checkHeight(ctx, recvHeight)
 	if height == -1 {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Unexpected value found, height needs to be " +
 				"a string representing an int!"})
 		return
 	}
 
 	// Attempt to load connection with registry client
-	connection, ro := loadRegistryClient(socket)
+	connection, ro := loadRegistryClient(ctx, socket)
 
 	// Close connection once code underneath executes
-	defer connection.Close()
+	defer closeConnection(connection)
 
 	// If null object was retrieved send response
 	if ro == nil {
 
 		// Stop code here faild to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to establish connection using socket: " +
 				socket})
 		return
 	}
 
 	// Retrieve the events at specified block height.
-	events, err := ro.GetEvents(context.Background(), height)
+	events, err := ro.GetEvents(ctx, height)
 	if err != nil {
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to get Events!"})
-		lgr.Error.Println(
+		log.Error.Println(
 			"Request at /api/registry/events failed to retrieve "+
 				"events : ", err)
 		return
 	}
 
 	// Respond with events retrieved at height
-	lgr.Info.Println(
+	log.Debug.Println(
 		"Request at /api/registry/events responding with Events!")
-	json.NewEncoder(w).Encode(responses.RegistryEventsResponse{Events: events})
+	encodeResponse(ctx, w, responses.RegistryEventsResponse{Events: events})
 }
 
 // GetRuntimes returns all runtimes at specific block height
@@ -221,24 +243,27 @@ func GetRuntimes(w http.ResponseWriter, r *http.Request) {
 	// Add header so that received knows they're receiving JSON
 	w.Header().Add("Content-Type", "application/json")
 
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
 	// Retrieving name of node from query request
 	nodeName := r.URL.Query().Get("name")
-	confirmation, socket := checkNodeName(nodeName)
+	confirmation, socket := checkNodeName(ctx, nodeName)
 	if !confirmation {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Node name requested doesn't exist"})
 		return
 	}
 
 	// Retrieving height from query
 	recvHeight := r.URL.Query().Get("height")
-	height := checkHeight(recvHeight)
+	height := checkHeight(ctx, recvHeight)
 	if height == -1 {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Unexpected value found, height needs to be " +
 				"a string representing an int!"})
 		return
@@ -250,16 +275,16 @@ func GetRuntimes(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Attempt to load connection with registry client
-	connection, ro := loadRegistryClient(socket)
+	connection, ro := loadRegistryClient(ctx, socket)
 
 	// Close connection once code underneath executes
-	defer connection.Close()
+	defer closeConnection(connection)
 
 	// If null object was retrieved send response
 	if ro == nil {
 
 		// Stop code here faild to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to establish connection using socket: " +
 				socket})
 		return
@@ -269,20 +294,20 @@ func GetRuntimes(w http.ResponseWriter, r *http.Request) {
 		IncludeSuspended: suspendedBool}
 
 	// Retrieving runtimes at specific block height from registry client
-	runtimes, err := ro.GetRuntimes(context.Background(), &query)
+	runtimes, err := ro.GetRuntimes(ctx, &query)
 	if err != nil {
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to get runtimes!"})
-		lgr.Error.Println(
+		log.Error.Println(
 			"Request at /api/registry/runtimes failed to "+
 				"retrieve runtimes : ", err)
 		return
 	}
 
 	// Responding with runtimes returned above
-	lgr.Info.Println("Request at /api/registry/runtimes responding " +
+	log.Debug.Println("Request at /api/registry/runtimes responding " +
 		"with runtimes!")
-	json.NewEncoder(w).Encode(responses.RuntimesResponse{
+	encodeResponse(ctx, w, responses.RuntimesResponse{
 		Runtimes: runtimes})
 }
 
@@ -293,61 +318,64 @@ func GetRegistryStateToGenesis(w http.ResponseWriter, r *http.Request) {
 	// Add header so that received knows they're receiving JSON
 	w.Header().Add("Content-Type", "application/json")
 
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
 	// Retrieving name of node from query request
 	nodeName := r.URL.Query().Get("name")
-	confirmation, socket := checkNodeName(nodeName)
+	confirmation, socket := checkNodeName(ctx, nodeName)
 	if !confirmation {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Node name requested doesn't exist"})
 		return
 	}
 
 	// Retrieving height from query request
 	recvHeight := r.URL.Query().Get("height")
-	height := checkHeight(recvHeight)
+	height := checkHeight(ctx, recvHeight)
 	if height == -1 {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Unexpected value found, height needs to be " +
 				"a string representing an int!"})
 		return
 	}
 
 	// Attempt to load connection with registry client
-	connection, ro := loadRegistryClient(socket)
+	connection, ro := loadRegistryClient(ctx, socket)
 
 	// Close connection once code underneath executes
-	defer connection.Close()
+	defer closeConnection(connection)
 
 	// If null object was retrieved send response
 	if ro == nil {
 
 		// Stop code here faild to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to establish connection using socket: " +
 				socket})
 		return
 	}
 
 	// Retrieving genesis state of registry object
-	genesisRegistry, err := ro.StateToGenesis(context.Background(), height)
+	genesisRegistry, err := ro.StateToGenesis(ctx, height)
 	if err != nil {
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to get Registry Genesis!"})
-		lgr.Error.Println(
+		log.Error.Println(
 			"Request at /api/registry/genesis failed to retrieve"+
 				" Registry Genesis : ", err)
 		return
 	}
 
 	// Responding with genesis state retrieved above
-	lgr.Info.Println(
+	log.Debug.Println(
 		"Request at /api/registry/genesis responding with Registry" +
 			" Genesis!")
-	json.NewEncoder(w).Encode(responses.RegistryGenesisResponse{
+	encodeResponse(ctx, w, responses.RegistryGenesisResponse{
 		GenesisRegistry: genesisRegistry})
 }
 
@@ -357,24 +385,27 @@ func GetEntity(w http.ResponseWriter, r *http.Request) {
 	// Add header so that received knows they're receiving JSON
 	w.Header().Add("Content-Type", "application/json")
 
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
 	// Retrieving name of node from query request
 	nodeName := r.URL.Query().Get("name")
-	confirmation, socket := checkNodeName(nodeName)
+	confirmation, socket := checkNodeName(ctx, nodeName)
 	if !confirmation {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Node name requested doesn't exist"})
 		return
 	}
 
 	// Retrieving height from query request
 	recvHeight := r.URL.Query().Get("height")
-	height := checkHeight(recvHeight)
+	height := checkHeight(ctx, recvHeight)
 	if height == -1 {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Unexpected value found, height needs to be " +
 				"a string representing an int!"})
 		return
@@ -386,9 +417,9 @@ func GetEntity(w http.ResponseWriter, r *http.Request) {
 	if len(entityID) == 0 {
 
 		// Stop code here no need to establish connection and reply
-		lgr.Warning.Println("Request at /api/registry/entity failed," +
+		log.Warning.Println("Request at /api/registry/entity failed," +
 			" EntityID can't be empty!")
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "EntityID can't be empty!"})
 		return
 	}
@@ -396,24 +427,24 @@ func GetEntity(w http.ResponseWriter, r *http.Request) {
 	// Unmarshal text into public key
 	err := pubKey.UnmarshalText([]byte(entityID))
 	if err != nil {
-		lgr.Error.Println(
+		log.Error.Println(
 			"Failed to UnmarshalText into Public Key", err)
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to UnmarshalText into Public Key."})
 		return
 	}
 
 	// Attempt to load connection with registry client
-	connection, ro := loadRegistryClient(socket)
+	connection, ro := loadRegistryClient(ctx, socket)
 
 	// Close connection once code underneath executes
-	defer connection.Close()
+	defer closeConnection(connection)
 
 	// If null object was retrieved send response
 	if ro == nil {
 
 		// Stop code here faild to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to establish connection using socket: " +
 				socket})
 		return
@@ -424,19 +455,19 @@ func GetEntity(w http.ResponseWriter, r *http.Request) {
 
 	// Retrieve Entity and it's information from Registry
 	// client using above query.
-	registryEntity, err := ro.GetEntity(context.Background(), &query)
+	registryEntity, err := ro.GetEntity(ctx, &query)
 	if err != nil {
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to get Registry Entity!"})
-		lgr.Error.Println("Request at /api/registry/entity failed to"+
+		log.Error.Println("Request at /api/registry/entity failed to"+
 			" retrieve Registry Entity : ", err)
 		return
 	}
 
 	// Responding with Entity object retrieved above
-	lgr.Info.Println("Request at /api/registry/entity responding with" +
+	log.Debug.Println("Request at /api/registry/entity responding with" +
 		" Registry Entity!")
-	json.NewEncoder(w).Encode(responses.RegistryEntityResponse{
+	encodeResponse(ctx, w, responses.RegistryEntityResponse{
 		Entity: registryEntity})
 }
 
@@ -446,24 +477,27 @@ func GetNode(w http.ResponseWriter, r *http.Request) {
 	// Add header so that received knows they're receiving JSON
 	w.Header().Add("Content-Type", "application/json")
 
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
 	// Retrieving name of node from query request
 	nodeName := r.URL.Query().Get("name")
-	confirmation, socket := checkNodeName(nodeName)
+	confirmation, socket := checkNodeName(ctx, nodeName)
 	if !confirmation {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Node name requested doesn't exist"})
 		return
 	}
 
 	// Retrieving height from query
 	recvHeight := r.URL.Query().Get("height")
-	height := checkHeight(recvHeight)
+	height := checkHeight(ctx, recvHeight)
 	if height == -1 {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Unexpected value found, height needs to be " +
 				"a string representing an int!"})
 		return
@@ -477,9 +511,9 @@ func GetNode(w http.ResponseWriter, r *http.Request) {
 	if len(nodeID) == 0 {
 
 		// Stop code here no need to establish connection and reply
-		lgr.Warning.Println("Request at /api/registry/node failed, " +
+		log.Warning.Println("Request at /api/registry/node failed, " +
 			"NodeID can't be empty!")
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "NodeID can't be empty!"})
 		return
 	}
@@ -487,24 +521,24 @@ func GetNode(w http.ResponseWriter, r *http.Request) {
 	// Unmarshal received text into public key object
 	err := pubKey.UnmarshalText([]byte(nodeID))
 	if err != nil {
-		lgr.Error.Println(
+		log.Error.Println(
 			"Failed to UnmarshalText into Public Key", err)
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to UnmarshalText into Public Key."})
 		return
 	}
 
 	// Attempt to load connection with registry client
-	connection, ro := loadRegistryClient(socket)
+	connection, ro := loadRegistryClient(ctx, socket)
 
 	// Close connection once code underneath executes
-	defer connection.Close()
+	defer closeConnection(connection)
 
 	// If null object was retrieved send response
 	if ro == nil {
 
 		// Stop code here faild to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to establish connection using socket: " +
 				socket})
 		return
@@ -514,19 +548,19 @@ func GetNode(w http.ResponseWriter, r *http.Request) {
 	query := registry.IDQuery{Height: height, ID: pubKey}
 
 	// Retriveing node object using above query
-	registryNode, err := ro.GetNode(context.Background(), &query)
+	registryNode, err := ro.GetNode(ctx, &query)
 	if err != nil {
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to get Registry Node!"})
-		lgr.Error.Println("Request at /api/registry/node failed to "+
+		log.Error.Println("Request at /api/registry/node failed to "+
 			"retrieve Registry Node : ", err)
 		return
 	}
 
 	// Responding with retrieved node object
-	lgr.Info.Println("Request at /api/registry/node responding with " +
+	log.Debug.Println("Request at /api/registry/node responding with " +
 		"Registry Node!")
-	json.NewEncoder(w).Encode(responses.RegistryNodeResponse{
+	encodeResponse(ctx, w, responses.RegistryNodeResponse{
 		Node: registryNode})
 }
 
@@ -536,24 +570,27 @@ func GetNodeStatus(w http.ResponseWriter, r *http.Request) {
 	// Add header so that received knows they're receiving JSON
 	w.Header().Add("Content-Type", "application/json")
 
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
 	// Retrieving name of node from query request
 	nodeName := r.URL.Query().Get("name")
-	confirmation, socket := checkNodeName(nodeName)
+	confirmation, socket := checkNodeName(ctx, nodeName)
 	if !confirmation {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Node name requested doesn't exist"})
 		return
 	}
 
 	// Retrieving height from query
 	recvHeight := r.URL.Query().Get("height")
-	height := checkHeight(recvHeight)
+	height := checkHeight(ctx, recvHeight)
 	if height == -1 {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Unexpected value found, height needs to be " +
 				"a string representing an int!"})
 		return
@@ -567,9 +604,9 @@ func GetNodeStatus(w http.ResponseWriter, r *http.Request) {
 	if len(nodeID) == 0 {
 
 		// Stop code here no need to establish connection and reply
-		lgr.Warning.Println("Request at /api/registry/node failed, " +
+		log.Warning.Println("Request at /api/registry/node failed, " +
 			"NodeID can't be empty!")
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "NodeID can't be empty!"})
 		return
 	}
@@ -577,24 +614,24 @@ func GetNodeStatus(w http.ResponseWriter, r *http.Request) {
 	// Unmarshal received text into public key object
 	err := pubKey.UnmarshalText([]byte(nodeID))
 	if err != nil {
-		lgr.Error.Println(
+		log.Error.Println(
 			"Failed to UnmarshalText into Public Key", err)
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to UnmarshalText into Public Key."})
 		return
 	}
 
 	// Attempt to load connection with registry client
-	connection, ro := loadRegistryClient(socket)
+	connection, ro := loadRegistryClient(ctx, socket)
 
 	// Close connection once code underneath executes
-	defer connection.Close()
+	defer closeConnection(connection)
 
 	// If null object was retrieved send response
 	if ro == nil {
 
 		// Stop code here faild to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to establish connection using socket: " +
 				socket})
 		return
@@ -604,19 +641,19 @@ func GetNodeStatus(w http.ResponseWriter, r *http.Request) {
 	query := registry.IDQuery{Height: height, ID: pubKey}
 
 	// Retriveing a node's status.
-	nodeStatus, err := ro.GetNodeStatus(context.Background(), &query)
+	nodeStatus, err := ro.GetNodeStatus(ctx, &query)
 	if err != nil {
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to get Node Status!"})
-		lgr.Error.Println("Request at /api/registry/nodestatus failed to "+
+		log.Error.Println("Request at /api/registry/nodestatus failed to "+
 			"retrieve Node Status: ", err)
 		return
 	}
 
 	// Responding with retrieved node object
-	lgr.Info.Println("Request at /api/registry/nodestatus responding with " +
+	log.Debug.Println("Request at /api/registry/nodestatus responding with " +
 		"Node Status!")
-	json.NewEncoder(w).Encode(responses.NodeStatusResponse{
+	encodeResponse(ctx, w, responses.NodeStatusResponse{
 		NodeStatus: nodeStatus})
 }
 
@@ -626,24 +663,27 @@ func GetRuntime(w http.ResponseWriter, r *http.Request) {
 	// Add header so that received knows they're receiving JSON
 	w.Header().Add("Content-Type", "application/json")
 
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
 	// Retrieving name of node from query request
 	nodeName := r.URL.Query().Get("name")
-	confirmation, socket := checkNodeName(nodeName)
+	confirmation, socket := checkNodeName(ctx, nodeName)
 	if !confirmation {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Node name requested doesn't exist"})
 		return
 	}
 
 	// Retrieve height from query
 	recvHeight := r.URL.Query().Get("height")
-	height := checkHeight(recvHeight)
+	height := checkHeight(ctx, recvHeight)
 	if height == -1 {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Unexpected value found, height needs to be " +
 				"a string representing an int!"})
 		return
@@ -656,9 +696,9 @@ func GetRuntime(w http.ResponseWriter, r *http.Request) {
 	nmspace := r.URL.Query().Get("namespace")
 	if len(nmspace) == 0 {
 		// Stop code here no need to establish connection and reply
-		lgr.Warning.Println("Request at /api/registry/runtime failed" +
+		log.Warning.Println("Request at /api/registry/runtime failed" +
 			", namespace can't be empty!")
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "namespace can't be empty!"})
 		return
 	}
@@ -666,23 +706,23 @@ func GetRuntime(w http.ResponseWriter, r *http.Request) {
 	// Unmarshal received text into namespace object
 	err := nameSpace.UnmarshalText([]byte(nmspace))
 	if err != nil {
-		lgr.Error.Println("Failed to UnmarshalText into Namespace", err)
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		log.Error.Println("Failed to UnmarshalText into Namespace", err)
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to UnmarshalText into Namespace."})
 		return
 	}
 
 	// Attempt to load connection with registry client
-	connection, ro := loadRegistryClient(socket)
+	connection, ro := loadRegistryClient(ctx, socket)
 
 	// Close connection once code underneath executes
-	defer connection.Close()
+	defer closeConnection(connection)
 
 	// If null object was retrieved send response
 	if ro == nil {
 
 		// Stop code here faild to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to establish connection using socket: " +
 				socket})
 		return
@@ -692,18 +732,708 @@ func GetRuntime(w http.ResponseWriter, r *http.Request) {
 	query := registry.GetRuntimeQuery{Height: height, ID: nameSpace}
 
 	// Retrieving runtime object using above query
-	registryRuntime, err := ro.GetRuntime(context.Background(), &query)
+	registryRuntime, err := ro.GetRuntime(ctx, &query)
 	if err != nil {
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to get Registry Runtime!"})
-		lgr.Error.Println("Request at /api/registry/runtime failed "+
+		log.Error.Println("Request at /api/registry/runtime failed "+
 			"to retrieve Registry Runtime : ", err)
 		return
 	}
 
 	// Responding with runtime object retrieved above
-	lgr.Info.Println("Request at /api/registry/runtime responding with " +
+	log.Debug.Println("Request at /api/registry/runtime responding with " +
 		"Registry Runtime!")
-	json.NewEncoder(w).Encode(responses.RuntimeResponse{
+	encodeResponse(ctx, w, responses.RuntimeResponse{
 		Runtime: registryRuntime})
 }
+
+// GetEntityOverview returns the nodes of an entity together with their
+// roles, runtimes, addresses, registration expiry, freeze status and
+// validator set membership, so that an entity's registrations can be checked
+// in a single request.
+func GetEntityOverview(w http.ResponseWriter, r *http.Request) {
+
+	// Add header so that received knows they're receiving JSON
+	w.Header().Add("Content-Type", "application/json")
+
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
+	// Retrieving name of node from query request
+	nodeName := r.URL.Query().Get("name")
+	confirmation, socket := checkNodeName(ctx, nodeName)
+	if !confirmation {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Node name requested doesn't exist"})
+		return
+	}
+
+	// Retrieving height from query request
+	recvHeight := r.URL.Query().Get("height")
+	height := checkHeight(ctx, recvHeight)
+	if height == -1 {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Unexpected value found, height needs to be " +
+				"a string representing an int!"})
+		return
+	}
+
+	// Create public key object and retrieve entity from query
+	var pubKey common_signature.PublicKey
+	entityID := r.URL.Query().Get("entity")
+	if len(entityID) == 0 {
+
+		// Stop code here no need to establish connection and reply
+		log.Warning.Println("Request at /api/registry/entityoverview " +
+			"failed, EntityID can't be empty!")
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "EntityID can't be empty!"})
+		return
+	}
+
+	// Unmarshal text into public key
+	err := pubKey.UnmarshalText([]byte(entityID))
+	if err != nil {
+		log.Error.Println(
+			"Failed to UnmarshalText into Public Key", err)
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to UnmarshalText into Public Key."})
+		return
+	}
+
+	// Attempt to load connection with registry client
+	connection, ro := loadRegistryClient(ctx, socket)
+
+	// Close connection once code underneath executes
+	defer closeConnection(connection)
+
+	// If null object was retrieved send response
+	if ro == nil {
+
+		// Stop code here faild to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to establish connection using socket: " +
+				socket})
+		return
+	}
+
+	// Attempt to load connection with scheduler client
+	schedulerConnection, so := loadSchedulerClient(ctx, socket)
+
+	// Close connection once code underneath executes
+	defer closeConnection(schedulerConnection)
+
+	// If null object was retrieved send response
+	if so == nil {
+
+		// Stop code here faild to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to establish connection using socket: " +
+				socket})
+		return
+	}
+
+	// Attempt to load connection with consensus client
+	consensusConnection, co := loadConsensusClient(ctx, socket)
+
+	// Close connection once code underneath executes
+	defer closeConnection(consensusConnection)
+
+	// If null object was retrieved send response
+	if co == nil {
+
+		// Stop code here faild to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to establish connection using socket: " +
+				socket})
+		return
+	}
+
+	// Retrieve Entity and it's information from Registry
+	query := registry.IDQuery{Height: height, ID: pubKey}
+	registryEntity, err := ro.GetEntity(ctx, &query)
+	if err != nil {
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to get Registry Entity!"})
+		log.Error.Println("Request at /api/registry/entityoverview failed"+
+			" to retrieve Registry Entity : ", err)
+		return
+	}
+
+	// Retrieve all registered nodes to find the nodes of the entity
+	nodes, err := ro.GetNodes(ctx, height)
+	if err != nil {
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to get Nodes!"})
+		log.Error.Println("Request at /api/registry/entityoverview failed"+
+			" to retrieve Nodes : ", err)
+		return
+	}
+
+	// Retrieve the validator set to check the membership of the nodes
+	validators, err := so.GetValidators(ctx, height)
+	if err != nil {
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to get Validators!"})
+		log.Error.Println("Request at /api/registry/entityoverview failed"+
+			" to retrieve Validators : ", err)
+		return
+	}
+
+	// Retrieve the epoch that registration expiry is compared against
+	epoch, err := co.Beacon().GetEpoch(ctx, height)
+	if err != nil {
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to get Epoch!"})
+		log.Error.Println("Request at /api/registry/entityoverview failed"+
+			" to retrieve Epoch : ", err)
+		return
+	}
+
+	votingPowers := make(map[common_signature.PublicKey]int64)
+	for _, validator := range validators {
+		votingPowers[validator.ID] = validator.VotingPower
+	}
+
+	overview := responses.EntityOverview{
+		Entity:            registryEntity,
+		CurrentEpoch:      epoch,
+		Nodes:             []responses.NodeOverview{},
+		UnregisteredNodes: []common_signature.PublicKey{},
+	}
+	registered := make(map[common_signature.PublicKey]bool)
+	for _, node := range nodes {
+		if !node.EntityID.Equal(pubKey) {
+			continue
+		}
+		registered[node.ID] = true
+
+		// Retrieve the node's status to report whether it is frozen
+		nodeStatus, err := ro.GetNodeStatus(ctx,
+			&registry.IDQuery{Height: height, ID: node.ID})
+		if err != nil {
+			encodeResponse(ctx, w, responses.ErrorResponse{
+				Error: "Failed to get Node Status!"})
+			log.Error.Println("Request at /api/registry/entityoverview "+
+				"failed to retrieve Node Status : ", err)
+			return
+		}
+
+		votingPower, inValidatorSet := votingPowers[node.ID]
+		overview.Nodes = append(overview.Nodes, nodeOverview(
+			node, nodeStatus, epoch, inValidatorSet, votingPower))
+	}
+
+	// Nodes the entity lists that have no registration at this height
+	for _, nodeID := range registryEntity.Nodes {
+		if !registered[nodeID] {
+			overview.UnregisteredNodes = append(
+				overview.UnregisteredNodes, nodeID)
+		}
+	}
+
+	// Responding with the overview of the entity's nodes
+	log.Debug.Println("Request at /api/registry/entityoverview responding" +
+		" with Entity Overview!")
+	encodeResponse(ctx, w, responses.EntityOverviewResponse{
+		Overview: &overview})
+}
+
+// nodeOverview summarises a node's registration, comparing its expiry with
+// the current epoch
+func nodeOverview(node *common_node.Node, status *registry.NodeStatus,
+	epoch beacon.EpochTime, inValidatorSet bool,
+	votingPower int64) responses.NodeOverview {
+
+	overview := responses.NodeOverview{
+		ID:                 node.ID,
+		Roles:              []string{},
+		Runtimes:           []common_namespace.Namespace{},
+		TLSPubKey:          node.TLS.PubKey,
+		P2PID:              node.P2P.ID,
+		P2PAddresses:       []string{},
+		ConsensusID:        node.Consensus.ID,
+		ConsensusAddresses: []string{},
+		Expiration:         node.Expiration,
+		EpochsUntilExpiry:  int64(node.Expiration) - int64(epoch),
+		Expired:            node.IsExpired(uint64(epoch)),
+		Frozen:             status.IsFrozen(),
+		FreezeEndTime:      status.FreezeEndTime,
+		InValidatorSet:     inValidatorSet,
+		VotingPower:        votingPower,
+	}
+	if node.Roles != 0 {
+		overview.Roles = strings.Split(node.Roles.String(), ",")
+	}
+	for _, runtime := range node.Runtimes {
+		overview.Runtimes = append(overview.Runtimes, runtime.ID)
+	}
+	for _, address := range node.P2P.Addresses {
+		overview.P2PAddresses = append(overview.P2PAddresses,
+			address.String())
+	}
+	for _, address := range node.Consensus.Addresses {
+		overview.ConsensusAddresses = append(overview.ConsensusAddresses,
+			address.String())
+	}
+	return overview
+}
+
+// nodeKeyMatch is a registered node together with the kind of key that
+// identified it
+type nodeKeyMatch struct {
+	node      *common_node.Node
+	matchedBy string
+}
+
+// nodeKeyIndex indexes registered nodes by every public key they are known
+// by, since keys are unique a key only ever identifies a single node. Keys a
+// node doesn't have, such as the VRF key of nodes that aren't validators,
+// are left zero and aren't indexed.
+func nodeKeyIndex(
+	nodes []*common_node.Node) map[common_signature.PublicKey]nodeKeyMatch {
+
+	index := make(map[common_signature.PublicKey]nodeKeyMatch)
+	add := func(key common_signature.PublicKey, node *common_node.Node,
+		keyType string) {
+
+		if key != (common_signature.PublicKey{}) {
+			index[key] = nodeKeyMatch{node, keyType}
+		}
+	}
+	for _, node := range nodes {
+		add(node.VRF.ID, node, "vrf_id")
+		add(node.TLS.PubKey, node, "tls_pub_key")
+		add(node.P2P.ID, node, "p2p_id")
+		add(node.Consensus.ID, node, "consensus_id")
+		add(node.ID, node, "node_id")
+	}
+	return index
+}
+
+// GetNodeLookup resolves a node from any of its public keys, or from the
+// Tendermint address of its consensus key, and returns the node descriptor
+// with its owning entity.
+func GetNodeLookup(w http.ResponseWriter, r *http.Request) {
+
+	// Add header so that received knows they're receiving JSON
+	w.Header().Add("Content-Type", "application/json")
+
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
+	// Retrieving name of node from query request
+	nodeName := r.URL.Query().Get("name")
+	confirmation, socket := checkNodeName(ctx, nodeName)
+	if !confirmation {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Node name requested doesn't exist"})
+		return
+	}
+
+	// Retrieving height from query request
+	recvHeight := r.URL.Query().Get("height")
+	height := checkHeight(ctx, recvHeight)
+	if height == -1 {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Unexpected value found, height needs to be " +
+				"a string representing an int!"})
+		return
+	}
+
+	// Retrieving key or Tendermint address from query, exactly one of
+	// them needs to be given
+	key := r.URL.Query().Get("key")
+	address := r.URL.Query().Get("address")
+	if (len(key) == 0) == (len(address) == 0) {
+
+		// Stop code here no need to establish connection and reply
+		log.Warning.Println("Request at /api/registry/nodelookup failed, " +
+			"either a key or an address is required!")
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Either a key or an address is required!"})
+		return
+	}
+
+	// Unmarshal text into public key or decode the hex Tendermint address
+	var pubKey common_signature.PublicKey
+	var consensusAddress []byte
+	if len(key) != 0 {
+		err := pubKey.UnmarshalText([]byte(key))
+		if err != nil {
+			log.Error.Println(
+				"Failed to UnmarshalText into Public Key", err)
+			encodeResponse(ctx, w, responses.ErrorResponse{
+				Error: "Failed to UnmarshalText into Public Key."})
+			return
+		}
+	} else {
+		decoded, err := hex.DecodeString(address)
+		if err != nil || len(decoded) != tmcrypto.AddressSize {
+			log.Error.Println("Failed to decode Tendermint Address", err)
+			encodeResponse(ctx, w, responses.ErrorResponse{
+				Error: "Unexpected value found, address needs to be " +
+					"a hex encoded Tendermint address!"})
+			return
+		}
+		consensusAddress = decoded
+	}
+
+	// Attempt to load connection with registry client
+	connection, ro := loadRegistryClient(ctx, socket)
+
+	// Close connection once code underneath executes
+	defer closeConnection(connection)
+
+	// If null object was retrieved send response
+	if ro == nil {
+
+		// Stop code here faild to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to establish connection using socket: " +
+				socket})
+		return
+	}
+
+	var match nodeKeyMatch
+	if consensusAddress != nil {
+
+		// Tendermint addresses are resolved by the registry itself
+		node, err := ro.GetNodeByConsensusAddress(ctx,
+			&registry.ConsensusAddressQuery{Height: height,
+				Address: consensusAddress})
+		if errors.Is(err, registry.ErrNoSuchNode) {
+			encodeResponse(ctx, w, responses.ErrorResponse{
+				Error: "Node not found!"})
+			return
+		}
+		if err != nil {
+			encodeResponse(ctx, w, responses.ErrorResponse{
+				Error: "Failed to get Node!"})
+			log.Error.Println("Request at /api/registry/nodelookup "+
+				"failed to retrieve Node : ", err)
+			return
+		}
+		match = nodeKeyMatch{node, "tendermint_address"}
+	} else {
+
+		// Keys are resolved against an index of all registered nodes
+		nodes, err := ro.GetNodes(ctx, height)
+		if err != nil {
+			encodeResponse(ctx, w, responses.ErrorResponse{
+				Error: "Failed to get Nodes!"})
+			log.Error.Println("Request at /api/registry/nodelookup "+
+				"failed to retrieve Nodes : ", err)
+			return
+		}
+		found, ok := nodeKeyIndex(nodes)[pubKey]
+		if !ok {
+			encodeResponse(ctx, w, responses.ErrorResponse{
+				Error: "Node not found!"})
+			return
+		}
+		match = found
+	}
+
+	// Retrieve the entity owning the node
+	query := registry.IDQuery{Height: height, ID: match.node.EntityID}
+	registryEntity, err := ro.GetEntity(ctx, &query)
+	if err != nil {
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to get Registry Entity!"})
+		log.Error.Println("Request at /api/registry/nodelookup failed"+
+			" to retrieve Registry Entity : ", err)
+		return
+	}
+
+	// Responding with the node and entity found above
+	log.Debug.Println("Request at /api/registry/nodelookup responding" +
+		" with Node Lookup!")
+	encodeResponse(ctx, w, responses.NodeLookupResponse{
+		Lookup: &responses.NodeLookup{
+			MatchedBy: match.matchedBy,
+			Node:      match.node,
+			Entity:    registryEntity,
+		}})
+}
+
+// Bounds of the registry change feed, the events of each height in the
+// range are retrieved by a fixed number of concurrent workers
+const (
+	maxRegistryChangeHeights = 1000
+	registryChangeWorkers    = 8
+)
+
+// Types of registry changes
+const (
+	changeEntityRegistered   = "entity_registered"
+	changeEntityDeregistered = "entity_deregistered"
+	changeNodeRegistered     = "node_registered"
+	changeNodeDeregistered   = "node_deregistered"
+	changeNodeUnfrozen       = "node_unfrozen"
+	changeRuntimeRegistered  = "runtime_registered"
+	changeRuntimeSuspended   = "runtime_suspended"
+)
+
+// GetRegistryChanges scans the registry events of a height range and
+// returns the entity, node and runtime changes found in them, optionally
+// only those of a single entity.
+func GetRegistryChanges(w http.ResponseWriter, r *http.Request) {
+
+	// Add header so that received knows they're receiving JSON
+	w.Header().Add("Content-Type", "application/json")
+
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
+	// Retrieving name of node from query request
+	nodeName := r.URL.Query().Get("name")
+	confirmation, socket := checkNodeName(ctx, nodeName)
+	if !confirmation {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Node name requested doesn't exist"})
+		return
+	}
+
+	// Retrieving height range from query request, the range ends at the
+	// latest height when to_height is left out
+	fromHeight := checkHeight(ctx, r.URL.Query().Get("from_height"))
+	toHeight := checkHeight(ctx, r.URL.Query().Get("to_height"))
+	if fromHeight == -1 || toHeight == -1 {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Unexpected value found, height needs to be " +
+				"a string representing an int!"})
+		return
+	}
+	if fromHeight == 0 {
+
+		// Stop code here no need to establish connection and reply
+		log.Warning.Println("Request at /api/registry/changes failed, " +
+			"from_height can't be empty!")
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "from_height can't be empty!"})
+		return
+	}
+
+	// Retrieving optional entity to filter changes on
+	var entityID *common_signature.PublicKey
+	if entity := r.URL.Query().Get("entity"); len(entity) != 0 {
+		var pubKey common_signature.PublicKey
+		if err := pubKey.UnmarshalText([]byte(entity)); err != nil {
+			log.Error.Println(
+				"Failed to UnmarshalText into Public Key", err)
+			encodeResponse(ctx, w, responses.ErrorResponse{
+				Error: "Failed to UnmarshalText into Public Key."})
+			return
+		}
+		entityID = &pubKey
+	}
+
+	// Attempt to load connection with registry client
+	connection, ro := loadRegistryClient(ctx, socket)
+
+	// Close connection once code underneath executes
+	defer closeConnection(connection)
+
+	// If null object was retrieved send response
+	if ro == nil {
+
+		// Stop code here faild to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to establish connection using socket: " +
+				socket})
+		return
+	}
+
+	// Attempt to load connection with consensus client
+	consensusConnection, co := loadConsensusClient(ctx, socket)
+
+	// Close connection once code underneath executes
+	defer closeConnection(consensusConnection)
+
+	// If null object was retrieved send response
+	if co == nil {
+
+		// Stop code here faild to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to establish connection using socket: " +
+				socket})
+		return
+	}
+
+	if toHeight == 0 {
+		block, err := co.GetBlock(ctx, consensus.HeightLatest)
+		if err != nil {
+			encodeResponse(ctx, w, responses.ErrorResponse{
+				Error: "Failed to get Block!"})
+			log.Error.Println("Request at /api/registry/changes failed "+
+				"to retrieve latest block : ", err)
+			return
+		}
+		toHeight = block.Height
+	}
+	if fromHeight > toHeight ||
+		toHeight-fromHeight >= maxRegistryChangeHeights {
+
+		// Stop code here no need to retrieve events and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Unexpected value found, height range needs to " +
+				"span between 1 and 1000 heights!"})
+		return
+	}
+
+	events, err := registryEventsInRange(ctx, ro, fromHeight, toHeight)
+	if err != nil {
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to get Events!"})
+		log.Error.Println("Request at /api/registry/changes failed to "+
+			"retrieve events : ", err)
+		return
+	}
+
+	changes := []responses.RegistryChange{}
+	for _, event := range events {
+		change, err := registryChange(ctx, ro, event)
+		if err != nil {
+			encodeResponse(ctx, w, responses.ErrorResponse{
+				Error: "Failed to get Registry Changes!"})
+			log.Error.Println("Request at /api/registry/changes failed "+
+				"to resolve change : ", err)
+			return
+		}
+		if change == nil {
+			continue
+		}
+		if entityID != nil && (change.EntityID == nil ||
+			!change.EntityID.Equal(*entityID)) {
+			continue
+		}
+		changes = append(changes, *change)
+	}
+
+	// Responding with the changes found in the height range
+	log.Debug.Println("Request at /api/registry/changes responding with " +
+		"Registry Changes!")
+	encodeResponse(ctx, w, responses.RegistryChangesResponse{
+		Changes: changes})
+}
+
+// registryEventsInRange retrieves the registry events of every height in
+// a range concurrently and returns them in height order
+func registryEventsInRange(ctx context.Context, ro registry.Backend,
+	fromHeight int64, toHeight int64) ([]*registry.Event, error) {
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	perHeight := make([][]*registry.Event, toHeight-fromHeight+1)
+	heights := make(chan int64)
+	errs := make(chan error, registryChangeWorkers)
+	var wg sync.WaitGroup
+	for i := 0; i < registryChangeWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for height := range heights {
+				events, err := ro.GetEvents(ctx, height)
+				if err != nil {
+					errs <- fmt.Errorf("height %d : %w", height, err)
+					cancel()
+					return
+				}
+				perHeight[height-fromHeight] = events
+			}
+		}()
+	}
+
+feed:
+	for height := fromHeight; height <= toHeight; height++ {
+		select {
+		case heights <- height:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(heights)
+	wg.Wait()
+	close(errs)
+	if err := <-errs; err != nil {
+		return nil, err
+	}
+
+	events := []*registry.Event{}
+	for _, atHeight := range perHeight {
+		events = append(events, atHeight...)
+	}
+	return events, nil
+}
+
+// registryChange flattens a registry event into a change, looking up the
+// owning entity of nodes and runtimes that events only identify by ID.
+// Events that aren't entity, node or runtime changes return nil.
+func registryChange(ctx context.Context, ro registry.Backend,
+	event *registry.Event) (*responses.RegistryChange, error) {
+
+	change := &responses.RegistryChange{Height: event.Height}
+	if !event.TxHash.IsEmpty() {
+		txHash := event.TxHash
+		change.TxHash = &txHash
+	}
+
+	switch {
+	case event.EntityEvent != nil:
+		change.Type = changeEntityDeregistered
+		if event.EntityEvent.IsRegistration {
+			change.Type = changeEntityRegistered
+		}
+		change.EntityID = &event.EntityEvent.Entity.ID
+	case event.NodeEvent != nil:
+		change.Type = changeNodeDeregistered
+		if event.NodeEvent.IsRegistration {
+			change.Type = changeNodeRegistered
+		}
+		change.EntityID = &event.NodeEvent.Node.EntityID
+		change.NodeID = &event.NodeEvent.Node.ID
+	case event.NodeUnfrozenEvent != nil:
+		change.Type = changeNodeUnfrozen
+		change.NodeID = &event.NodeUnfrozenEvent.NodeID
+		node, err := ro.GetNode(ctx, &registry.IDQuery{
+			Height: event.Height, ID: event.NodeUnfrozenEvent.NodeID})
+		if err != nil {
+			return nil, err
+		}
+		change.EntityID = &node.EntityID
+	case event.RuntimeStartedEvent != nil:
+		change.Type = changeRuntimeRegistered
+		change.EntityID = &event.RuntimeStartedEvent.Runtime.EntityID
+		change.RuntimeID = &event.RuntimeStartedEvent.Runtime.ID
+	case event.RuntimeSuspendedEvent != nil:
+		change.Type = changeRuntimeSuspended
+		change.RuntimeID = &event.RuntimeSuspendedEvent.RuntimeID
+		runtime, err := ro.GetRuntime(ctx, &registry.GetRuntimeQuery{
+			Height:           event.Height,
+			ID:               *change.RuntimeID,
+			IncludeSuspended: true,
+		})
+		if err != nil {
+			return nil, err
+		}
+		change.EntityID = &runtime.EntityID
+	default:
+		return nil, nil
+	}
+	return change, nil
+}
@@ -9,6 +9,7 @@ import (
 
 	hdl "github.com/SimplyVC/oasis_api_server/src/handlers"
 	"github.com/SimplyVC/oasis_api_server/src/responses"
+	common_signature "github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	common_entity "github.com/oasisprotocol/oasis-core/go/common/entity"
 	common_node "github.com/oasisprotocol/oasis-core/go/common/node"
 	registry_api "github.com/oasisprotocol/oasis-core/go/registry/api"
@@ -535,3 +536,434 @@ func Test_GetRuntime_InvalidHeight(t *testing.T) {
 			rr.Body.String(), expected)
 	}
 }
+
+func Test_GetEntityOverview_BadNode(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/registry/entityoverview", nil)
+	q := req.URL.Query()
+	q.Add("name", "Unicorn")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetEntityOverview)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Node name requested doesn't exist"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetEntityOverview_InvalidHeight(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/registry/entityoverview", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	q.Add("height", "Unicorn")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetEntityOverview)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Unexpected value found, height needs to be a string representing an int!"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetEntityOverview_EmptyEntity(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/registry/entityoverview", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetEntityOverview)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"EntityID can't be empty!"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetEntityOverview_InvalidEntity(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/registry/entityoverview", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	q.Add("entity", "Unicorn")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetEntityOverview)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Failed to UnmarshalText into Public Key."}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetEntityOverview(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/registry/entityoverview", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	q.Add("entity", "gb8SHLeDc69Elk7OTfqhtVgE2sqxrBCDQI84xKR+Bjg=")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetEntityOverview)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := "result"
+
+	response := &responses.EntityOverviewResponse{}
+
+	err := json.Unmarshal([]byte(rr.Body.String()), response)
+	if err != nil {
+		t.Errorf("Failed to unmarshall data")
+	}
+
+	if strings.Contains(strings.TrimSpace(rr.Body.String()), expected) != true {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			strings.TrimSpace(rr.Body.String()), expected)
+	}
+}
+
+func Test_GetNodeLookup_BadNode(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/registry/nodelookup", nil)
+	q := req.URL.Query()
+	q.Add("name", "Unicorn")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetNodeLookup)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Node name requested doesn't exist"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetNodeLookup_InvalidHeight(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/registry/nodelookup", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	q.Add("height", "Unicorn")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetNodeLookup)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Unexpected value found, height needs to be a string representing an int!"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetNodeLookup_MissingIdentifier(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/registry/nodelookup", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetNodeLookup)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Either a key or an address is required!"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetNodeLookup_KeyAndAddress(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/registry/nodelookup", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	q.Add("key", "gb8SHLeDc69Elk7OTfqhtVgE2sqxrBCDQI84xKR+Bjg=")
+	q.Add("address", "9E2A5C94F6D43F2C5C5A8AD0A6FE1A2F47B32B9B")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetNodeLookup)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Either a key or an address is required!"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetNodeLookup_InvalidKey(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/registry/nodelookup", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	q.Add("key", "Unicorn")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetNodeLookup)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Failed to UnmarshalText into Public Key."}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetNodeLookup_InvalidAddress(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/registry/nodelookup", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	q.Add("address", "Unicorn")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetNodeLookup)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Unexpected value found, address needs to be a hex encoded Tendermint address!"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetNodeLookup(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/registry/nodelookup", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	q.Add("key", "5RIMVgnsN1D/HdvNxXCpE+lWH5U/SGYUrYsvhsTMbyA=")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetNodeLookup)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := "result"
+
+	response := &responses.NodeLookupResponse{}
+
+	err := json.Unmarshal([]byte(rr.Body.String()), response)
+	if err != nil {
+		t.Errorf("Failed to unmarshall data")
+	}
+
+	if strings.Contains(strings.TrimSpace(rr.Body.String()), expected) != true {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			strings.TrimSpace(rr.Body.String()), expected)
+	}
+}
+
+func Test_GetRegistryChanges_BadNode(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/registry/changes", nil)
+	q := req.URL.Query()
+	q.Add("name", "Unicorn")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetRegistryChanges)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Node name requested doesn't exist"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetRegistryChanges_InvalidHeight(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/registry/changes", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	q.Add("from_height", "Unicorn")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetRegistryChanges)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Unexpected value found, height needs to be a string representing an int!"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetRegistryChanges_EmptyFromHeight(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/registry/changes", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetRegistryChanges)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"from_height can't be empty!"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetRegistryChanges_InvalidEntity(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/registry/changes", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	q.Add("from_height", "1")
+	q.Add("entity", "Unicorn")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetRegistryChanges)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Failed to UnmarshalText into Public Key."}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetRegistryChanges(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/registry/changes", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	q.Add("from_height", "1")
+	q.Add("to_height", "100")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetRegistryChanges)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := "result"
+
+	response := &responses.RegistryChangesResponse{}
+
+	err := json.Unmarshal([]byte(rr.Body.String()), response)
+	if err != nil {
+		t.Errorf("Failed to unmarshall data")
+	}
+
+	if strings.Contains(strings.TrimSpace(rr.Body.String()), expected) != true {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			strings.TrimSpace(rr.Body.String()), expected)
+	}
+}
+
+func Test_NodeKeyIndex_ZeroKeys(t *testing.T) {
+	validator := &common_node.Node{}
+	validator.ID[0], validator.Consensus.ID[0] = 1, 2
+	validator.VRF.ID[0] = 3
+	compute := &common_node.Node{}
+	compute.ID[0], compute.Consensus.ID[0] = 4, 5
+	nodes := []*common_node.Node{validator, compute}
+
+	var vrfID common_signature.PublicKey
+	vrfID[0] = 3
+	if node, key, ok := hdl.LookupNodeKey(nodes, vrfID); !ok ||
+		node != validator || key != "vrf_id" {
+		t.Errorf("Unexpected match of VRF key %v %s", node, key)
+	}
+
+	// Keys nodes don't have are left zero and must not match any node
+	if node, key, ok := hdl.LookupNodeKey(nodes,
+		common_signature.PublicKey{}); ok {
+		t.Errorf("Zero key matched %s of node %v", key, node)
+	}
+}
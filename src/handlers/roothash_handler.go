@@ -0,0 +1,492 @@
+package handlers
+
+import (
+	"context"
+	"math"
+	"net/http"
+
+	"google.golang.org/grpc"
+
+	lgr "github.com/SimplyVC/oasis_api_server/src/logger"
+	"github.com/SimplyVC/oasis_api_server/src/responses"
+	"github.com/SimplyVC/oasis_api_server/src/rpc"
+	common_namespace "github.com/oasisprotocol/oasis-core/go/common"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+)
+
+// loadRootHashClient loads roothash client and returns it
+func loadRootHashClient(ctx context.Context, socket string) (
+	*grpc.ClientConn, roothash.Backend) {
+
+	log := lgr.FromContext(ctx)
+
+	// Attempt to load connection with roothash client
+	span := startConnect(ctx, "roothash", socket)
+	connection, rootHashClient, err := rpc.RootHashClient(socket)
+	endConnect(ctx, span, connection, err)
+	if err != nil {
+		log.Error.Println(
+			"Failed to establish connection to roothash client : ",
+			err)
+		return nil, nil
+	}
+	return connection, rootHashClient
+}
+
+// GetRuntimeLatestBlock returns the latest block of a runtime at a consensus
+// block height.
+func GetRuntimeLatestBlock(w http.ResponseWriter, r *http.Request) {
+
+	// Add header so that received knows they're receiving JSON
+	w.Header().Add("Content-Type", "application/json")
+
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
+	// Retrieving name of node from query request
+	nodeName := r.URL.Query().Get("name")
+	confirmation, socket := checkNodeName(ctx, nodeName)
+	if !confirmation {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Node name requested doesn't exist"})
+		return
+	}
+
+	// Retrieve height from query
+	recvHeight := r.URL.Query().Get("height")
+	height := checkHeight(ctx, recvHeight)
+	if height == -1 {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Unexpected value found, height needs to be " +
+				"a string representing an int!"})
+		return
+	}
+
+	// Note Make sure that namespace that is being sent is coded properly
+	// Example A1X90rT/WK4AOTh/dJsUlOqNDV/nXM6ZU+h+blS9pto= should be
+	// A1X90rT/WK4AOTh/dJsUlOqNDV/nXM6ZU%2Bh%2BblS9pto=
+	var nameSpace common_namespace.Namespace
+	nmspace := r.URL.Query().Get("namespace")
+	if len(nmspace) == 0 {
+		// Stop code here no need to establish connection and reply
+		log.Warning.Println("Request at /api/roothash/latestblock failed" +
+			", namespace can't be empty!")
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "namespace can't be empty!"})
+		return
+	}
+
+	// Unmarshal received text into namespace object
+	err := nameSpace.UnmarshalText([]byte(nmspace))
+	if err != nil {
+		log.Error.Println("Failed to UnmarshalText into Namespace", err)
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to UnmarshalText into Namespace."})
+		return
+	}
+
+	// Attempt to load connection with roothash client
+	connection, rh := loadRootHashClient(ctx, socket)
+
+	// Close connection once code underneath executes
+	defer closeConnection(connection)
+
+	// If null object was retrieved send response
+	if rh == nil {
+
+		// Stop code here faild to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to establish connection using socket: " +
+				socket})
+		return
+	}
+
+	// Retrieve latest runtime block at given consensus height
+	query := roothash.RuntimeRequest{RuntimeID: nameSpace, Height: height}
+	runtimeBlock, err := rh.GetLatestBlock(ctx, &query)
+	if err != nil {
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to get Latest Runtime Block!"})
+		log.Error.Println("Request at /api/roothash/latestblock failed "+
+			"to retrieve Latest Runtime Block : ", err)
+		return
+	}
+
+	// Responding with runtime block retrieved above
+	log.Debug.Println("Request at /api/roothash/latestblock responding " +
+		"with Latest Runtime Block!")
+	encodeResponse(ctx, w, responses.RuntimeBlockResponse{
+		Block: runtimeBlock})
+}
+
+// GetRuntimeState returns the roothash state of a runtime at a consensus
+// block height, including its current round, executor committee and last
+// normal round.
+func GetRuntimeState(w http.ResponseWriter, r *http.Request) {
+
+	// Add header so that received knows they're receiving JSON
+	w.Header().Add("Content-Type", "application/json")
+
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
+	// Retrieving name of node from query request
+	nodeName := r.URL.Query().Get("name")
+	confirmation, socket := checkNodeName(ctx, nodeName)
+	if !confirmation {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Node name requested doesn't exist"})
+		return
+	}
+
+	// Retrieve height from query
+	recvHeight := r.URL.Query().Get("height")
+	height := checkHeight(ctx, recvHeight)
+	if height == -1 {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Unexpected value found, height needs to be " +
+				"a string representing an int!"})
+		return
+	}
+
+	// Note Make sure that namespace that is being sent is coded properly
+	// Example A1X90rT/WK4AOTh/dJsUlOqNDV/nXM6ZU+h+blS9pto= should be
+	// A1X90rT/WK4AOTh/dJsUlOqNDV/nXM6ZU%2Bh%2BblS9pto=
+	var nameSpace common_namespace.Namespace
+	nmspace := r.URL.Query().Get("namespace")
+	if len(nmspace) == 0 {
+		// Stop code here no need to establish connection and reply
+		log.Warning.Println("Request at /api/roothash/runtimestate failed" +
+			", namespace can't be empty!")
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "namespace can't be empty!"})
+		return
+	}
+
+	// Unmarshal received text into namespace object
+	err := nameSpace.UnmarshalText([]byte(nmspace))
+	if err != nil {
+		log.Error.Println("Failed to UnmarshalText into Namespace", err)
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to UnmarshalText into Namespace."})
+		return
+	}
+
+	// Attempt to load connection with roothash client
+	connection, rh := loadRootHashClient(ctx, socket)
+
+	// Close connection once code underneath executes
+	defer closeConnection(connection)
+
+	// If null object was retrieved send response
+	if rh == nil {
+
+		// Stop code here faild to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to establish connection using socket: " +
+				socket})
+		return
+	}
+
+	// Retrieve runtime state at given consensus height
+	query := roothash.RuntimeRequest{RuntimeID: nameSpace, Height: height}
+	runtimeState, err := rh.GetRuntimeState(ctx, &query)
+	if err != nil {
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to get Runtime State!"})
+		log.Error.Println("Request at /api/roothash/runtimestate failed "+
+			"to retrieve Runtime State : ", err)
+		return
+	}
+
+	// Responding with runtime state retrieved above
+	log.Debug.Println("Request at /api/roothash/runtimestate responding " +
+		"with Runtime State!")
+	encodeResponse(ctx, w, responses.RuntimeStateResponse{
+		RuntimeState: runtimeState})
+}
+
+// GetRoothashEvents returns the roothash events of all runtimes at a
+// consensus block height.
+func GetRoothashEvents(w http.ResponseWriter, r *http.Request) {
+
+	// Add header so that received knows they're receiving JSON
+	w.Header().Add("Content-Type", "application/json")
+
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
+	// Retrieving name of node from query request
+	nodeName := r.URL.Query().Get("name")
+	confirmation, socket := checkNodeName(ctx, nodeName)
+	if !confirmation {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Node name requested doesn't exist"})
+		return
+	}
+
+	// Retrieve height from query
+	recvHeight := r.URL.Query().Get("height")
+	height := checkHeight(ctx, recvHeight)
+	if height == -1 {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Unexpected value found, height needs to be " +
+				"a string representing an int!"})
+		return
+	}
+
+	// Attempt to load connection with roothash client
+	connection, rh := loadRootHashClient(ctx, socket)
+
+	// Close connection once code underneath executes
+	defer closeConnection(connection)
+
+	// If null object was retrieved send response
+	if rh == nil {
+
+		// Stop code here faild to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to establish connection using socket: " +
+				socket})
+		return
+	}
+
+	// Retrieve roothash events at given consensus height
+	events, err := rh.GetEvents(ctx, height)
+	if err != nil {
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to get Roothash Events!"})
+		log.Error.Println("Request at /api/roothash/events failed "+
+			"to retrieve Roothash Events : ", err)
+		return
+	}
+
+	// Responding with roothash events retrieved above
+	log.Debug.Println("Request at /api/roothash/events responding " +
+		"with Roothash Events!")
+	encodeResponse(ctx, w, responses.RoothashEventsResponse{
+		Events: events})
+}
+
+// GetLastRoundResults returns the results of the last normal round of a
+// runtime at a consensus block height, including the results of the runtime
+// messages emitted in that round.
+func GetLastRoundResults(w http.ResponseWriter, r *http.Request) {
+
+	// Add header so that received knows they're receiving JSON
+	w.Header().Add("Content-Type", "application/json")
+
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
+	// Retrieving name of node from query request
+	nodeName := r.URL.Query().Get("name")
+	confirmation, socket := checkNodeName(ctx, nodeName)
+	if !confirmation {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Node name requested doesn't exist"})
+		return
+	}
+
+	// Retrieve height from query
+	recvHeight := r.URL.Query().Get("height")
+	height := checkHeight(ctx, recvHeight)
+	if height == -1 {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Unexpected value found, height needs to be " +
+				"a string representing an int!"})
+		return
+	}
+
+	// Note Make sure that namespace that is being sent is coded properly
+	// Example A1X90rT/WK4AOTh/dJsUlOqNDV/nXM6ZU+h+blS9pto= should be
+	// A1X90rT/WK4AOTh/dJsUlOqNDV/nXM6ZU%2Bh%2BblS9pto=
+	var nameSpace common_namespace.Namespace
+	nmspace := r.URL.Query().Get("namespace")
+	if len(nmspace) == 0 {
+		// Stop code here no need to establish connection and reply
+		log.Warning.Println("Request at /api/roothash/lastroundresults failed" +
+			", namespace can't be empty!")
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "namespace can't be empty!"})
+		return
+	}
+
+	// Unmarshal received text into namespace object
+	err := nameSpace.UnmarshalText([]byte(nmspace))
+	if err != nil {
+		log.Error.Println("Failed to UnmarshalText into Namespace", err)
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to UnmarshalText into Namespace."})
+		return
+	}
+
+	// Attempt to load connection with roothash client
+	connection, rh := loadRootHashClient(ctx, socket)
+
+	// Close connection once code underneath executes
+	defer closeConnection(connection)
+
+	// If null object was retrieved send response
+	if rh == nil {
+
+		// Stop code here faild to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to establish connection using socket: " +
+				socket})
+		return
+	}
+
+	// Retrieve last round results at given consensus height
+	query := roothash.RuntimeRequest{RuntimeID: nameSpace, Height: height}
+	results, err := rh.GetLastRoundResults(ctx, &query)
+	if err != nil {
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to get Last Round Results!"})
+		log.Error.Println("Request at /api/roothash/lastroundresults "+
+			"failed to retrieve Last Round Results : ", err)
+		return
+	}
+
+	// Responding with last round results retrieved above
+	log.Debug.Println("Request at /api/roothash/lastroundresults " +
+		"responding with Last Round Results!")
+	encodeResponse(ctx, w, responses.RoundResultsResponse{
+		RoundResults: results})
+}
+
+// GetIncomingMessages returns the incoming message queue of a runtime at a
+// consensus block height.
+func GetIncomingMessages(w http.ResponseWriter, r *http.Request) {
+
+	// Add header so that received knows they're receiving JSON
+	w.Header().Add("Content-Type", "application/json")
+
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
+	// Retrieving name of node from query request
+	nodeName := r.URL.Query().Get("name")
+	confirmation, socket := checkNodeName(ctx, nodeName)
+	if !confirmation {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Node name requested doesn't exist"})
+		return
+	}
+
+	// Retrieve height from query
+	recvHeight := r.URL.Query().Get("height")
+	height := checkHeight(ctx, recvHeight)
+	if height == -1 {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Unexpected value found, height needs to be " +
+				"a string representing an int!"})
+		return
+	}
+
+	// Note Make sure that namespace that is being sent is coded properly
+	// Example A1X90rT/WK4AOTh/dJsUlOqNDV/nXM6ZU+h+blS9pto= should be
+	// A1X90rT/WK4AOTh/dJsUlOqNDV/nXM6ZU%2Bh%2BblS9pto=
+	var nameSpace common_namespace.Namespace
+	nmspace := r.URL.Query().Get("namespace")
+	if len(nmspace) == 0 {
+		// Stop code here no need to establish connection and reply
+		log.Warning.Println("Request at /api/roothash/incomingmessages failed" +
+			", namespace can't be empty!")
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "namespace can't be empty!"})
+		return
+	}
+
+	// Unmarshal received text into namespace object
+	err := nameSpace.UnmarshalText([]byte(nmspace))
+	if err != nil {
+		log.Error.Println("Failed to UnmarshalText into Namespace", err)
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to UnmarshalText into Namespace."})
+		return
+	}
+
+	// Retrieve optional offset and limit of messages from query
+	offset := checkAmount(ctx, r.URL.Query().Get("offset"))
+	limit := checkAmount(ctx, r.URL.Query().Get("limit"))
+	if offset < 0 || limit < 0 || limit > math.MaxUint32 {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Unexpected value found, offset and limit need to " +
+				"be strings representing positive ints!"})
+		return
+	}
+
+	// Attempt to load connection with roothash client
+	connection, rh := loadRootHashClient(ctx, socket)
+
+	// Close connection once code underneath executes
+	defer closeConnection(connection)
+
+	// If null object was retrieved send response
+	if rh == nil {
+
+		// Stop code here faild to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to establish connection using socket: " +
+				socket})
+		return
+	}
+
+	// Retrieve queue metadata and queued messages at given consensus height
+	metaQuery := roothash.RuntimeRequest{RuntimeID: nameSpace, Height: height}
+	meta, err := rh.GetIncomingMessageQueueMeta(ctx,
+		&metaQuery)
+	if err != nil {
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to get Incoming Message Queue!"})
+		log.Error.Println("Request at /api/roothash/incomingmessages "+
+			"failed to retrieve Incoming Message Queue : ", err)
+		return
+	}
+	query := roothash.InMessageQueueRequest{
+		RuntimeID: nameSpace,
+		Height:    height,
+		Offset:    uint64(offset),
+		Limit:     uint32(limit),
+	}
+	messages, err := rh.GetIncomingMessageQueue(ctx, &query)
+	if err != nil {
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to get Incoming Messages!"})
+		log.Error.Println("Request at /api/roothash/incomingmessages "+
+			"failed to retrieve Incoming Messages : ", err)
+		return
+	}
+
+	// Responding with incoming messages retrieved above
+	log.Debug.Println("Request at /api/roothash/incomingmessages " +
+		"responding with Incoming Messages!")
+	encodeResponse(ctx, w, responses.IncomingMessagesResponse{
+		IncomingMessages: &responses.IncomingMessages{
+			Meta:     meta,
+			Messages: messages,
+		}})
+}
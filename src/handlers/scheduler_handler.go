@@ -1,26 +1,38 @@
 package handlers
 
 import (
+	"bytes"
 	"context"
-	"encoding/json"
 	"net/http"
+	"sort"
 
 	"google.golang.org/grpc"
 
 	lgr "github.com/SimplyVC/oasis_api_server/src/logger"
 	"github.com/SimplyVC/oasis_api_server/src/responses"
 	"github.com/SimplyVC/oasis_api_server/src/rpc"
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
 	common_namespace "github.com/oasisprotocol/oasis-core/go/common"
+	common_signature "github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	common_node "github.com/oasisprotocol/oasis-core/go/common/node"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
 	scheduler "github.com/oasisprotocol/oasis-core/go/scheduler/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
 )
 
 // loadSchedulerClient loads scheduler client and returns it
-func loadSchedulerClient(socket string) (*grpc.ClientConn, scheduler.Backend) {
+func loadSchedulerClient(ctx context.Context, socket string) (
+	*grpc.ClientConn, scheduler.Backend) {
+
+	log := lgr.FromContext(ctx)
 
 	// Attempt to load connection with scheduler client
+	span := startConnect(ctx, "scheduler", socket)
 	connection, schedulerClient, err := rpc.SchedulerClient(socket)
+	endConnect(ctx, span, connection, err)
 	if err != nil {
-		lgr.Error.Println(
+		log.Error.Println(
 			"Failed to establish connection to scheduler client : ",
 			err)
 		return nil, nil
@@ -34,59 +46,62 @@ func GetValidators(w http.ResponseWriter, r *http.Request) {
 	// Add header so that received knows they're receiving JSON
 	w.Header().Add("Content-Type", "application/json")
 
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
 	// Retrieving name of node from query request
 	nodeName := r.URL.Query().Get("name")
-	confirmation, socket := checkNodeName(nodeName)
+	confirmation, socket := checkNodeName(ctx, nodeName)
 	if !confirmation  {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Node name requested doesn't exist"})
 		return
 	}
 
 	// Retrieving height of node from query request
 	recvHeight := r.URL.Query().Get("height")
-	height := checkHeight(recvHeight)
+	height := checkHeight(ctx, recvHeight)
 	if height == -1 {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Unexpected value found, height needs to be " +
 				"a string representing an int!"})
 		return
 	}
 
 	// Attempt to load connection with scheduler client
-	connection, sc := loadSchedulerClient(socket)
+	connection, sc := loadSchedulerClient(ctx, socket)
 
 	// Close connection once code underneath executes
-	defer connection.Close()
+	defer closeConnection(connection)
 
 	// If null object was retrieved send response
 	if sc == nil {
 
 		// Stop code here faild to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to establish connection using socket: " +
 				socket})
 		return
 	}
 
 	// Retrieve validators at given block height
-	validators, err := sc.GetValidators(context.Background(), height)
+	validators, err := sc.GetValidators(ctx, height)
 	if err != nil {
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to get Validators!"})
-		lgr.Error.Println("Request at /api/scheduler/validators "+
+		log.Error.Println("Request at /api/scheduler/validators "+
 			"failed to retrieve validators : ", err)
 		return
 	}
 
 	// Responding with Validators retrieved from scheduler client
-	lgr.Info.Println("Request at /api/scheduler/validators responding " +
+	log.Debug.Println("Request at /api/scheduler/validators responding " +
 		"with Validators!")
-	json.NewEncoder(w).Encode(responses.ValidatorsResponse{
+	encodeResponse(ctx, w, responses.ValidatorsResponse{
 		Validators: validators})
 }
 
@@ -98,22 +113,25 @@ func GetCommittees(w http.ResponseWriter, r *http.Request) {
 	// Add header so that received knows they're receiving JSON
 	w.Header().Add("Content-Type", "application/json")
 
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
 	// Retrieving name of node from query request
 	nodeName := r.URL.Query().Get("name")
-	confirmation, socket := checkNodeName(nodeName)
+	confirmation, socket := checkNodeName(ctx, nodeName)
 	if !confirmation  {
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Node name requested doesn't exist"})
 		return
 	}
 
 	// Retrieving height from query request
 	recvHeight := r.URL.Query().Get("height")
-	height := checkHeight(recvHeight)
+	height := checkHeight(ctx, recvHeight)
 	if height == -1 {
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Unexpected value found, height needs to be " +
 				"a string representing an int!"})
 		return
@@ -127,9 +145,9 @@ func GetCommittees(w http.ResponseWriter, r *http.Request) {
 	if len(nmspace) == 0 {
 
 		// Stop code here no need to establish connection and reply
-		lgr.Warning.Println("Request at /api/scheduler/committees failed" +
+		log.Warning.Println("Request at /api/scheduler/committees failed" +
 			", namespace can't be empty!")
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "namespace can't be empty!"})
 		return
 	}
@@ -137,23 +155,23 @@ func GetCommittees(w http.ResponseWriter, r *http.Request) {
 	// Unmarshal text into namespace object to be used in query
 	err := nameSpace.UnmarshalText([]byte(nmspace))
 	if err != nil {
-		lgr.Error.Println("Failed to UnmarshalText into Namespace", err)
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		log.Error.Println("Failed to UnmarshalText into Namespace", err)
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to UnmarshalText into Namespace."})
 		return
 	}
 
 	// Attempt to load connection with scheduler client
-	connection, sc := loadSchedulerClient(socket)
+	connection, sc := loadSchedulerClient(ctx, socket)
 
 	// Close connection once code underneath executes
-	defer connection.Close()
+	defer closeConnection(connection)
 
 	// If null object was retrieved send response
 	if sc == nil {
 
 		// Stop code here faild to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to establish connection using socket: " +
 				socket})
 		return
@@ -164,19 +182,19 @@ func GetCommittees(w http.ResponseWriter, r *http.Request) {
 		RuntimeID: nameSpace}
 
 	// Retrieving Committees using query above
-	committees, err := sc.GetCommittees(context.Background(), &query)
+	committees, err := sc.GetCommittees(ctx, &query)
 	if err != nil {
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to get Committees!"})
-		lgr.Error.Println("Request at /api/scheduler/committees "+
+		log.Error.Println("Request at /api/scheduler/committees "+
 			"failed to retrieve committees : ", err)
 		return
 	}
 
 	// Responding with committees that were retrieved from scheduler client
-	lgr.Info.Println("Request at /api/scheduler/committees responding " +
+	log.Debug.Println("Request at /api/scheduler/committees responding " +
 		"with Committees!")
-	json.NewEncoder(w).Encode(responses.CommitteesResponse{
+	encodeResponse(ctx, w, responses.CommitteesResponse{
 		Committee: committees})
 }
 
@@ -187,58 +205,982 @@ func GetSchedulerStateToGenesis(w http.ResponseWriter, r *http.Request) {
 	// Add header so that received knows they're receiving JSON
 	w.Header().Add("Content-Type", "application/json")
 
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
 	// Retrieving name of node from query request
 	nodeName := r.URL.Query().Get("name")
-	confirmation, socket := checkNodeName(nodeName)
+	confirmation, socket := checkNodeName(ctx, nodeName)
 	if !confirmation  {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Node name requested doesn't exist"})
 		return
 	}
 
 	// Retrieving height from query request
 	recvHeight := r.URL.Query().Get("height")
-	height := checkHeight(recvHeight)
+	height := checkHeight(ctx, recvHeight)
 	if height == -1 {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Unexpected value found, height needs to be " +
 				"a string representing an int!"})
 		return
 	}
 
 	// Attempt to load connection with scheduler client
-	connection, sc := loadSchedulerClient(socket)
+	connection, sc := loadSchedulerClient(ctx, socket)
 
 	// Close connection once code underneath executes
-	defer connection.Close()
+	defer closeConnection(connection)
 
 	// If null object was retrieved send response
 	if sc == nil {
 
 		// Stop code here faild to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to establish connection using socket: " +
 				socket})
 		return
 	}
 
 	// Retrieve genesis state of scheduler at specific block height
-	gensis, err := sc.StateToGenesis(context.Background(), height)
+	gensis, err := sc.StateToGenesis(ctx, height)
 	if err != nil {
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to get Scheduler Genesis State!"})
-		lgr.Error.Println("Request at /api/scheduler/genesis failed "+
+		log.Error.Println("Request at /api/scheduler/genesis failed "+
 			"to retrieve Scheduler Genesis State : ", err)
 		return
 	}
 
 	// Responding with genesis state retrieved above
-	lgr.Info.Println("Request at /api/scheduler/genesis responding with " +
+	log.Debug.Println("Request at /api/scheduler/genesis responding with " +
 		"scheduler genesis state!")
-	json.NewEncoder(w).Encode(responses.SchedulerGenesisState{
+	encodeResponse(ctx, w, responses.SchedulerGenesisState{
 		SchedulerGenesisState: gensis})
 }
+
+// Bounds of the committee membership history of a node, each epoch of
+// history queries the committees of every runtime
+const (
+	defaultCommitteeHistoryEpochs = 10
+	maxCommitteeHistoryEpochs     = 100
+)
+
+// GetNodeCommittees returns the committees across all registered runtimes
+// that a node is a member of at a block height, together with the changes
+// in its membership over the preceding epochs.
+func GetNodeCommittees(w http.ResponseWriter, r *http.Request) {
+
+	// Add header so that received knows they're receiving JSON
+	w.Header().Add("Content-Type", "application/json")
+
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
+	// Retrieving name of node from query request
+	nodeName := r.URL.Query().Get("name")
+	confirmation, socket := checkNodeName(ctx, nodeName)
+	if !confirmation {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Node name requested doesn't exist"})
+		return
+	}
+
+	// Retrieve height from query
+	recvHeight := r.URL.Query().Get("height")
+	height := checkHeight(ctx, recvHeight)
+	if height == -1 {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Unexpected value found, height needs to be " +
+				"a string representing an int!"})
+		return
+	}
+
+	// Retrieve node ID from query
+	var pubKey common_signature.PublicKey
+	nodeID := r.URL.Query().Get("nodeID")
+	if len(nodeID) == 0 {
+
+		// Stop code here no need to establish connection and reply
+		log.Warning.Println("Request at /api/scheduler/nodecommittees " +
+			"failed, NodeID can't be empty!")
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "NodeID can't be empty!"})
+		return
+	}
+
+	// Unmarshal received text into public key object
+	err := pubKey.UnmarshalText([]byte(nodeID))
+	if err != nil {
+		log.Error.Println(
+			"Failed to UnmarshalText into Public Key", err)
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to UnmarshalText into Public Key."})
+		return
+	}
+
+	// Retrieve number of epochs of history from query
+	epochs := int64(defaultCommitteeHistoryEpochs)
+	if recvEpochs := r.URL.Query().Get("epochs"); len(recvEpochs) != 0 {
+		epochs = checkAmount(ctx, recvEpochs)
+	}
+	if epochs < 0 || epochs > maxCommitteeHistoryEpochs {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Unexpected value found, epochs needs to be a " +
+				"string representing an int between 0 and 100!"})
+		return
+	}
+
+	// Attempt to load connection with scheduler client
+	connection, sc := loadSchedulerClient(ctx, socket)
+
+	// Close connection once code underneath executes
+	defer closeConnection(connection)
+
+	// If null object was retrieved send response
+	if sc == nil {
+
+		// Stop code here faild to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to establish connection using socket: " +
+				socket})
+		return
+	}
+
+	// Attempt to load connection with registry client
+	registryConnection, ro := loadRegistryClient(ctx, socket)
+
+	// Close connection once code underneath executes
+	defer closeConnection(registryConnection)
+
+	// If null object was retrieved send response
+	if ro == nil {
+
+		// Stop code here faild to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to establish connection using socket: " +
+				socket})
+		return
+	}
+
+	// Attempt to load connection with consensus client
+	consensusConnection, co := loadConsensusClient(ctx, socket)
+
+	// Close connection once code underneath executes
+	defer closeConnection(consensusConnection)
+
+	// If null object was retrieved send response
+	if co == nil {
+
+		// Stop code here faild to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to establish connection using socket: " +
+				socket})
+		return
+	}
+
+	// Retrieve the epoch of the height and the first epoch of the chain
+	epoch, err := co.Beacon().GetEpoch(ctx, height)
+	if err != nil {
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to get Epoch!"})
+		log.Error.Println("Request at /api/scheduler/nodecommittees "+
+			"failed to retrieve Epoch : ", err)
+		return
+	}
+	baseEpoch, err := co.Beacon().GetBaseEpoch(ctx)
+	if err != nil {
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to get Base Epoch!"})
+		log.Error.Println("Request at /api/scheduler/nodecommittees "+
+			"failed to retrieve Base Epoch : ", err)
+		return
+	}
+
+	memberships, err := committeeMemberships(ctx, ro, sc, height, pubKey)
+	if err != nil {
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to get Committees!"})
+		log.Error.Println("Request at /api/scheduler/nodecommittees "+
+			"failed to retrieve committees : ", err)
+		return
+	}
+
+	// Walk back through the preceding epochs, comparing the membership
+	// at the start of each epoch with the epoch after it
+	history := []responses.CommitteeMembershipChange{}
+	next := responses.CommitteeMembershipChange{
+		Epoch:  epoch,
+		Height: height,
+	}
+	nextMemberships := memberships
+	for previous := epoch; previous > baseEpoch &&
+		int64(epoch-previous) < epochs; {
+
+		previous--
+		startHeight, err := co.Beacon().GetEpochBlock(ctx, previous)
+		if err != nil {
+			encodeResponse(ctx, w, responses.ErrorResponse{
+				Error: "Failed to get Committee History!"})
+			log.Error.Println("Request at /api/scheduler/nodecommittees "+
+				"failed to retrieve start of epoch : ", err)
+			return
+		}
+		previousMemberships, err := committeeMemberships(ctx, ro, sc,
+			startHeight, pubKey)
+		if err != nil {
+			encodeResponse(ctx, w, responses.ErrorResponse{
+				Error: "Failed to get Committee History!"})
+			log.Error.Println("Request at /api/scheduler/nodecommittees "+
+				"failed to retrieve committees : ", err)
+			return
+		}
+
+		next.Joined = membershipDifference(nextMemberships,
+			previousMemberships)
+		next.Left = membershipDifference(previousMemberships,
+			nextMemberships)
+		if len(next.Joined) != 0 || len(next.Left) != 0 {
+			history = append(history, next)
+		}
+		next = responses.CommitteeMembershipChange{
+			Epoch:  previous,
+			Height: startHeight,
+		}
+		nextMemberships = previousMemberships
+	}
+
+	// Responding with the committee memberships of the node
+	log.Debug.Println("Request at /api/scheduler/nodecommittees responding " +
+		"with Node Committees!")
+	encodeResponse(ctx, w, responses.NodeCommitteesResponse{
+		NodeCommittees: &responses.NodeCommittees{
+			NodeID:      pubKey,
+			Epoch:       epoch,
+			Memberships: memberships,
+			History:     history,
+		}})
+}
+
+// committeeMemberships finds the committees of all registered runtimes that
+// a node is a member of at a height
+func committeeMemberships(ctx context.Context, ro registry.Backend,
+	sc scheduler.Backend, height int64,
+	nodeID common_signature.PublicKey) ([]responses.CommitteeMembership,
+	error) {
+
+	runtimes, err := ro.GetRuntimes(ctx, &registry.GetRuntimesQuery{
+		Height: height})
+	if err != nil {
+		return nil, err
+	}
+
+	memberships := []responses.CommitteeMembership{}
+	for _, runtime := range runtimes {
+		committees, err := sc.GetCommittees(ctx,
+			&scheduler.GetCommitteesRequest{Height: height,
+				RuntimeID: runtime.ID})
+		if err != nil {
+			return nil, err
+		}
+		for _, committee := range committees {
+			for _, member := range committee.Members {
+				if !member.PublicKey.Equal(nodeID) {
+					continue
+				}
+				memberships = append(memberships,
+					responses.CommitteeMembership{
+						RuntimeID: committee.RuntimeID,
+						Kind:      committee.Kind,
+						Role:      member.Role,
+					})
+			}
+		}
+	}
+	return memberships, nil
+}
+
+// membershipDifference returns the memberships in a that aren't in b
+func membershipDifference(a,
+	b []responses.CommitteeMembership) []responses.CommitteeMembership {
+
+	inB := make(map[responses.CommitteeMembership]bool)
+	for _, membership := range b {
+		inB[membership] = true
+	}
+	difference := []responses.CommitteeMembership{}
+	for _, membership := range a {
+		if !inB[membership] {
+			difference = append(difference, membership)
+		}
+	}
+	return difference
+}
+
+// maxVotingPowerEpochs bounds the number of epochs of a voting power series,
+// each of them queries the validator set
+const maxVotingPowerEpochs = 100
+
+// GetValidatorsDiff compares the validator sets at two heights or at the
+// start of two epochs, returning the validators that were added, removed or
+// whose voting power changed.
+func GetValidatorsDiff(w http.ResponseWriter, r *http.Request) {
+
+	// Add header so that received knows they're receiving JSON
+	w.Header().Add("Content-Type", "application/json")
+
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
+	// Retrieving name of node from query request
+	nodeName := r.URL.Query().Get("name")
+	confirmation, socket := checkNodeName(ctx, nodeName)
+	if !confirmation {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Node name requested doesn't exist"})
+		return
+	}
+
+	// Retrieving heights from query request
+	fromHeight := checkHeight(ctx, r.URL.Query().Get("from_height"))
+	toHeight := checkHeight(ctx, r.URL.Query().Get("to_height"))
+	if fromHeight == -1 || toHeight == -1 {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Unexpected value found, height needs to be " +
+				"a string representing an int!"})
+		return
+	}
+
+	// Retrieving epochs from query request
+	fromEpoch, okFrom := checkEpoch(ctx, r.URL.Query().Get("from_epoch"))
+	toEpoch, okTo := checkEpoch(ctx, r.URL.Query().Get("to_epoch"))
+	if !okFrom || !okTo {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Unexpected value found, epoch needs to be " +
+				"a string representing an unsigned int!"})
+		return
+	}
+
+	// Each side is given either as a height or as an epoch, only the side
+	// compared against may be left out to compare with the latest height
+	if (fromHeight != 0) == (fromEpoch != beacon.EpochInvalid) ||
+		(toHeight != 0 && toEpoch != beacon.EpochInvalid) {
+
+		// Stop code here no need to establish connection and reply
+		log.Warning.Println("Request at /api/scheduler/validatorsdiff " +
+			"failed, expected either a height or an epoch for each side!")
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Either a height or an epoch is required to compare " +
+				"from, and at most one of them to compare to!"})
+		return
+	}
+
+	// Attempt to load connection with scheduler client
+	connection, sc := loadSchedulerClient(ctx, socket)
+
+	// Close connection once code underneath executes
+	defer closeConnection(connection)
+
+	// If null object was retrieved send response
+	if sc == nil {
+
+		// Stop code here faild to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to establish connection using socket: " +
+				socket})
+		return
+	}
+
+	// Attempt to load connection with consensus client
+	consensusConnection, co := loadConsensusClient(ctx, socket)
+
+	// Close connection once code underneath executes
+	defer closeConnection(consensusConnection)
+
+	// If null object was retrieved send response
+	if co == nil {
+
+		// Stop code here faild to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to establish connection using socket: " +
+				socket})
+		return
+	}
+
+	// Epochs are compared at the first block of the epoch
+	var err error
+	if fromEpoch != beacon.EpochInvalid {
+		fromHeight, err = co.Beacon().GetEpochBlock(ctx, fromEpoch)
+	}
+	if err == nil && toEpoch != beacon.EpochInvalid {
+		toHeight, err = co.Beacon().GetEpochBlock(ctx, toEpoch)
+	}
+	if err != nil {
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to get Epoch Block!"})
+		log.Error.Println("Request at /api/scheduler/validatorsdiff "+
+			"failed to retrieve start of epoch : ", err)
+		return
+	}
+
+	// Resolve the latest height so that the response says what was compared
+	if toHeight == 0 {
+		block, err := co.GetBlock(ctx, consensus.HeightLatest)
+		if err != nil {
+			encodeResponse(ctx, w, responses.ErrorResponse{
+				Error: "Failed to get Block!"})
+			log.Error.Println("Request at /api/scheduler/validatorsdiff "+
+				"failed to retrieve latest block : ", err)
+			return
+		}
+		toHeight = block.Height
+	}
+
+	// Retrieve the validator sets at both heights
+	fromValidators, err := sc.GetValidators(ctx, fromHeight)
+	if err != nil {
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to get Validators!"})
+		log.Error.Println("Request at /api/scheduler/validatorsdiff "+
+			"failed to retrieve validators : ", err)
+		return
+	}
+	toValidators, err := sc.GetValidators(ctx, toHeight)
+	if err != nil {
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to get Validators!"})
+		log.Error.Println("Request at /api/scheduler/validatorsdiff "+
+			"failed to retrieve validators : ", err)
+		return
+	}
+
+	// Responding with the differences between the validator sets
+	diff := diffValidators(fromValidators, toValidators)
+	diff.FromHeight = fromHeight
+	diff.ToHeight = toHeight
+	log.Debug.Println("Request at /api/scheduler/validatorsdiff responding " +
+		"with Validators Diff!")
+	encodeResponse(ctx, w, responses.ValidatorsDiffResponse{
+		ValidatorsDiff: diff})
+}
+
+// diffValidators finds the validators added to, removed from and changed in
+// voting power between two validator sets
+func diffValidators(from,
+	to []*scheduler.Validator) *responses.ValidatorsDiff {
+
+	diff := &responses.ValidatorsDiff{
+		Added:   []*scheduler.Validator{},
+		Removed: []*scheduler.Validator{},
+		Changed: []responses.VotingPowerChange{},
+	}
+	previous := make(map[common_signature.PublicKey]*scheduler.Validator)
+	for _, validator := range from {
+		previous[validator.ID] = validator
+	}
+	for _, validator := range to {
+		before, ok := previous[validator.ID]
+		if !ok {
+			diff.Added = append(diff.Added, validator)
+			continue
+		}
+		delete(previous, validator.ID)
+		if before.VotingPower != validator.VotingPower {
+			diff.Changed = append(diff.Changed, responses.VotingPowerChange{
+				ID:              validator.ID,
+				EntityID:        validator.EntityID,
+				FromVotingPower: before.VotingPower,
+				ToVotingPower:   validator.VotingPower,
+			})
+		}
+	}
+
+	// Keep removed validators in the order of the earlier set
+	for _, validator := range from {
+		if _, ok := previous[validator.ID]; ok {
+			diff.Removed = append(diff.Removed, validator)
+		}
+	}
+	return diff
+}
+
+// GetVotingPowerSeries returns the voting power of a node at the start of
+// each epoch in a range, so that drops out of the validator set stand out.
+func GetVotingPowerSeries(w http.ResponseWriter, r *http.Request) {
+
+	// Add header so that received knows they're receiving JSON
+	w.Header().Add("Content-Type", "application/json")
+
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
+	// Retrieving name of node from query request
+	nodeName := r.URL.Query().Get("name")
+	confirmation, socket := checkNodeName(ctx, nodeName)
+	if !confirmation {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Node name requested doesn't exist"})
+		return
+	}
+
+	// Retrieve node ID from query
+	var pubKey common_signature.PublicKey
+	nodeID := r.URL.Query().Get("nodeID")
+	if len(nodeID) == 0 {
+
+		// Stop code here no need to establish connection and reply
+		log.Warning.Println("Request at /api/scheduler/votingpower " +
+			"failed, NodeID can't be empty!")
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "NodeID can't be empty!"})
+		return
+	}
+
+	// Unmarshal received text into public key object
+	err := pubKey.UnmarshalText([]byte(nodeID))
+	if err != nil {
+		log.Error.Println(
+			"Failed to UnmarshalText into Public Key", err)
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to UnmarshalText into Public Key."})
+		return
+	}
+
+	// Retrieving epoch range from query request, the range ends at the
+	// current epoch and spans the last 10 epochs when left out
+	fromEpoch, okFrom := checkEpoch(ctx, r.URL.Query().Get("from_epoch"))
+	toEpoch, okTo := checkEpoch(ctx, r.URL.Query().Get("to_epoch"))
+	if !okFrom || !okTo {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Unexpected value found, epoch needs to be " +
+				"a string representing an unsigned int!"})
+		return
+	}
+
+	// Attempt to load connection with scheduler client
+	connection, sc := loadSchedulerClient(ctx, socket)
+
+	// Close connection once code underneath executes
+	defer closeConnection(connection)
+
+	// If null object was retrieved send response
+	if sc == nil {
+
+		// Stop code here faild to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to establish connection using socket: " +
+				socket})
+		return
+	}
+
+	// Attempt to load connection with consensus client
+	consensusConnection, co := loadConsensusClient(ctx, socket)
+
+	// Close connection once code underneath executes
+	defer closeConnection(consensusConnection)
+
+	// If null object was retrieved send response
+	if co == nil {
+
+		// Stop code here faild to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to establish connection using socket: " +
+				socket})
+		return
+	}
+
+	if toEpoch == beacon.EpochInvalid {
+		toEpoch, err = co.Beacon().GetEpoch(ctx, consensus.HeightLatest)
+		if err != nil {
+			encodeResponse(ctx, w, responses.ErrorResponse{
+				Error: "Failed to get Epoch!"})
+			log.Error.Println("Request at /api/scheduler/votingpower "+
+				"failed to retrieve Epoch : ", err)
+			return
+		}
+	}
+	if fromEpoch == beacon.EpochInvalid {
+		fromEpoch = 0
+		if toEpoch >= 9 {
+			fromEpoch = toEpoch - 9
+		}
+	}
+	if fromEpoch > toEpoch || toEpoch-fromEpoch >= maxVotingPowerEpochs {
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Unexpected value found, epoch range needs to " +
+				"span between 1 and 100 epochs!"})
+		return
+	}
+
+	// Sample the validator set at the first block of every epoch, epochs
+	// before the first epoch of the chain are skipped
+	baseEpoch, err := co.Beacon().GetBaseEpoch(ctx)
+	if err != nil {
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to get Base Epoch!"})
+		log.Error.Println("Request at /api/scheduler/votingpower "+
+			"failed to retrieve Base Epoch : ", err)
+		return
+	}
+	if fromEpoch < baseEpoch {
+		fromEpoch = baseEpoch
+	}
+
+	series := []responses.VotingPowerSample{}
+	for epoch := fromEpoch; epoch <= toEpoch; epoch++ {
+		height, err := co.Beacon().GetEpochBlock(ctx, epoch)
+		if err != nil {
+			encodeResponse(ctx, w, responses.ErrorResponse{
+				Error: "Failed to get Epoch Block!"})
+			log.Error.Println("Request at /api/scheduler/votingpower "+
+				"failed to retrieve start of epoch : ", err)
+			return
+		}
+		validators, err := sc.GetValidators(ctx, height)
+		if err != nil {
+			encodeResponse(ctx, w, responses.ErrorResponse{
+				Error: "Failed to get Validators!"})
+			log.Error.Println("Request at /api/scheduler/votingpower "+
+				"failed to retrieve validators : ", err)
+			return
+		}
+
+		sample := responses.VotingPowerSample{Epoch: epoch, Height: height}
+		for _, validator := range validators {
+			if validator.ID.Equal(pubKey) {
+				sample.InValidatorSet = true
+				sample.VotingPower = validator.VotingPower
+			}
+		}
+		series = append(series, sample)
+	}
+
+	// Responding with the voting power of the node per epoch
+	log.Debug.Println("Request at /api/scheduler/votingpower responding " +
+		"with Voting Power Series!")
+	encodeResponse(ctx, w, responses.VotingPowerSeriesResponse{
+		Series: series})
+}
+
+// GetExpectedValidators ranks the entities running validator nodes by
+// escrow balance the way the scheduler elects the validator set at the next
+// epoch, showing the stake cutoff of the set and, when an entity is given,
+// its rank and margin to the cutoff.
+func GetExpectedValidators(w http.ResponseWriter, r *http.Request) {
+
+	// Add header so that received knows they're receiving JSON
+	w.Header().Add("Content-Type", "application/json")
+
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
+	// Retrieving name of node from query request
+	nodeName := r.URL.Query().Get("name")
+	confirmation, socket := checkNodeName(ctx, nodeName)
+	if !confirmation {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Node name requested doesn't exist"})
+		return
+	}
+
+	// Retrieve height from query
+	recvHeight := r.URL.Query().Get("height")
+	height := checkHeight(ctx, recvHeight)
+	if height == -1 {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Unexpected value found, height needs to be " +
+				"a string representing an int!"})
+		return
+	}
+
+	// Retrieve optional entity whose rank is reported from query
+	var entityID *common_signature.PublicKey
+	if entity := r.URL.Query().Get("entity"); len(entity) != 0 {
+		var pubKey common_signature.PublicKey
+		if err := pubKey.UnmarshalText([]byte(entity)); err != nil {
+			log.Error.Println(
+				"Failed to UnmarshalText into Public Key", err)
+			encodeResponse(ctx, w, responses.ErrorResponse{
+				Error: "Failed to UnmarshalText into Public Key."})
+			return
+		}
+		entityID = &pubKey
+	}
+
+	// Attempt to load connection with scheduler client
+	connection, sc := loadSchedulerClient(ctx, socket)
+
+	// Close connection once code underneath executes
+	defer closeConnection(connection)
+
+	// If null object was retrieved send response
+	if sc == nil {
+
+		// Stop code here faild to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to establish connection using socket: " +
+				socket})
+		return
+	}
+
+	// Attempt to load connection with registry client
+	registryConnection, ro := loadRegistryClient(ctx, socket)
+
+	// Close connection once code underneath executes
+	defer closeConnection(registryConnection)
+
+	// If null object was retrieved send response
+	if ro == nil {
+
+		// Stop code here faild to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to establish connection using socket: " +
+				socket})
+		return
+	}
+
+	// Attempt to load connection with staking client
+	stakingConnection, so := loadStakingClient(ctx, socket)
+
+	// Close connection once code underneath executes
+	defer closeConnection(stakingConnection)
+
+	// If null object was retrieved send response
+	if so == nil {
+
+		// Stop code here faild to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to establish connection using socket: " +
+				socket})
+		return
+	}
+
+	// Attempt to load connection with consensus client
+	consensusConnection, co := loadConsensusClient(ctx, socket)
+
+	// Close connection once code underneath executes
+	defer closeConnection(consensusConnection)
+
+	// If null object was retrieved send response
+	if co == nil {
+
+		// Stop code here faild to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to establish connection using socket: " +
+				socket})
+		return
+	}
+
+	params, err := sc.ConsensusParameters(ctx, height)
+	if err != nil {
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to get Scheduler Consensus Parameters!"})
+		log.Error.Println("Request at /api/scheduler/expectedvalidators "+
+			"failed to retrieve Scheduler Consensus Parameters : ", err)
+		return
+	}
+	stakingParams, err := so.ConsensusParameters(ctx, height)
+	if err != nil {
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to get Staking Consensus Parameters!"})
+		log.Error.Println("Request at /api/scheduler/expectedvalidators "+
+			"failed to retrieve Staking Consensus Parameters : ", err)
+		return
+	}
+	epoch, err := co.Beacon().GetEpoch(ctx, height)
+	if err != nil {
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to get Epoch!"})
+		log.Error.Println("Request at /api/scheduler/expectedvalidators "+
+			"failed to retrieve Epoch : ", err)
+		return
+	}
+	nodes, err := ro.GetNodes(ctx, height)
+	if err != nil {
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to get Nodes!"})
+		log.Error.Println("Request at /api/scheduler/expectedvalidators "+
+			"failed to retrieve Nodes : ", err)
+		return
+	}
+
+	// Like the scheduler, only consider validator nodes that aren't frozen
+	// or expired at the next epoch, of entities that meet their stake claims
+	nextEpoch := epoch + 1
+	candidates := make(map[common_signature.PublicKey]*responses.RankedEntity)
+	ineligible := make(map[common_signature.PublicKey]bool)
+	for _, node := range nodes {
+		if !node.HasRoles(common_node.RoleValidator) ||
+			node.IsExpired(uint64(nextEpoch)) ||
+			ineligible[node.EntityID] {
+			continue
+		}
+		status, err := ro.GetNodeStatus(ctx, &registry.IDQuery{
+			Height: height, ID: node.ID})
+		if err != nil {
+			encodeResponse(ctx, w, responses.ErrorResponse{
+				Error: "Failed to get Node Status!"})
+			log.Error.Println("Request at /api/scheduler/expectedvalidators"+
+				" failed to retrieve Node Status : ", err)
+			return
+		}
+		if status.IsFrozen() {
+			continue
+		}
+
+		candidate, ok := candidates[node.EntityID]
+		if !ok {
+			account, err := so.Account(ctx, &staking.OwnerQuery{
+				Height: height, Owner: staking.NewAddress(node.EntityID)})
+			if err != nil {
+				encodeResponse(ctx, w, responses.ErrorResponse{
+					Error: "Failed to get Account!"})
+				log.Error.Println("Request at /api/scheduler/"+
+					"expectedvalidators failed to retrieve Account : ", err)
+				return
+			}
+			if !params.DebugBypassStake && account.Escrow.CheckStakeClaims(
+				stakingParams.Thresholds) != nil {
+				ineligible[node.EntityID] = true
+				continue
+			}
+			candidate = &responses.RankedEntity{
+				EntityID:      node.EntityID,
+				EscrowBalance: account.Escrow.Active.Balance,
+				Nodes:         []common_signature.PublicKey{},
+			}
+			candidates[node.EntityID] = candidate
+		}
+		candidate.Nodes = append(candidate.Nodes, node.ID)
+	}
+
+	expected, err := rankValidatorEntities(candidates, params)
+	if err != nil {
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to compute Voting Power!"})
+		log.Error.Println("Request at /api/scheduler/expectedvalidators "+
+			"failed to compute Voting Power : ", err)
+		return
+	}
+	expected.Height = height
+	expected.Epoch = nextEpoch
+	expected.Parameters = params
+	if entityID != nil {
+		for i := range expected.Entities {
+			if expected.Entities[i].EntityID.Equal(*entityID) {
+				expected.Entity = &expected.Entities[i]
+			}
+		}
+	}
+
+	// Responding with the expected validator set
+	log.Debug.Println("Request at /api/scheduler/expectedvalidators " +
+		"responding with Expected Validators!")
+	encodeResponse(ctx, w, responses.ExpectedValidatorsResponse{
+		ExpectedValidators: expected})
+}
+
+// rankValidatorEntities sorts candidate entities by descending escrow
+// balance and elects nodes from them in that order until the validator set
+// is full. The scheduler breaks ties between equal balances using the
+// beacon, so entities with the same balance keep the order of their IDs.
+func rankValidatorEntities(
+	candidates map[common_signature.PublicKey]*responses.RankedEntity,
+	params *scheduler.ConsensusParameters) (*responses.ExpectedValidators,
+	error) {
+
+	entities := make([]responses.RankedEntity, 0, len(candidates))
+	for _, candidate := range candidates {
+		entities = append(entities, *candidate)
+	}
+	sort.Slice(entities, func(i, j int) bool {
+		cmp := entities[i].EscrowBalance.Cmp(&entities[j].EscrowBalance)
+		if cmp != 0 {
+			return cmp > 0
+		}
+		return bytes.Compare(entities[i].EntityID[:],
+			entities[j].EntityID[:]) < 0
+	})
+
+	expected := &responses.ExpectedValidators{}
+	elected := 0
+	lastElected := -1
+	for i := range entities {
+		entity := &entities[i]
+		entity.Rank = i + 1
+		if elected >= params.MaxValidators {
+			continue
+		}
+
+		entity.ElectedNodes = len(entity.Nodes)
+		if entity.ElectedNodes > params.MaxValidatorsPerEntity {
+			entity.ElectedNodes = params.MaxValidatorsPerEntity
+		}
+		if entity.ElectedNodes > params.MaxValidators-elected {
+			entity.ElectedNodes = params.MaxValidators - elected
+		}
+		if entity.ElectedNodes == 0 {
+			continue
+		}
+		entity.Elected = true
+		elected += entity.ElectedNodes
+		lastElected = i
+
+		power := int64(1)
+		if !params.DebugBypassStake {
+			var err error
+			power, err = scheduler.VotingPowerFromStake(
+				&entity.EscrowBalance, params.VotingPowerDistribution)
+			if err != nil {
+				return nil, err
+			}
+		}
+		entity.VotingPower = power
+	}
+	expected.ElectedValidators = elected
+
+	// The margin of an entity is how far its balance is above or below the
+	// balance of the last entity to be elected
+	if lastElected != -1 {
+		expected.CutoffBalance = entities[lastElected].EscrowBalance.Clone()
+		for i := range entities {
+			entity := &entities[i]
+			margin := entity.EscrowBalance.Clone()
+			entity.AboveCutoff = margin.Cmp(expected.CutoffBalance) >= 0
+			if entity.AboveCutoff {
+				_ = margin.Sub(expected.CutoffBalance)
+			} else {
+				margin = expected.CutoffBalance.Clone()
+				_ = margin.Sub(&entity.EscrowBalance)
+			}
+			entity.Margin = *margin
+		}
+	}
+	expected.Entities = entities
+	return expected, nil
+}
@@ -9,6 +9,8 @@ import (
 
 	hdl "github.com/SimplyVC/oasis_api_server/src/handlers"
 	"github.com/SimplyVC/oasis_api_server/src/responses"
+	common_signature "github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	common_quantity "github.com/oasisprotocol/oasis-core/go/common/quantity"
 	scheduler_api "github.com/oasisprotocol/oasis-core/go/scheduler/api"
 )
 
@@ -215,3 +217,624 @@ func Test_GetSchedulerStateToGenesis(t *testing.T) {
 			strings.TrimSpace(rr.Body.String()), expected)
 	}
 }
+
+func Test_GetNodeCommittees_BadNode(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/scheduler/nodecommittees", nil)
+	q := req.URL.Query()
+	q.Add("name", "Unicorn")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetNodeCommittees)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Node name requested doesn't exist"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetNodeCommittees_InvalidHeight(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/scheduler/nodecommittees", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	q.Add("height", "Unicorn")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetNodeCommittees)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Unexpected value found, height needs to be a string representing an int!"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetNodeCommittees_EmptyNodeID(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/scheduler/nodecommittees", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetNodeCommittees)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"NodeID can't be empty!"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetNodeCommittees_InvalidNodeID(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/scheduler/nodecommittees", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	q.Add("nodeID", "Unicorn")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetNodeCommittees)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Failed to UnmarshalText into Public Key."}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetNodeCommittees_InvalidEpochs(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/scheduler/nodecommittees", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	q.Add("nodeID", "5RIMVgnsN1D/HdvNxXCpE+lWH5U/SGYUrYsvhsTMbyA=")
+	q.Add("epochs", "101")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetNodeCommittees)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Unexpected value found, epochs needs to be a string representing an int between 0 and 100!"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetNodeCommittees(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/scheduler/nodecommittees", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	q.Add("nodeID", "5RIMVgnsN1D/HdvNxXCpE+lWH5U/SGYUrYsvhsTMbyA=")
+	q.Add("epochs", "2")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetNodeCommittees)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := "result"
+
+	response := &responses.NodeCommitteesResponse{}
+
+	err := json.Unmarshal([]byte(rr.Body.String()), response)
+	if err != nil {
+		t.Errorf("Failed to unmarshall data")
+	}
+
+	if strings.Contains(strings.TrimSpace(rr.Body.String()), expected) != true {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			strings.TrimSpace(rr.Body.String()), expected)
+	}
+}
+
+func Test_GetValidatorsDiff_BadNode(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/scheduler/validatorsdiff", nil)
+	q := req.URL.Query()
+	q.Add("name", "Unicorn")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetValidatorsDiff)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Node name requested doesn't exist"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetValidatorsDiff_InvalidHeight(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/scheduler/validatorsdiff", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	q.Add("from_height", "Unicorn")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetValidatorsDiff)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Unexpected value found, height needs to be a string representing an int!"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetValidatorsDiff_InvalidEpoch(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/scheduler/validatorsdiff", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	q.Add("from_epoch", "Unicorn")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetValidatorsDiff)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Unexpected value found, epoch needs to be a string representing an unsigned int!"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetValidatorsDiff_MissingFrom(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/scheduler/validatorsdiff", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetValidatorsDiff)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Either a height or an epoch is required to compare from, and at most one of them to compare to!"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetValidatorsDiff_HeightAndEpoch(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/scheduler/validatorsdiff", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	q.Add("from_height", "1")
+	q.Add("to_height", "10")
+	q.Add("to_epoch", "2")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetValidatorsDiff)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Either a height or an epoch is required to compare from, and at most one of them to compare to!"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetValidatorsDiff(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/scheduler/validatorsdiff", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	q.Add("from_height", "1")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetValidatorsDiff)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := "result"
+
+	response := &responses.ValidatorsDiffResponse{}
+
+	err := json.Unmarshal([]byte(rr.Body.String()), response)
+	if err != nil {
+		t.Errorf("Failed to unmarshall data")
+	}
+
+	if strings.Contains(strings.TrimSpace(rr.Body.String()), expected) != true {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			strings.TrimSpace(rr.Body.String()), expected)
+	}
+}
+
+func Test_GetVotingPowerSeries_BadNode(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/scheduler/votingpower", nil)
+	q := req.URL.Query()
+	q.Add("name", "Unicorn")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetVotingPowerSeries)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Node name requested doesn't exist"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetVotingPowerSeries_EmptyNodeID(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/scheduler/votingpower", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetVotingPowerSeries)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"NodeID can't be empty!"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetVotingPowerSeries_InvalidNodeID(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/scheduler/votingpower", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	q.Add("nodeID", "Unicorn")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetVotingPowerSeries)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Failed to UnmarshalText into Public Key."}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetVotingPowerSeries_InvalidEpoch(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/scheduler/votingpower", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	q.Add("nodeID", "5RIMVgnsN1D/HdvNxXCpE+lWH5U/SGYUrYsvhsTMbyA=")
+	q.Add("to_epoch", "Unicorn")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetVotingPowerSeries)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Unexpected value found, epoch needs to be a string representing an unsigned int!"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetVotingPowerSeries(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/scheduler/votingpower", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	q.Add("nodeID", "5RIMVgnsN1D/HdvNxXCpE+lWH5U/SGYUrYsvhsTMbyA=")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetVotingPowerSeries)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := "result"
+
+	response := &responses.VotingPowerSeriesResponse{}
+
+	err := json.Unmarshal([]byte(rr.Body.String()), response)
+	if err != nil {
+		t.Errorf("Failed to unmarshall data")
+	}
+
+	if strings.Contains(strings.TrimSpace(rr.Body.String()), expected) != true {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			strings.TrimSpace(rr.Body.String()), expected)
+	}
+}
+
+func Test_GetExpectedValidators_BadNode(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/scheduler/expectedvalidators", nil)
+	q := req.URL.Query()
+	q.Add("name", "Unicorn")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetExpectedValidators)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Node name requested doesn't exist"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetExpectedValidators_InvalidHeight(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/scheduler/expectedvalidators", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	q.Add("height", "Unicorn")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetExpectedValidators)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Unexpected value found, height needs to be a string representing an int!"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetExpectedValidators_InvalidEntity(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/scheduler/expectedvalidators", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	q.Add("entity", "Unicorn")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetExpectedValidators)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Failed to UnmarshalText into Public Key."}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetExpectedValidators(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/scheduler/expectedvalidators", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	q.Add("entity", "gb8SHLeDc69Elk7OTfqhtVgE2sqxrBCDQI84xKR+Bjg=")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetExpectedValidators)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := "result"
+
+	response := &responses.ExpectedValidatorsResponse{}
+
+	err := json.Unmarshal([]byte(rr.Body.String()), response)
+	if err != nil {
+		t.Errorf("Failed to unmarshall data")
+	}
+
+	if strings.Contains(strings.TrimSpace(rr.Body.String()), expected) != true {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			strings.TrimSpace(rr.Body.String()), expected)
+	}
+}
+
+// newPublicKey creates a distinct public key from a single byte
+func newPublicKey(b byte) common_signature.PublicKey {
+	var pk common_signature.PublicKey
+	pk[0] = b
+	return pk
+}
+
+// candidate is an entity taking part in the validator election, with an ID
+// derived from a single byte and a number of eligible nodes
+type candidate struct {
+	id      byte
+	balance uint64
+	nodes   int
+}
+
+func Test_RankValidatorEntities(t *testing.T) {
+	tests := []struct {
+		name          string
+		candidates    []candidate
+		maxValidators int
+		maxPerEntity  int
+		// order lists the entities by rank and elected how many of their
+		// nodes are elected
+		order   []byte
+		elected []int
+		cutoff  uint64
+	}{
+		{
+			name: "ties keep the order of entity IDs",
+			candidates: []candidate{{2, 100, 1}, {1, 100, 1},
+				{3, 50, 1}},
+			maxValidators: 2,
+			maxPerEntity:  1,
+			order:         []byte{1, 2, 3},
+			elected:       []int{1, 1, 0},
+			cutoff:        100,
+		},
+		{
+			name:          "nodes are capped per entity",
+			candidates:    []candidate{{1, 300, 3}, {2, 200, 2}},
+			maxValidators: 10,
+			maxPerEntity:  2,
+			order:         []byte{1, 2},
+			elected:       []int{2, 2},
+			cutoff:        200,
+		},
+		{
+			name:          "fewer entities than validators",
+			candidates:    []candidate{{1, 50, 1}, {2, 100, 1}},
+			maxValidators: 5,
+			maxPerEntity:  1,
+			order:         []byte{2, 1},
+			elected:       []int{1, 1},
+			cutoff:        50,
+		},
+		{
+			name: "more entities than validators",
+			candidates: []candidate{{1, 400, 1}, {2, 300, 0},
+				{3, 200, 3}, {4, 100, 1}},
+			maxValidators: 3,
+			maxPerEntity:  5,
+			order:         []byte{1, 2, 3, 4},
+			elected:       []int{1, 0, 2, 0},
+			cutoff:        200,
+		},
+	}
+
+	for _, test := range tests {
+		candidates := map[common_signature.PublicKey]*responses.RankedEntity{}
+		for _, c := range test.candidates {
+			entity := &responses.RankedEntity{
+				EntityID:      newPublicKey(c.id),
+				EscrowBalance: *common_quantity.NewFromUint64(c.balance),
+			}
+			for i := 0; i < c.nodes; i++ {
+				entity.Nodes = append(entity.Nodes,
+					newPublicKey(byte(100+i)))
+			}
+			candidates[entity.EntityID] = entity
+		}
+
+		expected, err := hdl.RankValidatorEntities(candidates,
+			&scheduler_api.ConsensusParameters{
+				MaxValidators:          test.maxValidators,
+				MaxValidatorsPerEntity: test.maxPerEntity,
+				DebugBypassStake:       true,
+			})
+		if err != nil {
+			t.Fatalf("%s : %v", test.name, err)
+		}
+
+		total := 0
+		for i, entity := range expected.Entities {
+			if entity.EntityID != newPublicKey(test.order[i]) ||
+				entity.Rank != i+1 ||
+				entity.ElectedNodes != test.elected[i] ||
+				entity.Elected != (test.elected[i] > 0) {
+				t.Errorf("%s : entity %d got %+v", test.name, i, entity)
+			}
+			total += test.elected[i]
+		}
+		cutoff := common_quantity.NewFromUint64(test.cutoff)
+		if len(expected.Entities) != len(test.order) ||
+			expected.ElectedValidators != total ||
+			expected.CutoffBalance.Cmp(cutoff) != 0 {
+			t.Errorf("%s : got %d entities, %d validators and cutoff %v",
+				test.name, len(expected.Entities),
+				expected.ElectedValidators, expected.CutoffBalance)
+		}
+	}
+}
@@ -2,7 +2,6 @@ package handlers
 
 import (
 	"context"
-	"encoding/json"
 	"net/http"
 
 	"google.golang.org/grpc"
@@ -14,13 +13,17 @@ import (
 )
 
 // loadSentryClient loads sentry client and returns it
-func loadSentryClient(socket string, tls string) (*grpc.ClientConn,
-	sentry.Backend) {
+func loadSentryClient(ctx context.Context, socket string, tls string) (
+	*grpc.ClientConn, sentry.Backend) {
+
+	log := lgr.FromContext(ctx)
 
 	// Attempt to load connection with sentry client
+	span := startConnect(ctx, "sentry", socket)
 	connection, sentryClient, err := rpc.SentryClient(socket, tls)
+	endConnect(ctx, span, connection, err)
 	if err != nil {
-		lgr.Error.Println(
+		log.Error.Println(
 			"Failed to establish connection to sentry client : ", err)
 		return nil, nil
 	}
@@ -34,46 +37,49 @@ func GetSentryAddresses(w http.ResponseWriter, r *http.Request) {
 	// Add header so that received knows they're receiving JSON
 	w.Header().Add("Content-Type", "application/json")
 
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
 	// Retrieving name of sentry from query request
 	nodeName := r.URL.Query().Get("name")
-	confirmation, extURL, tlsPath := checkSentryData(nodeName)
+	confirmation, extURL, tlsPath := checkSentryData(ctx, nodeName)
 	if !confirmation  {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Sentry name requested doesn't exist"})
 		return
 	}
 
 	// Attempt to load connection with sentry client
-	connection, sy := loadSentryClient(extURL, tlsPath)
+	connection, sy := loadSentryClient(ctx, extURL, tlsPath)
 
 	// Close connection once code underneath executes
-	defer connection.Close()
+	defer closeConnection(connection)
 
 	// If null object was retrieved send response
 	if sy == nil {
 
 		// Stop code here faild to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to establish connection using url : " + extURL})
 		return
 	}
 
 	// Retrieve addresses connected to sentry
-	sentryAddresses, err := sy.GetAddresses(context.Background())
+	sentryAddresses, err := sy.GetAddresses(ctx)
 	if err != nil {
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to get Sentry AddressesS!"})
-		lgr.Error.Println(
+		log.Error.Println(
 			"Request at /api/sentry/addresses failed to get addresses : ", err)
 		return
 	}
 
 	// Responding with addresses connected to a sentry
 	// retrieved from sentry client
-	lgr.Info.Println(
+	log.Debug.Println(
 		"Request at /api/sentry/addresses responding with Sentry Addresses!")
-	json.NewEncoder(w).Encode(responses.SentryResponse{
+	encodeResponse(ctx, w, responses.SentryResponse{
 		SentryAddresses: sentryAddresses})
 }
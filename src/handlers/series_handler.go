@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"net/http"
+
+	lgr "github.com/SimplyVC/oasis_api_server/src/logger"
+	"github.com/SimplyVC/oasis_api_server/src/responses"
+	"github.com/SimplyVC/oasis_api_server/src/sampler"
+)
+
+// GetStakingSeries returns the recorded total supply, common pool and fees
+// of a node over a height or time range.
+func GetStakingSeries(w http.ResponseWriter, r *http.Request) {
+
+	// Add header so that received knows they're receiving JSON
+	w.Header().Add("Content-Type", "application/json")
+
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
+	// Retrieving name of node from query request
+	nodeName := r.URL.Query().Get("name")
+	confirmation, _ := checkNodeName(ctx, nodeName)
+	if !confirmation {
+
+		// Stop code here no need to look up samples and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Node name requested doesn't exist"})
+		return
+	}
+
+	// Retrieving height range from query request, missing heights leave
+	// the range open
+	fromHeight := checkHeight(ctx, r.URL.Query().Get("from_height"))
+	toHeight := checkHeight(ctx, r.URL.Query().Get("to_height"))
+	if fromHeight == -1 || toHeight == -1 {
+
+		// Stop code here no need to look up samples and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Unexpected value found, height needs to be a string representing an int!"})
+		return
+	}
+
+	// Retrieving time range from query request
+	fromTime, okFrom := checkTime(ctx, r.URL.Query().Get("from_time"))
+	toTime, okTo := checkTime(ctx, r.URL.Query().Get("to_time"))
+	if !okFrom || !okTo {
+
+		// Stop code here no need to look up samples and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Unexpected value found, time needs to be RFC3339 or a unix timestamp!"})
+		return
+	}
+
+	// Retrieving maximum number of points from query request
+	maxPoints := checkAmount(ctx, r.URL.Query().Get("max_points"))
+	if maxPoints < 0 {
+
+		// Stop code here no need to look up samples and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Unexpected value found, max_points needs to be a string representing a positive int!"})
+		return
+	}
+
+	store, ok := sampler.GetStore(nodeName)
+	if !ok {
+		log.Warning.Printf("Request at /api/staking/series failed, node %s "+
+			"isn't being sampled", nodeName)
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Node isn't being sampled, check if the sampler is enabled!"})
+		return
+	}
+
+	samples := store.Range(sampler.Query{
+		FromHeight: fromHeight,
+		ToHeight:   toHeight,
+		FromTime:   fromTime,
+		ToTime:     toTime,
+	})
+
+	// Respond with the samples reduced to the number of points requested
+	log.Debug.Println("Request at /api/staking/series responding with " +
+		"Series!")
+	encodeResponse(ctx, w, responses.SeriesResponse{
+		Series: sampler.Downsample(samples, int(maxPoints))})
+}
@@ -10,17 +10,23 @@ import (
 	lgr "github.com/SimplyVC/oasis_api_server/src/logger"
 	"github.com/SimplyVC/oasis_api_server/src/responses"
 	"github.com/SimplyVC/oasis_api_server/src/rpc"
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
 	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
 	common_signature "github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 )
 
 // loadStakingClient loads staking client and returns it
-func loadStakingClient(socket string) (*grpc.ClientConn, staking.Backend) {
+func loadStakingClient(ctx context.Context, socket string) (
+	*grpc.ClientConn, staking.Backend) {
+
+	log := lgr.FromContext(ctx)
 
 	// Attempt to load connection with staking client
+	span := startConnect(ctx, "staking", socket)
 	connection, stakingClient, err := rpc.StakingClient(socket)
+	endConnect(ctx, span, connection, err)
 	if err != nil {
-		lgr.Error.Println("Failed to establish connection to staking client : ",
+		log.Error.Println("Failed to establish connection to staking client : ",
 			err)
 		return nil, nil
 	}
@@ -33,55 +39,59 @@ func GetTotalSupply(w http.ResponseWriter, r *http.Request) {
 	// Add header so that received knows they're receiving JSON
 	w.Header().Add("Content-Type", "application/json")
 
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
 	// Retrieving name of node from query request
 	nodeName := r.URL.Query().Get("name")
-	confirmation, socket := checkNodeName(nodeName)
+	confirmation, socket := checkNodeName(ctx, nodeName)
 	if !confirmation  {
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Node name requested doesn't exist"})
 		return
 	}
 
 	recvHeight := r.URL.Query().Get("height")
-	height := checkHeight(recvHeight)
+	height := checkHeight(ctx, recvHeight)
 	if height == -1 {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Unexpected value found, height needs to be a string representing an int!"})
 		return
 	}
 
 	// Attempt to load connection with staking client
-	connection, so := loadStakingClient(socket)
+	connection, so := loadStakingClient(ctx, socket)
 
 	// Close connection once code underneath executes
-	defer connection.Close()
+	defer closeConnection(connection)
 
 	// If null object was retrieved send response
 	if so == nil {
 
 		// Stop code here faild to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to establish connection using socket : " + socket})
 		return
 	}
 
 	// Using Oasis API to return total supply of tokens at specific block height
-	totalSupply, err := so.TotalSupply(context.Background(), height)
+	totalSupply, err := so.TotalSupply(ctx, height)
 	if err != nil {
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to get TotalSupply!"})
-		lgr.Error.Println(
+		log.Error.Println(
 			"Request at /api/staking/totalsupply failed to retrieve "+
 				"totalsupply : ", err)
 		return
 	}
 
-	lgr.Info.Println("Request at /api/staking/totalsupply responding with " +
+	log.Debug.Println("Request at /api/staking/totalsupply responding with " +
 		"TotalSupply!")
-	json.NewEncoder(w).Encode(responses.QuantityResponse{Quantity: totalSupply})
+	respondWithUnits(ctx, w, so, r.URL.Query().Get("units"), totalSupply,
+		responses.QuantityResponse{Quantity: totalSupply})
 }
 
 // GetCommonPool returns common pool balance at block height
@@ -90,58 +100,62 @@ func GetCommonPool(w http.ResponseWriter, r *http.Request) {
 	// Add header so that received knows they're receiving JSON
 	w.Header().Add("Content-Type", "application/json")
 
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
 	// Retrieving name of node from query request
 	nodeName := r.URL.Query().Get("name")
-	confirmation, socket := checkNodeName(nodeName)
+	confirmation, socket := checkNodeName(ctx, nodeName)
 	if !confirmation  {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Node name requested doesn't exist"})
 		return
 	}
 
 	// Retrieving height from query request
 	recvHeight := r.URL.Query().Get("height")
-	height := checkHeight(recvHeight)
+	height := checkHeight(ctx, recvHeight)
 	if height == -1 {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Unexpected value found, height needs to be a string representing an int!"})
 		return
 	}
 
 	// Attempt to load connection with staking client
-	connection, so := loadStakingClient(socket)
+	connection, so := loadStakingClient(ctx, socket)
 
 	// Close connection once code underneath executes
-	defer connection.Close()
+	defer closeConnection(connection)
 
 	// If null object was retrieved send response
 	if so == nil {
 
 		// Stop code here faild to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to establish connection using socket : " + socket})
 		return
 	}
 
 	// Return common pool at specific block height
-	commonPool, err := so.CommonPool(context.Background(), height)
+	commonPool, err := so.CommonPool(ctx, height)
 	if err != nil {
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to get Common Pool!"})
 
-		lgr.Error.Println(
+		log.Error.Println(
 			"Request at /api/staking/commonpool failed to retrieve common "+
 				"pool : ", err)
 		return
 	}
 
-	lgr.Info.Println("Request at /api/staking/commonpool responding with " +
+	log.Debug.Println("Request at /api/staking/commonpool responding with " +
 		"Common Pool!")
-	json.NewEncoder(w).Encode(responses.QuantityResponse{Quantity: commonPool})
+	respondWithUnits(ctx, w, so, r.URL.Query().Get("units"), commonPool,
+		responses.QuantityResponse{Quantity: commonPool})
 }
 
 
@@ -151,59 +165,62 @@ func GetLastBlockFees(w http.ResponseWriter, r *http.Request) {
 	// Add header so that received knows they're receiving JSON
 	w.Header().Add("Content-Type", "application/json")
 
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
 	// Retrieving name of node from query request
 	nodeName := r.URL.Query().Get("name")
-	confirmation, socket := checkNodeName(nodeName)
+	confirmation, socket := checkNodeName(ctx, nodeName)
 	if !confirmation  {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Node name requested doesn't exist"})
 		return
 	}
 
 	// Retrieving height from query request
 	recvHeight := r.URL.Query().Get("height")
-	height := checkHeight(recvHeight)
+	height := checkHeight(ctx, recvHeight)
 	if height == -1 {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Unexpected value found, height needs to be a string representing an int!"})
 		return
 	}
 
 	// Attempt to load connection with staking client
-	connection, so := loadStakingClient(socket)
+	connection, so := loadStakingClient(ctx, socket)
 
 	// Close connection once code underneath executes
-	defer connection.Close()
+	defer closeConnection(connection)
 
 	// If null object was retrieved send response
 	if so == nil {
 
 		// Stop code here faild to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to establish connection using socket : " + socket})
 		return
 	}
 
 	// Return LastBlockFees at specific block height
-	lastestBlockFees, err := so.LastBlockFees(context.Background(), height)
+	lastestBlockFees, err := so.LastBlockFees(ctx, height)
 	if err != nil {
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to get last block fees!"})
 
-		lgr.Error.Println(
+		log.Error.Println(
 			"Request at /api/staking/lastblockfees failed to retrieve " +
 				"last block fees : ", err)
 		return
 	}
 
-	lgr.Info.Println("Request at /api/staking/lastblockfees responding with" +
+	log.Debug.Println("Request at /api/staking/lastblockfees responding with" +
 		" latest block fees!")
-	json.NewEncoder(w).Encode(responses.QuantityResponse{Quantity: 
-		lastestBlockFees})
+	respondWithUnits(ctx, w, so, r.URL.Query().Get("units"), lastestBlockFees,
+		responses.QuantityResponse{Quantity: lastestBlockFees})
 }
 
 // GetStakingStateToGenesis returns state of genesis file of staking client
@@ -212,59 +229,63 @@ func GetStakingStateToGenesis(w http.ResponseWriter, r *http.Request) {
 	// Add header so that received knows they're receiving JSON
 	w.Header().Add("Content-Type", "application/json")
 
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
 	// Retrieving name of node from query request
 	nodeName := r.URL.Query().Get("name")
-	confirmation, socket := checkNodeName(nodeName)
+	confirmation, socket := checkNodeName(ctx, nodeName)
 	if !confirmation  {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Node name requested doesn't exist"})
 		return
 	}
 
 	// Retrieving height from query request
 	recvHeight := r.URL.Query().Get("height")
-	height := checkHeight(recvHeight)
+	height := checkHeight(ctx, recvHeight)
 	if height == -1 {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Unexpected value found, height needs to be a string representing an int!"})
 		return
 	}
 
 	// Attempt to load connection with staking client
-	connection, so := loadStakingClient(socket)
+	connection, so := loadStakingClient(ctx, socket)
 
 	// Close connection once code underneath executes
-	defer connection.Close()
+	defer closeConnection(connection)
 
 	// If null object was retrieved send response
 	if so == nil {
 
 		// Stop code here faild to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to establish connection using socket : " + socket})
 		return
 	}
 
 	// Returning state to genesis at specific height
-	genesisStaking, err := so.StateToGenesis(context.Background(), height)
+	genesisStaking, err := so.StateToGenesis(ctx, height)
 	if err != nil {
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to get Staking Genesis State!"})
-		lgr.Error.Println(
+		log.Error.Println(
 			"Request at /api/staking/genesis failed to retrieve Staking "+
 				"Genesis State : ", err)
 		return
 	}
 
-	lgr.Info.Println(
+	log.Debug.Println(
 		"Request at /api/staking/genesis responding with Staking " +
 			"Genesis State!")
-	json.NewEncoder(w).Encode(responses.StakingGenesisResponse{
-		GenesisStaking: genesisStaking})
+	respondWithUnits(ctx, w, so, r.URL.Query().Get("units"), genesisStaking,
+		responses.StakingGenesisResponse{
+			GenesisStaking: genesisStaking})
 }
 
 // GetThreshold returns specific staking threshold by kind.
@@ -273,50 +294,53 @@ func GetThreshold(w http.ResponseWriter, r *http.Request) {
 	// Add header so that received knows they're receiving JSON
 	w.Header().Add("Content-Type", "application/json")
 
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
 	// Retrieving name of node from query request
 	nodeName := r.URL.Query().Get("name")
-	confirmation, socket := checkNodeName(nodeName)
+	confirmation, socket := checkNodeName(ctx, nodeName)
 	if !confirmation  {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Node name requested doesn't exist"})
 		return
 	}
 
 	// Retrieving height from query request
 	recvHeight := r.URL.Query().Get("height")
-	height := checkHeight(recvHeight)
+	height := checkHeight(ctx, recvHeight)
 	if height == -1 {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Unexpected value found, height needs to be a string representing an int!"})
 		return
 	}
 
 	// Retrieving kind from query request
 	recvKind := r.URL.Query().Get("kind")
-	kind := checkKind(recvKind)
+	kind := checkKind(ctx, recvKind)
 	if kind == -1 {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Unexpected value found, kind needs to be a string representing an int!"})
 		return
 	}
 
 	// Attempt to load connection with staking client
-	connection, so := loadStakingClient(socket)
+	connection, so := loadStakingClient(ctx, socket)
 
 	// Close connection once code underneath executes
-	defer connection.Close()
+	defer closeConnection(connection)
 
 	// If null object was retrieved send response
 	if so == nil {
 
 		// Stop code here faild to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to establish connection using socket : " + socket})
 		return
 	}
@@ -325,20 +349,21 @@ func GetThreshold(w http.ResponseWriter, r *http.Request) {
 	query := staking.ThresholdQuery{Height: height, Kind: staking.ThresholdKind(kind)}
 
 	// Return threshold from staking client using created query
-	threshold, err := so.Threshold(context.Background(), &query)
+	threshold, err := so.Threshold(ctx, &query)
 	if err != nil {
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to get Threshold!"})
-		lgr.Error.Println(
+		log.Error.Println(
 			"Request at /api/staking/threshold failed to retrieve "+
 				"Threshold : ", err)
 		return
 	}
 
 	// Responding with threshold quantity retrieved
-	lgr.Info.Println(
+	log.Debug.Println(
 		"Request at /api/staking/threshold responding with Threshold!")
-	json.NewEncoder(w).Encode(responses.QuantityResponse{Quantity: threshold})
+	respondWithUnits(ctx, w, so, r.URL.Query().Get("units"), threshold,
+		responses.QuantityResponse{Quantity: threshold})
 }
 
 // GetAddresses returns IDs of all accounts with non-zero general balance
@@ -347,58 +372,61 @@ func GetAddresses(w http.ResponseWriter, r *http.Request) {
 	// Add header so that received knows they're receiving JSON
 	w.Header().Add("Content-Type", "application/json")
 
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
 	// Retrieving name of node from query request
 	nodeName := r.URL.Query().Get("name")
-	confirmation, socket := checkNodeName(nodeName)
+	confirmation, socket := checkNodeName(ctx, nodeName)
 	if !confirmation  {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Node name requested doesn't exist"})
 		return
 	}
 
 	// Retrieving height from query request
 	recvHeight := r.URL.Query().Get("height")
-	height := checkHeight(recvHeight)
+	height := checkHeight(ctx, recvHeight)
 	if height == -1 {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Unexpected value found, height needs to be a string representing an int!"})
 		return
 	}
 
 	// Attempt to load connection with staking client
-	connection, so := loadStakingClient(socket)
+	connection, so := loadStakingClient(ctx, socket)
 
 	// Close connection once code underneath executes
-	defer connection.Close()
+	defer closeConnection(connection)
 
 	// If null object was retrieved send response
 	if so == nil {
 
 		// Stop code here faild to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to establish connection using socket : " + socket})
 		return
 	}
 
 	// Return addresses from staking client
-	addresses, err := so.Addresses(context.Background(), height)
+	addresses, err := so.Addresses(ctx, height)
 	if err != nil {
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to get Addresses!"})
-		lgr.Error.Println(
+		log.Error.Println(
 			"Request at /api/staking/addresses failed to retrieve Addresses : ",
 			err)
 		return
 	}
 
 	// Respond with array of all accounts
-	lgr.Info.Println("Request at /api/staking/addresses responding with " +
+	log.Debug.Println("Request at /api/staking/addresses responding with " +
 		"Addresses!")
-	json.NewEncoder(w).Encode(responses.AllAddressesResponse{AllAddresses: 
+	encodeResponse(ctx, w, responses.AllAddressesResponse{AllAddresses: 
 		addresses})
 }
 
@@ -408,16 +436,19 @@ func GetAddressFromPublicKey(w http.ResponseWriter, r *http.Request) {
 	// Add header so that received knows they're receiving JSON
 	w.Header().Add("Content-Type", "application/json")
 
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
 	// Get the public key from the query
 	var pubKey common_signature.PublicKey
 	publicKey := r.URL.Query().Get("pubKey")
 	if len(publicKey) == 0 {
 
 		// Stop code here no need to establish connection and reply
-		lgr.Warning.Println(
+		log.Warning.Println(
 			"Request at /api/staking/publickeytoaddress failed, pubKey " +
 				"can't be empty!")
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "pubKey can't be empty!"})
 		return
 	}
@@ -425,8 +456,8 @@ func GetAddressFromPublicKey(w http.ResponseWriter, r *http.Request) {
 	// Unmarshall text into public key object
 	err := pubKey.UnmarshalText([]byte(publicKey))
 	if err != nil {
-		lgr.Error.Println("Failed to UnmarshalText into PublicKey", err)
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		log.Error.Println("Failed to UnmarshalText into PublicKey", err)
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to UnmarshalText into PublicKey."})
 		return
 	}
@@ -435,9 +466,9 @@ func GetAddressFromPublicKey(w http.ResponseWriter, r *http.Request) {
 	address := staking.NewAddress(pubKey)
 
 	// Respond with  the address of the public key
-	lgr.Info.Println("Request at /api/staking/publickeytoaddress responding " +
+	log.Debug.Println("Request at /api/staking/publickeytoaddress responding " +
 		"with Address!")
-	json.NewEncoder(w).Encode(responses.AddressResponse{
+	encodeResponse(ctx, w, responses.AddressResponse{
 		Address: address})
 }
 
@@ -448,60 +479,64 @@ func GetConsensusParameters(w http.ResponseWriter, r *http.Request) {
 	// Add header so that received knows they're receiving JSON
 	w.Header().Add("Content-Type", "application/json")
 
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
 	// Retrieving name of node from query request
 	nodeName := r.URL.Query().Get("name")
-	confirmation, socket := checkNodeName(nodeName)
+	confirmation, socket := checkNodeName(ctx, nodeName)
 	if !confirmation  {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Node name requested doesn't exist"})
 		return
 	}
 
 	// Retrieving height from query request
 	recvHeight := r.URL.Query().Get("height")
-	height := checkHeight(recvHeight)
+	height := checkHeight(ctx, recvHeight)
 	if height == -1 {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Unexpected value found, height needs to be a string representing an int!"})
 		return
 	}
 
 	// Attempt to load connection with staking client
-	connection, so := loadStakingClient(socket)
+	connection, so := loadStakingClient(ctx, socket)
 
 	// Close connection once code underneath executes
-	defer connection.Close()
+	defer closeConnection(connection)
 
 	// If null object was retrieved send response
 	if so == nil {
 
 		// Stop code here faild to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to establish connection using socket : " + socket})
 		return
 	}
 
 	// Return the staking consensus parameters
-	consensusParameters, err := so.ConsensusParameters(context.Background(), 
+	consensusParameters, err := so.ConsensusParameters(ctx, 
 		height)
 	if err != nil {
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to get Addresses!"})
-		lgr.Error.Println(
+		log.Error.Println(
 			"Request at /api/staking/consensusparameters failed to retrieve " +
 			"Addresses : ",err)
 		return
 	}
 
 	// Respond with array of all accounts
-	lgr.Info.Println("Request at /api/staking/consensusparameters responding " +
+	log.Debug.Println("Request at /api/staking/consensusparameters responding " +
 		"with Addresses!")
-	json.NewEncoder(w).Encode(responses.ConsensusParametersResponse{
-		ConsensusParameters: consensusParameters})
+	respondWithUnits(ctx, w, so, r.URL.Query().Get("units"), consensusParameters,
+		responses.ConsensusParametersResponse{
+			ConsensusParameters: consensusParameters})
 }
 
 // GetAccount returns the account descriptor for the given account.
@@ -510,24 +545,27 @@ func GetAccount(w http.ResponseWriter, r *http.Request) {
 	// Add header so that received knows they're receiving JSON
 	w.Header().Add("Content-Type", "application/json")
 
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
 	// Retrieving name of node from query request
 	nodeName := r.URL.Query().Get("name")
-	confirmation, socket := checkNodeName(nodeName)
+	confirmation, socket := checkNodeName(ctx, nodeName)
 	if !confirmation  {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Node name requested doesn't exist"})
 		return
 	}
 
 	// Retrieving height from query request
 	recvHeight := r.URL.Query().Get("height")
-	height := checkHeight(recvHeight)
+	height := checkHeight(ctx, recvHeight)
 	if height == -1 {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Unexpected value found, height needs to be a string representing an int!"})
 		return
 	}
@@ -537,10 +575,10 @@ func GetAccount(w http.ResponseWriter, r *http.Request) {
 	if len(addressQuery) == 0 {
 
 		// Stop code here no need to establish connection and reply
-		lgr.Warning.Println(
+		log.Warning.Println(
 			"Request at /api/staking/account failed, address can't be " +
 				"empty!")
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "address can't be empty!"})
 		return
 	}
@@ -548,23 +586,23 @@ func GetAccount(w http.ResponseWriter, r *http.Request) {
 	// Unmarshall text into public key object
 	err := address.UnmarshalText([]byte(addressQuery))
 	if err != nil {
-		lgr.Error.Println("Failed to UnmarshalText into Address", err)
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		log.Error.Println("Failed to UnmarshalText into Address", err)
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to UnmarshalText into Address."})
 		return
 	}
 
 	// Attempt to load connection with staking client
-	connection, so := loadStakingClient(socket)
+	connection, so := loadStakingClient(ctx, socket)
 
 	// Close connection once code underneath executes
-	defer connection.Close()
+	defer closeConnection(connection)
 
 	// If null object was retrieved send response
 	if so == nil {
 
 		// Stop code here faild to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to establish connection using socket : " + socket})
 		return
 	}
@@ -573,20 +611,21 @@ func GetAccount(w http.ResponseWriter, r *http.Request) {
 	query := staking.OwnerQuery{Height: height, Owner: address}
 
 	// Retrieve account information using created query
-	account, err := so.Account(context.Background(), &query)
+	account, err := so.Account(ctx, &query)
 	if err != nil {
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to get Account!"})
-		lgr.Error.Println(
+		log.Error.Println(
 			"Request at /api/staking/account failed to retrieve Account: "+
 				"", err)
 		return
 	}
 
 	// Return account information for created query
-	lgr.Info.Println("Request at /api/staking/account responding with " +
+	log.Debug.Println("Request at /api/staking/account responding with " +
 		"Account!")
-	json.NewEncoder(w).Encode(responses.AccountResponse{Account: account})
+	respondWithUnits(ctx, w, so, r.URL.Query().Get("units"), account,
+		responses.AccountResponse{Account: account})
 }
 
 // GetDelegations returns list of delegations for given owner
@@ -595,24 +634,27 @@ func GetDelegations(w http.ResponseWriter, r *http.Request) {
 	// Add header so that received knows they're receiving JSON
 	w.Header().Add("Content-Type", "application/json")
 
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
 	// Retrieving name of node from query request
 	nodeName := r.URL.Query().Get("name")
-	confirmation, socket := checkNodeName(nodeName)
+	confirmation, socket := checkNodeName(ctx, nodeName)
 	if !confirmation  {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Node name requested doesn't exist"})
 		return
 	}
 
 	// Retrieving height from query request
 	recvHeight := r.URL.Query().Get("height")
-	height := checkHeight(recvHeight)
+	height := checkHeight(ctx, recvHeight)
 	if height == -1 {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Unexpected value found, height needs to be a string representing an int!"})
 		return
 	}
@@ -622,10 +664,10 @@ func GetDelegations(w http.ResponseWriter, r *http.Request) {
 	if len(addressQuery) == 0 {
 
 		// Stop code here no need to establish connection and reply
-		lgr.Warning.Println(
+		log.Warning.Println(
 			"Request at /api/staking/delegations failed, address can't be " +
 				"empty!")
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "address can't be empty!"})
 		return
 	}
@@ -633,23 +675,23 @@ func GetDelegations(w http.ResponseWriter, r *http.Request) {
 	// Unmarshal text into public key object
 	err := address.UnmarshalText([]byte(addressQuery))
 	if err != nil {
-		lgr.Error.Println("Failed to UnmarshalText into Address", err)
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		log.Error.Println("Failed to UnmarshalText into Address", err)
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to UnmarshalText into Address."})
 		return
 	}
 
 	// Attempt to load connection with staking client
-	connection, so := loadStakingClient(socket)
+	connection, so := loadStakingClient(ctx, socket)
 
 	// Close connection once code underneath executes
-	defer connection.Close()
+	defer closeConnection(connection)
 
 	// If null object was retrieved send response
 	if so == nil {
 
 		// Stop code here faild to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to establish connection using socket : " + socket})
 		return
 	}
@@ -658,22 +700,22 @@ func GetDelegations(w http.ResponseWriter, r *http.Request) {
 	query := staking.OwnerQuery{Height: height, Owner: address}
 
 	// Return delegations for given account query
-	delegations, err := so.DelegationsTo(context.Background(), &query)
+	delegations, err := so.DelegationsTo(ctx, &query)
 	if err != nil {
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to get Delegations!"})
 
-		lgr.Error.Println(
+		log.Error.Println(
 			"Request at /api/staking/delegations failed to retrieve "+
 				"Delegations : ", err)
 		return
 	}
 
 	// Respond with delegations for given account query
-	lgr.Info.Println("Request at /api/staking/delegations responding with " +
+	log.Debug.Println("Request at /api/staking/delegations responding with " +
 		"delegations!")
-	json.NewEncoder(w).Encode(responses.DelegationsResponse{Delegations:
-		delegations})
+	respondWithUnits(ctx, w, so, r.URL.Query().Get("units"), delegations,
+		responses.DelegationsResponse{Delegations: delegations})
 }
 
 // GetDebondingDelegations returns list of debonding delegations
@@ -683,23 +725,26 @@ func GetDebondingDelegations(w http.ResponseWriter, r *http.Request) {
 	// Add header so that received knows they're receiving JSON
 	w.Header().Add("Content-Type", "application/json")
 
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
 	// Retrieving name of node from query request
 	nodeName := r.URL.Query().Get("name")
-	confirmation, socket := checkNodeName(nodeName)
+	confirmation, socket := checkNodeName(ctx, nodeName)
 	if !confirmation  {
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Node name requested doesn't exist"})
 		return
 	}
 
 	// Retrieving height from query request
 	recvHeight := r.URL.Query().Get("height")
-	height := checkHeight(recvHeight)
+	height := checkHeight(ctx, recvHeight)
 	if height == -1 {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Unexpected value found, height needs to be a string representing an int!"})
 		return
 	}
@@ -709,10 +754,10 @@ func GetDebondingDelegations(w http.ResponseWriter, r *http.Request) {
 	if len(addressQuery) == 0 {
 
 		// Stop code here no need to establish connection and reply
-		lgr.Warning.Println(
+		log.Warning.Println(
 			"Request at /api/staking/account failed, address can't be " +
 				"empty!")
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "address can't be empty!"})
 		return
 	}
@@ -720,23 +765,23 @@ func GetDebondingDelegations(w http.ResponseWriter, r *http.Request) {
 	// Unmarshal text into public key object
 	err := address.UnmarshalText([]byte(addressQuery))
 	if err != nil {
-		lgr.Error.Println("Failed to UnmarshalText into Address", err)
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		log.Error.Println("Failed to UnmarshalText into Address", err)
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to UnmarshalText into Address."})
 		return
 	}
 
 	// Attempt to load connection with staking client
-	connection, so := loadStakingClient(socket)
+	connection, so := loadStakingClient(ctx, socket)
 
 	// Close connection once code underneath executes
-	defer connection.Close()
+	defer closeConnection(connection)
 
 	// If null object was retrieved send response
 	if so == nil {
 
 		// Stop code here faild to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to establish connection using socket : " + socket})
 		return
 	}
@@ -745,23 +790,25 @@ func GetDebondingDelegations(w http.ResponseWriter, r *http.Request) {
 	query := staking.OwnerQuery{Height: height, Owner: address}
 
 	// Retrieving debonding delegations for an account using above query
-	debondingDelegations, err := so.DebondingDelegationsTo(context.Background(),
+	debondingDelegations, err := so.DebondingDelegationsTo(ctx,
 		&query)
 	if err != nil {
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to get Debonding Delegations!"})
-		lgr.Error.Println(
+		log.Error.Println(
 			"Request at /api/staking/debondingdelegations failed to retrieve"+
 				" Debonding Delegations : ", err)
 		return
 	}
 
 	// Responding with debonding delegations for given accounts
-	lgr.Info.Println(
+	log.Debug.Println(
 		"Request at /api/staking/debondingdelegations responding with " +
 			"Debonding Delegations!")
-	json.NewEncoder(w).Encode(responses.DebondingDelegationsResponse{
-		DebondingDelegations: debondingDelegations})
+	respondWithUnits(ctx, w, so, r.URL.Query().Get("units"),
+		debondingDelegations,
+		responses.DebondingDelegationsResponse{
+			DebondingDelegations: debondingDelegations})
 }
 
 // GetEvents returns events at a specific height.
@@ -770,55 +817,282 @@ func GetEvents(w http.ResponseWriter, r *http.Request) {
 	// Add header so that received knows they're receiving JSON
 	w.Header().Add("Content-Type", "application/json")
 
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
 	// Retrieving name of node from query request
 	nodeName := r.URL.Query().Get("name")
-	confirmation, socket := checkNodeName(nodeName)
+	confirmation, socket := checkNodeName(ctx, nodeName)
 	if !confirmation  {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Node name requested doesn't exist"})
 		return
 	}
 
 	// Retrieving height from query request
 	recvHeight := r.URL.Query().Get("height")
-	height := checkHeight(recvHeight)
+	height := checkHeight(ctx, recvHeight)
 	if height == -1 {
 
 		// Stop code here no need to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Unexpected value found, height needs to be a string representing an int!"})
 		return
 	}
 
 	// Attempt to load connection with staking client
-	connection, so := loadStakingClient(socket)
+	connection, so := loadStakingClient(ctx, socket)
 
 	// Close connection once code underneath executes
-	defer connection.Close()
+	defer closeConnection(connection)
 
 	// If null object was retrieved send response
 	if so == nil {
 
 		// Stop code here faild to establish connection and reply
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to establish connection using socket : " + socket})
 		return
 	}
 
 	// Return accounts from staking client
-	events, err := so.GetEvents(context.Background(), height)
+	events, err := so.GetEvents(ctx, height)
 	if err != nil {
-		json.NewEncoder(w).Encode(responses.ErrorResponse{
+		encodeResponse(ctx, w, responses.ErrorResponse{
 			Error: "Failed to get Events!"})
-		lgr.Error.Println(
+		log.Error.Println(
 			"Request at /api/staking/events failed to retrieve Events : ", err)
 		return
 	}
 
 	// Respond with array of all accounts
-	lgr.Info.Println("Request at /api/staking/events responding with" +
+	log.Debug.Println("Request at /api/staking/events responding with" +
 		" Events!")
-	json.NewEncoder(w).Encode(responses.StakingEvents{StakingEvents: events})
+	respondWithUnits(ctx, w, so, r.URL.Query().Get("units"), events,
+		responses.StakingEvents{StakingEvents: events})
+}
+
+// GetCommission returns the commission schedule overview of an account at an
+// epoch and optionally validates a proposed amendment to it.
+func GetCommission(w http.ResponseWriter, r *http.Request) {
+
+	// Add header so that received knows they're receiving JSON
+	w.Header().Add("Content-Type", "application/json")
+
+	ctx := r.Context()
+	log := lgr.FromContext(ctx)
+
+	// Retrieving name of node from query request
+	nodeName := r.URL.Query().Get("name")
+	confirmation, socket := checkNodeName(ctx, nodeName)
+	if !confirmation {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Node name requested doesn't exist"})
+		return
+	}
+
+	// Retrieving height from query request
+	recvHeight := r.URL.Query().Get("height")
+	height := checkHeight(ctx, recvHeight)
+	if height == -1 {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Unexpected value found, height needs to be a string representing an int!"})
+		return
+	}
+
+	var address staking.Address
+	addressQuery := r.URL.Query().Get("address")
+	if len(addressQuery) == 0 {
+
+		// Stop code here no need to establish connection and reply
+		log.Warning.Println(
+			"Request at /api/staking/commission failed, address can't be " +
+				"empty!")
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "address can't be empty!"})
+		return
+	}
+
+	// Unmarshall text into address object
+	err := address.UnmarshalText([]byte(addressQuery))
+	if err != nil {
+		log.Error.Println("Failed to UnmarshalText into Address", err)
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to UnmarshalText into Address."})
+		return
+	}
+
+	// Retrieving optional epoch from query request, if it isn't given the
+	// epoch at the requested height is used
+	recvEpoch := r.URL.Query().Get("epoch")
+	epoch, ok := checkEpoch(ctx, recvEpoch)
+	if !ok {
+
+		// Stop code here no need to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Unexpected value found, epoch needs to be a string representing an unsigned int!"})
+		return
+	}
+
+	// Retrieving optional amendment from query request, it is expected to
+	// be a JSON encoded commission schedule
+	var amendment *staking.CommissionSchedule
+	amendmentQuery := r.URL.Query().Get("amendment")
+	if len(amendmentQuery) != 0 {
+		amendment = &staking.CommissionSchedule{}
+		if err := json.Unmarshal([]byte(amendmentQuery), amendment); err != nil {
+			log.Error.Println("Failed to Unmarshal amendment into "+
+				"CommissionSchedule", err)
+			encodeResponse(ctx, w, responses.ErrorResponse{
+				Error: "Failed to Unmarshal amendment into CommissionSchedule."})
+			return
+		}
+	}
+
+	// Attempt to load connection with staking client
+	connection, so := loadStakingClient(ctx, socket)
+
+	// Close connection once code underneath executes
+	defer closeConnection(connection)
+
+	// If null object was retrieved send response
+	if so == nil {
+
+		// Stop code here faild to establish connection and reply
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to establish connection using socket : " + socket})
+		return
+	}
+
+	// If no epoch was given use the epoch at the requested height
+	if epoch == beacon.EpochInvalid {
+
+		// Attempt to load connection with consensus client
+		consensusConnection, co := loadConsensusClient(ctx, socket)
+
+		// Close connection once code underneath executes
+		defer closeConnection(consensusConnection)
+
+		// If null object was retrieved send response
+		if co == nil {
+
+			// Stop code here faild to establish connection and reply
+			encodeResponse(ctx, w, responses.ErrorResponse{
+				Error: "Failed to establish connection using socket : " + socket})
+			return
+		}
+
+		epoch, err = co.Beacon().GetEpoch(ctx, height)
+		if err != nil {
+			encodeResponse(ctx, w, responses.ErrorResponse{
+				Error: "Failed to retrieve Epoch of Block!"})
+			log.Error.Println(
+				"Request at /api/staking/commission failed to retrieve "+
+					"Epoch : ", err)
+			return
+		}
+	}
+
+	// Retrieve account information which holds the commission schedule
+	query := staking.OwnerQuery{Height: height, Owner: address}
+	account, err := so.Account(ctx, &query)
+	if err != nil {
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to get Account!"})
+		log.Error.Println(
+			"Request at /api/staking/commission failed to retrieve "+
+				"Account : ", err)
+		return
+	}
+
+	// Retrieve the consensus parameters which hold the commission rules
+	consensusParameters, err := so.ConsensusParameters(ctx,
+		height)
+	if err != nil {
+		encodeResponse(ctx, w, responses.ErrorResponse{
+			Error: "Failed to get Consensus Parameters!"})
+		log.Error.Println(
+			"Request at /api/staking/commission failed to retrieve "+
+				"Consensus Parameters : ", err)
+		return
+	}
+
+	overview := commissionOverview(&account.Escrow.CommissionSchedule,
+		&consensusParameters.CommissionScheduleRules, epoch, amendment)
+
+	// Respond with the commission overview of the account
+	log.Debug.Println("Request at /api/staking/commission responding with " +
+		"Commission!")
+	respondWithUnits(ctx, w, so, r.URL.Query().Get("units"), overview,
+		responses.CommissionResponse{Commission: overview})
+}
+
+// commissionOverview splits a commission schedule into the steps in effect at
+// epoch and the steps still to come, and validates amendment against rules
+// the same way the staking backend would if it was submitted at epoch.
+func commissionOverview(schedule *staking.CommissionSchedule,
+	rules *staking.CommissionScheduleRules, epoch beacon.EpochTime,
+	amendment *staking.CommissionSchedule) *responses.CommissionOverview {
+
+	overview := &responses.CommissionOverview{
+		Epoch:           epoch,
+		RateDenominator: staking.CommissionRateDenominator,
+		CurrentRate:     schedule.CurrentRate(epoch),
+		UpcomingRates:   []staking.CommissionRateStep{},
+		UpcomingBounds:  []staking.CommissionRateBoundStep{},
+		Rules:           rules,
+	}
+	if overview.CurrentRate != nil {
+		overview.CurrentRatePercentage = staking.
+			PrettyPrintCommissionRatePercentage(*overview.CurrentRate)
+	}
+
+	for _, step := range schedule.Rates {
+		if step.Start > epoch {
+			overview.UpcomingRates = append(overview.UpcomingRates, step)
+		}
+	}
+	for i, step := range schedule.Bounds {
+		if step.Start > epoch {
+			overview.UpcomingBounds = append(overview.UpcomingBounds, step)
+			continue
+		}
+		overview.CurrentBound = &schedule.Bounds[i]
+	}
+
+	if amendment == nil {
+		return overview
+	}
+
+	// The rate change interval is used as a divisor when validating, a zero
+	// value means that the network doesn't allow commission changes at all
+	if rules.RateChangeInterval == 0 {
+		overview.Amendment = &responses.CommissionAmendmentValidation{
+			Error: "commission schedule amendments are disabled, rate " +
+				"change interval is zero"}
+		return overview
+	}
+
+	// Amend a copy so that the current schedule in the response is unchanged
+	amended := staking.CommissionSchedule{
+		Rates:  append([]staking.CommissionRateStep{}, schedule.Rates...),
+		Bounds: append([]staking.CommissionRateBoundStep{}, schedule.Bounds...),
+	}
+	err := amended.AmendAndPruneAndValidate(amendment, rules, epoch)
+	if err != nil {
+		overview.Amendment = &responses.CommissionAmendmentValidation{
+			Error: err.Error()}
+		return overview
+	}
+	overview.Amendment = &responses.CommissionAmendmentValidation{
+		Valid:             true,
+		ResultingSchedule: &amended,
+	}
+	return overview
 }
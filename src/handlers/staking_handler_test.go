@@ -9,6 +9,7 @@ import (
 
 	hdl "github.com/SimplyVC/oasis_api_server/src/handlers"
 	"github.com/SimplyVC/oasis_api_server/src/responses"
+	beacon_api "github.com/oasisprotocol/oasis-core/go/beacon/api"
 	common_quantity "github.com/oasisprotocol/oasis-core/go/common/quantity"
 	staking_api "github.com/oasisprotocol/oasis-core/go/staking/api"
 )
@@ -718,3 +719,268 @@ func Test_GetEvents(t *testing.T) {
 			strings.TrimSpace(rr.Body.String()), expected)
 	}
 }
+
+func Test_GetCommission_BadNode(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/staking/commission", nil)
+	q := req.URL.Query()
+	q.Add("name", "Unicorn")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetCommission)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Node name requested doesn't exist"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetCommission_InvalidHeight(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/staking/commission", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	q.Add("height", "Unicorn")
+
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetCommission)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Unexpected value found, height needs to be a string representing an int!"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetCommission_InvalidEpoch(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/staking/commission", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	q.Add("address", "oasis1qqqf342r78nz05dq2pa3wzh0w54k3ea49u6rqdhv")
+	q.Add("epoch", "Unicorn")
+
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetCommission)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"error":"Unexpected value found, epoch needs to be a string representing an unsigned int!"}`
+
+	if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func Test_GetCommission(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/staking/commission", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	q.Add("address", "oasis1qqqf342r78nz05dq2pa3wzh0w54k3ea49u6rqdhv")
+
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetCommission)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := "result"
+
+	commission := &responses.CommissionResponse{
+		Commission: &responses.CommissionOverview{},
+	}
+
+	err := json.Unmarshal([]byte(rr.Body.String()), commission)
+	if err != nil {
+		t.Errorf("Failed to unmarshall data")
+	}
+
+	if strings.Contains(strings.TrimSpace(rr.Body.String()), expected) != true {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			strings.TrimSpace(rr.Body.String()), expected)
+	}
+}
+
+func Test_GetTotalSupply_Units(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/api/staking/totalsupply", nil)
+	q := req.URL.Query()
+	q.Add("name", "Oasis_Local")
+	q.Add("units", "ROSE")
+
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(hdl.GetTotalSupply)
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := "result"
+
+	quantity := &struct {
+		Quantity *responses.DenominatedQuantity `json:"result"`
+	}{}
+
+	err := json.Unmarshal([]byte(rr.Body.String()), quantity)
+	if err != nil {
+		t.Errorf("Failed to unmarshall data")
+	}
+
+	if strings.Contains(strings.TrimSpace(rr.Body.String()), expected) != true {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			strings.TrimSpace(rr.Body.String()), expected)
+	}
+}
+
+// commissionStep returns a rate step starting at an epoch
+func commissionStep(start beacon_api.EpochTime,
+	rate uint64) staking_api.CommissionRateStep {
+
+	return staking_api.CommissionRateStep{Start: start,
+		Rate: *common_quantity.NewFromUint64(rate)}
+}
+
+// commissionBound returns a bound step starting at an epoch
+func commissionBound(start beacon_api.EpochTime,
+	min, max uint64) staking_api.CommissionRateBoundStep {
+
+	return staking_api.CommissionRateBoundStep{Start: start,
+		RateMin: *common_quantity.NewFromUint64(min),
+		RateMax: *common_quantity.NewFromUint64(max)}
+}
+
+func Test_CommissionOverview(t *testing.T) {
+	schedule := &staking_api.CommissionSchedule{
+		Rates: []staking_api.CommissionRateStep{
+			commissionStep(0, 10000), commissionStep(20, 20000)},
+		Bounds: []staking_api.CommissionRateBoundStep{
+			commissionBound(0, 0, 50000), commissionBound(30, 0, 40000)},
+	}
+	rules := &staking_api.CommissionScheduleRules{RateChangeInterval: 10,
+		RateBoundLead: 20, MaxRateSteps: 10, MaxBoundSteps: 10}
+
+	tests := []struct {
+		name           string
+		epoch          beacon_api.EpochTime
+		rules          *staking_api.CommissionScheduleRules
+		amendment      *staking_api.CommissionSchedule
+		rate           uint64
+		percentage     string
+		bound          beacon_api.EpochTime
+		upcomingRates  int
+		upcomingBounds int
+		valid          bool
+		err            string
+	}{
+		{name: "before rate change", epoch: 10, rules: rules, rate: 10000,
+			percentage: "10.0%", bound: 0, upcomingRates: 1,
+			upcomingBounds: 1},
+		{name: "after rate change", epoch: 25, rules: rules, rate: 20000,
+			percentage: "20.0%", bound: 0, upcomingRates: 0,
+			upcomingBounds: 1},
+		{name: "after bound change", epoch: 30, rules: rules, rate: 20000,
+			percentage: "20.0%", bound: 30, upcomingRates: 0,
+			upcomingBounds: 0},
+		{name: "valid amendment", epoch: 10, rules: rules,
+			amendment: &staking_api.CommissionSchedule{
+				Rates: []staking_api.CommissionRateStep{
+					commissionStep(40, 30000)}},
+			rate: 10000, percentage: "10.0%", bound: 0, upcomingRates: 1,
+			upcomingBounds: 1, valid: true},
+		{name: "unaligned amendment", epoch: 10, rules: rules,
+			amendment: &staking_api.CommissionSchedule{
+				Rates: []staking_api.CommissionRateStep{
+					commissionStep(45, 30000)}},
+			rate: 10000, percentage: "10.0%", bound: 0, upcomingRates: 1,
+			upcomingBounds: 1, err: "not aligned"},
+		{name: "amendment above bound", epoch: 10, rules: rules,
+			amendment: &staking_api.CommissionSchedule{
+				Rates: []staking_api.CommissionRateStep{
+					commissionStep(40, 45000)}},
+			rate: 10000, percentage: "10.0%", bound: 0, upcomingRates: 1,
+			upcomingBounds: 1, err: "greater than maximum rate"},
+		{name: "amendments disabled", epoch: 10,
+			rules: &staking_api.CommissionScheduleRules{},
+			amendment: &staking_api.CommissionSchedule{
+				Rates: []staking_api.CommissionRateStep{
+					commissionStep(40, 30000)}},
+			rate: 10000, percentage: "10.0%", bound: 0, upcomingRates: 1,
+			upcomingBounds: 1, err: "amendments are disabled"},
+	}
+
+	for _, test := range tests {
+		overview := hdl.CommissionOverview(schedule, test.rules,
+			test.epoch, test.amendment)
+
+		if overview.CurrentRate == nil ||
+			overview.CurrentRate.Cmp(common_quantity.NewFromUint64(
+				test.rate)) != 0 ||
+			overview.CurrentRatePercentage != test.percentage {
+			t.Errorf("%s: unexpected current rate %v (%s)", test.name,
+				overview.CurrentRate, overview.CurrentRatePercentage)
+		}
+		if overview.CurrentBound == nil ||
+			overview.CurrentBound.Start != test.bound {
+			t.Errorf("%s: unexpected current bound %+v", test.name,
+				overview.CurrentBound)
+		}
+		if len(overview.UpcomingRates) != test.upcomingRates ||
+			len(overview.UpcomingBounds) != test.upcomingBounds {
+			t.Errorf("%s: unexpected upcoming steps %+v %+v", test.name,
+				overview.UpcomingRates, overview.UpcomingBounds)
+		}
+
+		amendment := overview.Amendment
+		switch {
+		case test.amendment == nil:
+			if amendment != nil {
+				t.Errorf("%s: unexpected amendment %+v", test.name,
+					amendment)
+			}
+		case test.valid:
+			if amendment == nil || !amendment.Valid ||
+				amendment.ResultingSchedule == nil ||
+				len(amendment.ResultingSchedule.Rates) != 3 ||
+				amendment.ResultingSchedule.Rates[2].Start != 40 {
+				t.Errorf("%s: unexpected amendment %+v", test.name,
+					amendment)
+			}
+		default:
+			if amendment == nil || amendment.Valid ||
+				!strings.Contains(amendment.Error, test.err) {
+				t.Errorf("%s: unexpected amendment %+v", test.name,
+					amendment)
+			}
+		}
+	}
+
+	// Validating an amendment leaves the schedule that was given unchanged
+	if len(schedule.Rates) != 2 || schedule.Rates[1].Start != 20 {
+		t.Errorf("Amendment changed the current schedule %+v", schedule)
+	}
+}
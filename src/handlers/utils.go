@@ -1,16 +1,32 @@
 package handlers
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
 	"strconv"
 	"sync"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
 
 	"github.com/SimplyVC/oasis_api_server/src/config"
 	lgr "github.com/SimplyVC/oasis_api_server/src/logger"
+	"github.com/SimplyVC/oasis_api_server/src/tracing"
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
 )
 
 // Function to verify and retrieve sentry data
-func checkSentryData(nodeName string) (bool, string, string) {
+func checkSentryData(ctx context.Context, nodeName string) (bool, string,
+	string) {
+	log := lgr.FromContext(ctx)
+
 	mutex := &sync.RWMutex{}
 	mutex.Lock()
 	// Get Sentry IP and localtion of TLS Cert
@@ -18,14 +34,14 @@ func checkSentryData(nodeName string) (bool, string, string) {
 	for _, sentry := range allSentries {
 		// If nodeName is in configuration reply with it's websocket
 		if sentry["node_name"] == nodeName {
-			lgr.Info.Printf("Requested sentry %s was found!",
+			log.Debug.Printf("Requested sentry %s was found!",
 				nodeName)
 			return true, sentry["ext_url"], sentry["tls_path"]
 		}
 	}
 
 	// If nodeName isn't in configuration produce Log and Reply with False
-	lgr.Error.Printf("Requested sentry %s was not found, check if "+
+	log.Error.Printf("Requested sentry %s was not found, check if "+
 		"configured!", nodeName)
 
 	mutex.Unlock()
@@ -33,7 +49,9 @@ func checkSentryData(nodeName string) (bool, string, string) {
 }
 
 // Function to check if node name is in configuration and return socket for it
-func checkNodeName(nodeName string) (bool, string) {
+func checkNodeName(ctx context.Context, nodeName string) (bool, string) {
+	log := lgr.FromContext(ctx)
+
 	mutex := &sync.RWMutex{}
 	mutex.Lock()
 	// Check if nodeName is in configuration
@@ -42,14 +60,14 @@ func checkNodeName(nodeName string) (bool, string) {
 
 		// If nodeName is in configuration reply with it's websocket
 		if socket["node_name"] == nodeName {
-			lgr.Info.Printf("Requested node %s was found!",
+			log.Debug.Printf("Requested node %s was found!",
 				nodeName)
 			return true, socket["isocket_path"]
 		}
 	}
 
 	// If nodeName isn't in configuration produce Log and Reply with False
-	lgr.Error.Printf(
+	log.Error.Printf(
 		"Requested node %s was not found, check if configured!",
 		nodeName)
 
@@ -58,7 +76,10 @@ func checkNodeName(nodeName string) (bool, string) {
 }
 
 // Function to check if node name has prometheus configuration for it
-func checkNodeNamePrometheus(nodeName string) (bool, string) {
+func checkNodeNamePrometheus(ctx context.Context, nodeName string) (bool,
+	string) {
+	log := lgr.FromContext(ctx)
+
 	mutex := &sync.RWMutex{}
 	mutex.Lock()
 	// Check if nodeName is in configuration
@@ -68,14 +89,14 @@ func checkNodeNamePrometheus(nodeName string) (bool, string) {
 		// If nodeName is in configuration reply with it's prometheus
 		// url
 		if node["node_name"] == nodeName {
-			lgr.Info.Printf("Requested node %s was found!",
+			log.Debug.Printf("Requested node %s was found!",
 				nodeName)
 			return true, node["prometheus_url"]
 		}
 	}
 
 	// If nodeName isn't in configuration produce Log and Reply with False
-	lgr.Error.Printf(
+	log.Error.Printf(
 		"Requested node %s was not found, check if configured!",
 		nodeName)
 
@@ -84,7 +105,9 @@ func checkNodeNamePrometheus(nodeName string) (bool, string) {
 }
 
 // Function to check if height is valid or to set height to latest
-func checkHeight(recvHeight string) int64 {
+func checkHeight(ctx context.Context, recvHeight string) int64 {
+	log := lgr.FromContext(ctx)
+
 	mutex := &sync.RWMutex{}
 	mutex.Lock()
 	// Declare height here so that it can be set inside if statement
@@ -94,7 +117,7 @@ func checkHeight(recvHeight string) int64 {
 	//  parameter was passed use latest height
 	if len(recvHeight) == 0 {
 		height = consensus.HeightLatest
-		lgr.Info.Println("No height specified getting latest height!")
+		log.Debug.Println("No height specified getting latest height!")
 	} else {
 
 		// If height isn't empty attempt to parse it into int64
@@ -103,7 +126,7 @@ func checkHeight(recvHeight string) int64 {
 
 			// If it fails it means that string given
 			// wasn't number and return result for
-			lgr.Error.Println("Unexpected value found, required "+
+			log.Error.Println("Unexpected value found, required "+
 				"string of int but received ", recvHeight)
 			return -1
 		}
@@ -115,8 +138,68 @@ func checkHeight(recvHeight string) int64 {
 	return height
 }
 
+// Function to check if epoch is valid, an empty epoch is returned as
+// EpochInvalid so that callers can fall back to the epoch at a height
+func checkEpoch(ctx context.Context, recvEpoch string) (beacon.EpochTime,
+	bool) {
+
+	log := lgr.FromContext(ctx)
+
+	// If string is empty meaning no optional parameter was passed
+	if len(recvEpoch) == 0 {
+		log.Debug.Println("No epoch specified using epoch at height!")
+		return beacon.EpochInvalid, true
+	}
+
+	// If epoch isn't empty attempt to parse it into uint64
+	epoch, err := strconv.ParseUint(recvEpoch, 10, 64)
+	if err != nil || beacon.EpochTime(epoch) == beacon.EpochInvalid {
+		log.Error.Println("Unexpected value found, required "+
+			"string of unsigned int but received ", recvEpoch)
+		return beacon.EpochInvalid, false
+	}
+	return beacon.EpochTime(epoch), true
+}
+
+// Function to check if a proposal ID is valid, unlike heights and epochs a
+// proposal ID is required
+func checkProposalID(ctx context.Context, recvID string) (uint64, bool) {
+	log := lgr.FromContext(ctx)
+
+	id, err := strconv.ParseUint(recvID, 10, 64)
+	if err != nil {
+		log.Error.Println("Unexpected value found, required "+
+			"string of unsigned int but received ", recvID)
+		return 0, false
+	}
+	return id, true
+}
+
+// Function to check if time is valid, it accepts RFC3339 or a unix timestamp
+// in seconds and returns the zero time if none is given
+func checkTime(ctx context.Context, recvTime string) (time.Time, bool) {
+	log := lgr.FromContext(ctx)
+
+	if len(recvTime) == 0 {
+		return time.Time{}, true
+	}
+
+	if seconds, err := strconv.ParseInt(recvTime, 10, 64); err == nil {
+		return time.Unix(seconds, 0), true
+	}
+	parsed, err := time.Parse(time.RFC3339, recvTime)
+	if err != nil {
+		log.Error.Println("Unexpected value found, required RFC3339 "+
+			"or unix timestamp but received ", recvTime)
+		return time.Time{}, false
+	}
+	return parsed, true
+}
+
 // Function to check if Kind is valid
-func checkKind(recvKind string) int64 {
+func checkKind(ctx context.Context, recvKind string) int64 {
+
+	log := lgr.FromContext(ctx)
 
 	mutex := &sync.RWMutex{}
 	mutex.Lock()
@@ -128,7 +211,7 @@ func checkKind(recvKind string) int64 {
 	// was passed use latest kind therefore set kind to 0
 	if len(recvKind) == 0 {
 		kind = 0
-		lgr.Info.Println("No Kind is specified setting kind to 0!")
+		log.Debug.Println("No Kind is specified setting kind to 0!")
 	} else {
 
 		// If kind isn't empty attempt to parse it into int64
@@ -137,7 +220,7 @@ func checkKind(recvKind string) int64 {
 
 			// If it fails it means that string
 			// given wasn't number and return result for
-			lgr.Error.Println("Unexpected value found, required "+
+			log.Error.Println("Unexpected value found, required "+
 				"string of int but received ", recvKind)
 			return -1
 		}
@@ -150,7 +233,9 @@ func checkKind(recvKind string) int64 {
 }
 
 // Function to check if amount is valid
-func checkAmount(recvAmount string) int64 {
+func checkAmount(ctx context.Context, recvAmount string) int64 {
+	log := lgr.FromContext(ctx)
+
 	mutex := &sync.RWMutex{}
 	mutex.Lock()
 	// Declare amount here so that it can be set inside if statement
@@ -161,7 +246,7 @@ func checkAmount(recvAmount string) int64 {
 	// was passed use latest amount therefore set amount to 0
 	if len(recvAmount) == 0 {
 		amount = 0
-		lgr.Info.Println("No amount is specified setting amount to 0!")
+		log.Debug.Println("No amount is specified setting amount to 0!")
 	} else {
 
 		// If amount isn't empty attempt to parse it into int64
@@ -169,7 +254,7 @@ func checkAmount(recvAmount string) int64 {
 		if err != nil {
 			// If it fails it means that string
 			// given wasn't number and return result for
-			lgr.Error.Println("Unexpected value found, required "+
+			log.Error.Println("Unexpected value found, required "+
 				"string of int but received ", recvAmount)
 			return -1
 		}
@@ -179,20 +264,112 @@ func checkAmount(recvAmount string) int64 {
 }
 
 // Function to check if a Node Exporter URL exists
-func getNodeExporter() (bool, string) {
+func getNodeExporter(ctx context.Context) (bool, string) {
+	log := lgr.FromContext(ctx)
+
 	mutex := &sync.RWMutex{}
 	mutex.Lock()
 	// Check if nodeName is in the configuration
 	mainInfo := config.GetMain()
 
 	if mainInfo["api_server"]["metrics_url"] != "" {
-		lgr.Info.Println("Requested node Node Exporter was found!")
+		log.Debug.Println("Requested node Node Exporter was found!")
 		return true, mainInfo["api_server"]["metrics_url"]
 	}
 
 	// If the Node Exporter was not configured then reply with False
-	lgr.Error.Println(
+	log.Error.Println(
 		"Requested node was not found, check if configured!")
 	mutex.Unlock()
 	return false, ""
 }
+
+// httpClient records a span for every request made to Prometheus or the
+// Node Exporter and passes on its trace context
+var httpClient = &http.Client{Transport: otelhttp.NewTransport(
+	http.DefaultTransport, otelhttp.WithTracerProvider(tracing.Provider),
+	otelhttp.WithPropagators(tracing.Propagator))}
+
+// httpGet requests a URL, passing on the request ID of ctx so that the
+// request can be found in the logs of the server answering it
+func httpGet(ctx context.Context, url string) (*http.Response, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if id := lgr.RequestID(ctx); id != "" {
+		request.Header.Set(lgr.RequestIDHeader, id)
+	}
+	return httpClient.Do(request)
+}
+
+// startConnect starts a span timing how long a client of a node takes to be
+// connected, which is ended by endConnect
+func startConnect(ctx context.Context, client string,
+	socket string) trace.Span {
+
+	_, span := tracing.Start(ctx, "connect "+client+" client",
+		attribute.String("socket", socket))
+	return span
+}
+
+// connectSpanTimeout bounds how long a connect span waits for the dial
+const connectSpanTimeout = 30 * time.Second
+
+// endConnect ends a span started by startConnect. Clients only dial the node
+// once they are used, so while tracing the span is ended in the background
+// once the connection is ready or failed, for it to time the dial without
+// holding up the request.
+func endConnect(ctx context.Context, span trace.Span,
+	conn *grpc.ClientConn, err error) {
+
+	if err != nil || !span.IsRecording() {
+		tracing.End(span, err)
+		return
+	}
+
+	// The dial outlives the request context when the request finishes first
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx),
+		connectSpanTimeout)
+	go func() {
+		defer cancel()
+		tracing.End(span, waitForConnection(ctx, conn))
+	}()
+}
+
+// waitForConnection connects a connection and waits until it is ready, it
+// fails once connecting failed or ctx is done
+func waitForConnection(ctx context.Context, conn *grpc.ClientConn) error {
+	conn.Connect()
+	for {
+		state := conn.GetState()
+		switch state {
+		case connectivity.Ready:
+			return nil
+		case connectivity.TransientFailure, connectivity.Shutdown:
+			return fmt.Errorf("connection to node is in state %s", state)
+		}
+		if !conn.WaitForStateChange(ctx, state) {
+			return ctx.Err()
+		}
+	}
+}
+
+// closeConnection closes a connection to a node, connections that failed to
+// be established are nil and left as they are
+func closeConnection(conn *grpc.ClientConn) {
+	if conn != nil {
+		conn.Close()
+	}
+}
+
+// encodeResponse writes a response as JSON, in a span of its own as
+// encoding large documents such as the genesis can take a while
+func encodeResponse(ctx context.Context, w http.ResponseWriter,
+	response interface{}) error {
+
+	_, span := tracing.Start(ctx, "encode response")
+	err := json.NewEncoder(w).Encode(response)
+	tracing.End(span, err)
+	return err
+}
@@ -0,0 +1,107 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	hdl "github.com/SimplyVC/oasis_api_server/src/handlers"
+	"github.com/SimplyVC/oasis_api_server/src/rpc"
+	"github.com/SimplyVC/oasis_api_server/src/tracing"
+)
+
+// dialDelay is how long the test node takes to accept a connection
+const dialDelay = 200 * time.Millisecond
+
+// slowListener accepts connections after dialDelay, the way a node behind a
+// slow network does
+type slowListener struct {
+	net.Listener
+}
+
+func (l slowListener) Accept() (net.Conn, error) {
+	time.Sleep(dialDelay)
+	return l.Listener.Accept()
+}
+
+func Test_EndConnect_Dial(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer()
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go server.Serve(slowListener{listener})
+	defer server.Stop()
+
+	// The stdout exporter writes to the standard output it is created with
+	file, err := os.Create(filepath.Join(t.TempDir(), "spans.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	stdout := os.Stdout
+	os.Stdout = file
+	err = tracing.Configure(&tracing.Settings{Enabled: true,
+		Exporter: tracing.ExporterStdout, SampleRate: 1})
+	os.Stdout = stdout
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	address := listener.Addr().String()
+	start := time.Now()
+	span := hdl.StartConnect(ctx, "health", address)
+	conn, err := rpc.Connect(address)
+	hdl.EndConnect(ctx, span, conn, err)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// The request doesn't wait for the dial, which the span still times
+	// after the request is done
+	if elapsed := time.Since(start); elapsed >= dialDelay {
+		t.Errorf("Ending connect span took %s", elapsed)
+	}
+	cancel()
+	for deadline := time.Now().Add(5 * time.Second); span.IsRecording(); {
+		if time.Now().After(deadline) {
+			t.Fatal("Connect span wasn't ended")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	tracing.Close()
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	var connect struct {
+		Name      string
+		StartTime time.Time
+		EndTime   time.Time
+		Status    struct {
+			Code string
+		}
+	}
+	if err := json.NewDecoder(file).Decode(&connect); err != nil {
+		t.Fatal(err)
+	}
+
+	// The span lasts until the node accepted the connection
+	if connect.Name != "connect health client" ||
+		connect.Status.Code == "Error" ||
+		connect.EndTime.Sub(connect.StartTime) < dialDelay {
+		t.Errorf("Unexpected connect span %+v", connect)
+	}
+}
@@ -0,0 +1,198 @@
+package incidents
+
+import (
+	"fmt"
+	"time"
+
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+// Types of incidents that are detected
+const (
+	TypeSlashing             = "slashing"
+	TypeFreeze               = "freeze"
+	TypeUnfreeze             = "unfreeze"
+	TypeRegistrationExpiring = "registration_expiring"
+	TypeRegistrationExpired  = "registration_expired"
+)
+
+// Incident is a single slashing, freeze or registration problem of a watched
+// entity or one of its nodes
+type Incident struct {
+	Height      int64                `json:"height"`
+	Time        time.Time            `json:"time"`
+	Epoch       beacon.EpochTime     `json:"epoch"`
+	Type        string               `json:"type"`
+	EntityID    signature.PublicKey  `json:"entity_id"`
+	NodeID      *signature.PublicKey `json:"node_id,omitempty"`
+	TxHash      *hash.Hash           `json:"tx_hash,omitempty"`
+	Amount      *quantity.Quantity   `json:"amount,omitempty"`
+	UntilEpoch  beacon.EpochTime     `json:"until_epoch,omitempty"`
+	Description string               `json:"description"`
+}
+
+// State is what the monitor remembers about the nodes of the watched
+// entities so that every change is reported once
+type State struct {
+	// Height is the last height whose events were processed
+	Height int64 `json:"height"`
+	// Frozen maps frozen nodes to the epoch their freeze ends
+	Frozen map[signature.PublicKey]beacon.EpochTime `json:"frozen"`
+	// Expiring maps nodes to the expiration they were warned about
+	Expiring map[signature.PublicKey]uint64 `json:"expiring"`
+	// Expired maps nodes to the expiration that was reported as expired
+	Expired map[signature.PublicKey]uint64 `json:"expired"`
+}
+
+// NewState creates an empty state
+func NewState() *State {
+	return &State{
+		Frozen:   map[signature.PublicKey]beacon.EpochTime{},
+		Expiring: map[signature.PublicKey]uint64{},
+		Expired:  map[signature.PublicKey]uint64{},
+	}
+}
+
+// NodeSnapshot holds the descriptor and status of a node at a height
+type NodeSnapshot struct {
+	Node   *node.Node
+	Status *registry.NodeStatus
+}
+
+// Block identifies the block at which incidents were detected
+type Block struct {
+	Height int64
+	Time   time.Time
+	Epoch  beacon.EpochTime
+}
+
+// newIncident creates an incident detected at block
+func newIncident(block Block, kind string, entityID signature.PublicKey,
+	description string) Incident {
+
+	return Incident{
+		Height:      block.Height,
+		Time:        block.Time,
+		Epoch:       block.Epoch,
+		Type:        kind,
+		EntityID:    entityID,
+		Description: description,
+	}
+}
+
+// SlashingIncidents finds the escrow taken from watched entities in staking
+// events. Oasis only slashes for double signing, which also freezes the
+// offending node, so the slashing is attributed to the node of the entity
+// that is frozen at the same height when there is exactly one.
+func SlashingIncidents(block Block, events []*staking.Event,
+	entities map[staking.Address]signature.PublicKey,
+	nodes []NodeSnapshot) []Incident {
+
+	incidents := []Incident{}
+	for _, event := range events {
+		if event.Escrow == nil || event.Escrow.Take == nil {
+			continue
+		}
+		take := event.Escrow.Take
+		entityID, ok := entities[take.Owner]
+		if !ok {
+			continue
+		}
+
+		incident := newIncident(block, TypeSlashing, entityID,
+			fmt.Sprintf("Escrow of %s was slashed by %s base units",
+				take.Owner, take.Amount))
+		amount := take.Amount.Clone()
+		incident.Amount = amount
+
+		// Events that weren't caused by a transaction have the hash of
+		// empty data, slashing is applied while processing evidence
+		if !event.TxHash.IsEmpty() {
+			txHash := event.TxHash
+			incident.TxHash = &txHash
+		}
+
+		var frozen []signature.PublicKey
+		for _, snapshot := range nodes {
+			if snapshot.Node.EntityID.Equal(entityID) &&
+				snapshot.Status.IsFrozen() {
+				frozen = append(frozen, snapshot.Node.ID)
+			}
+		}
+		if len(frozen) == 1 {
+			incident.NodeID = &frozen[0]
+			incident.Description += ", node " + frozen[0].String() +
+				" was frozen for double signing"
+		}
+		incidents = append(incidents, incident)
+	}
+	return incidents
+}
+
+// NodeIncidents compares the nodes of the watched entities with state,
+// reporting freezes, unfreezes and registrations that expire within
+// warningEpochs, and updates state accordingly
+func NodeIncidents(block Block, state *State,
+	entities map[signature.PublicKey]bool, nodes []NodeSnapshot,
+	warningEpochs uint64) []Incident {
+
+	incidents := []Incident{}
+	for _, snapshot := range nodes {
+		n, status := snapshot.Node, snapshot.Status
+		if !entities[n.EntityID] {
+			continue
+		}
+		nodeID := n.ID
+
+		_, wasFrozen := state.Frozen[nodeID]
+		switch {
+		case status.IsFrozen() && !wasFrozen:
+			incident := newIncident(block, TypeFreeze, n.EntityID,
+				fmt.Sprintf("Node %s was frozen until epoch %d", nodeID,
+					status.FreezeEndTime))
+			incident.NodeID = &nodeID
+			incident.UntilEpoch = status.FreezeEndTime
+			incidents = append(incidents, incident)
+			state.Frozen[nodeID] = status.FreezeEndTime
+		case !status.IsFrozen() && wasFrozen:
+			incident := newIncident(block, TypeUnfreeze, n.EntityID,
+				fmt.Sprintf("Node %s was unfrozen", nodeID))
+			incident.NodeID = &nodeID
+			incidents = append(incidents, incident)
+			delete(state.Frozen, nodeID)
+		}
+
+		epoch := uint64(block.Epoch)
+		switch {
+		case n.IsExpired(epoch):
+			if state.Expired[nodeID] == n.Expiration {
+				break
+			}
+			incident := newIncident(block, TypeRegistrationExpired,
+				n.EntityID, fmt.Sprintf("Registration of node %s expired "+
+					"at epoch %d", nodeID, n.Expiration))
+			incident.NodeID = &nodeID
+			incident.UntilEpoch = beacon.EpochTime(n.Expiration)
+			incidents = append(incidents, incident)
+			state.Expired[nodeID] = n.Expiration
+		case n.Expiration <= epoch+warningEpochs:
+			if state.Expiring[nodeID] == n.Expiration {
+				break
+			}
+			incident := newIncident(block, TypeRegistrationExpiring,
+				n.EntityID, fmt.Sprintf("Registration of node %s expires "+
+					"at epoch %d", nodeID, n.Expiration))
+			incident.NodeID = &nodeID
+			incident.UntilEpoch = beacon.EpochTime(n.Expiration)
+			incidents = append(incidents, incident)
+			state.Expiring[nodeID] = n.Expiration
+		}
+	}
+	return incidents
+}
@@ -0,0 +1,297 @@
+package incidents_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/claudetech/ini"
+
+	"github.com/SimplyVC/oasis_api_server/src/incidents"
+	lgr "github.com/SimplyVC/oasis_api_server/src/logger"
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+func TestMain(m *testing.M) {
+	lgr.SetLogger(os.Stdout, os.Stdout, os.Stderr)
+	os.Exit(m.Run())
+}
+
+// newKey creates a distinct public key from a single byte
+func newKey(b byte) signature.PublicKey {
+	var pk signature.PublicKey
+	pk[0] = b
+	return pk
+}
+
+var (
+	entityID  = newKey(1)
+	nodeID    = newKey(2)
+	otherID   = newKey(3)
+	entities  = map[signature.PublicKey]bool{entityID: true}
+	addresses = map[staking.Address]signature.PublicKey{
+		staking.NewAddress(entityID): entityID}
+	block = incidents.Block{
+		Height: 100,
+		Time:   time.Unix(600, 0).UTC(),
+		Epoch:  10,
+	}
+)
+
+// newSnapshot creates a node of an entity with a freeze end and expiration
+func newSnapshot(id signature.PublicKey, entity signature.PublicKey,
+	freezeEnd beacon.EpochTime, expiration uint64) incidents.NodeSnapshot {
+
+	return incidents.NodeSnapshot{
+		Node:   &node.Node{ID: id, EntityID: entity, Expiration: expiration},
+		Status: &registry.NodeStatus{FreezeEndTime: freezeEnd},
+	}
+}
+
+func TestSlashingIncidents(t *testing.T) {
+	var emptyHash hash.Hash
+	emptyHash.Empty()
+	events := []*staking.Event{
+		{Height: 100, TxHash: emptyHash, Escrow: &staking.EscrowEvent{
+			Take: &staking.TakeEscrowEvent{
+				Owner:  staking.NewAddress(entityID),
+				Amount: *quantity.NewFromUint64(500),
+			}}},
+		{Height: 100, TxHash: emptyHash, Escrow: &staking.EscrowEvent{
+			Take: &staking.TakeEscrowEvent{
+				Owner:  staking.NewAddress(otherID),
+				Amount: *quantity.NewFromUint64(500),
+			}}},
+	}
+	nodes := []incidents.NodeSnapshot{
+		newSnapshot(nodeID, entityID, 20, 50),
+		newSnapshot(otherID, entityID, 0, 50),
+	}
+
+	found := incidents.SlashingIncidents(block, events, addresses, nodes)
+	if len(found) != 1 {
+		t.Fatalf("Got %v incidents want 1", len(found))
+	}
+	incident := found[0]
+	if incident.Type != incidents.TypeSlashing ||
+		!incident.EntityID.Equal(entityID) {
+		t.Errorf("Got incident %+v", incident)
+	}
+	if incident.NodeID == nil || !incident.NodeID.Equal(nodeID) {
+		t.Errorf("Slashing wasn't attributed to the frozen node")
+	}
+	if incident.TxHash != nil {
+		t.Errorf("Slashing without transaction got hash %v",
+			incident.TxHash)
+	}
+	if incident.Amount == nil ||
+		incident.Amount.Cmp(quantity.NewFromUint64(500)) != 0 {
+		t.Errorf("Slashing got amount %v want 500", incident.Amount)
+	}
+}
+
+func TestNodeIncidents(t *testing.T) {
+	state := incidents.NewState()
+
+	// Frozen node and registration expiring within the warning
+	nodes := []incidents.NodeSnapshot{
+		newSnapshot(nodeID, entityID, 20, 11),
+		newSnapshot(otherID, newKey(4), 20, 11),
+	}
+	found := incidents.NodeIncidents(block, state, entities, nodes, 2)
+	if len(found) != 2 || found[0].Type != incidents.TypeFreeze ||
+		found[1].Type != incidents.TypeRegistrationExpiring {
+		t.Fatalf("Got incidents %+v want freeze and expiring", found)
+	}
+	if found[0].UntilEpoch != 20 || found[1].UntilEpoch != 11 {
+		t.Errorf("Got until epochs %v and %v want 20 and 11",
+			found[0].UntilEpoch, found[1].UntilEpoch)
+	}
+
+	// Nothing changed so nothing is reported again
+	found = incidents.NodeIncidents(block, state, entities, nodes, 2)
+	if len(found) != 0 {
+		t.Errorf("Unchanged nodes got incidents %+v", found)
+	}
+
+	// Unfrozen and expired
+	later := block
+	later.Epoch = 12
+	nodes = []incidents.NodeSnapshot{newSnapshot(nodeID, entityID, 0, 11)}
+	found = incidents.NodeIncidents(later, state, entities, nodes, 2)
+	if len(found) != 2 || found[0].Type != incidents.TypeUnfreeze ||
+		found[1].Type != incidents.TypeRegistrationExpired {
+		t.Fatalf("Got incidents %+v want unfreeze and expired", found)
+	}
+
+	// Renewed registrations are warned about again when they near expiry
+	nodes = []incidents.NodeSnapshot{newSnapshot(nodeID, entityID, 0, 14)}
+	found = incidents.NodeIncidents(later, state, entities, nodes, 2)
+	if len(found) != 1 ||
+		found[0].Type != incidents.TypeRegistrationExpiring {
+		t.Errorf("Got incidents %+v want expiring", found)
+	}
+}
+
+func TestStore_CommitAndReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "incidents.jsonl")
+	store, err := incidents.OpenStore(path)
+	if err != nil {
+		t.Fatalf("Failed to open store : %v", err)
+	}
+
+	state := store.State()
+	nodes := []incidents.NodeSnapshot{newSnapshot(nodeID, entityID, 20, 50)}
+	found := incidents.NodeIncidents(block, state, entities, nodes, 2)
+	state.Height = block.Height
+	if err := store.Commit(state, found); err != nil {
+		t.Fatalf("Failed to commit incidents : %v", err)
+	}
+	store.Close()
+
+	reopened, err := incidents.OpenStore(path)
+	if err != nil {
+		t.Fatalf("Failed to reopen store : %v", err)
+	}
+	defer reopened.Close()
+
+	restored := reopened.State()
+	if restored.Height != block.Height || restored.Frozen[nodeID] != 20 {
+		t.Errorf("Reopened store got state %+v", restored)
+	}
+	if all := reopened.Range(incidents.Query{}); len(all) != 1 {
+		t.Errorf("Reopened store got %v incidents want 1", len(all))
+	}
+	if byNode := reopened.Range(incidents.Query{NodeID: &otherID}); len(byNode) != 0 {
+		t.Errorf("Node query got %v incidents want 0", len(byNode))
+	}
+	byType := reopened.Range(incidents.Query{Type: incidents.TypeFreeze,
+		EntityID: &entityID})
+	if len(byType) != 1 {
+		t.Errorf("Type query got %v incidents want 1", len(byType))
+	}
+}
+
+func TestStore_IncompleteCommit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "incidents.jsonl")
+	store, err := incidents.OpenStore(path)
+	if err != nil {
+		t.Fatalf("Failed to open store : %v", err)
+	}
+	state := store.State()
+	state.Height = block.Height - 1
+	if err := store.Commit(state, nil); err != nil {
+		t.Fatalf("Failed to commit incidents : %v", err)
+	}
+	store.Close()
+
+	// A crash before the state file was replaced leaves the incidents of
+	// the next heights behind
+	nodes := []incidents.NodeSnapshot{newSnapshot(nodeID, entityID, 20, 50)}
+	found := incidents.NodeIncidents(block, incidents.NewState(), entities,
+		nodes, 2)
+	encoded, err := json.Marshal(found[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.Write(append(encoded, '\n'))
+	file.Close()
+
+	// Polling the heights again reports the incidents once
+	reopened, err := incidents.OpenStore(path)
+	if err != nil {
+		t.Fatalf("Failed to reopen store : %v", err)
+	}
+	state = reopened.State()
+	if state.Height != block.Height-1 || len(state.Frozen) != 0 {
+		t.Errorf("Reopened store got state %+v", state)
+	}
+	found = incidents.NodeIncidents(block, state, entities, nodes, 2)
+	state.Height = block.Height
+	if err := reopened.Commit(state, found); err != nil {
+		t.Fatalf("Failed to commit incidents : %v", err)
+	}
+	if all := reopened.Range(incidents.Query{}); len(all) != 1 {
+		t.Errorf("Reopened store got %v incidents want 1", len(all))
+	}
+	reopened.Close()
+}
+
+func TestLoadSettings(t *testing.T) {
+	settings, err := incidents.LoadSettings(map[string]map[string]string{
+		"incidents": {
+			"enabled":               "true",
+			"node_name":             "Oasis_Local",
+			"entities":              entityID.String(),
+			"expiry_warning_epochs": "5",
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to load settings : %v", err)
+	}
+	if !settings.Enabled || len(settings.Entities) != 1 ||
+		settings.WarningEpochs != 5 {
+		t.Errorf("Loaded unexpected settings %+v", settings)
+	}
+
+	invalid := []map[string]string{
+		{"enabled": "maybe"},
+		{"entities": "Unicorn"},
+		{"expiry_warning_epochs": "-1"},
+		{"poll_interval": "0"},
+		{"enabled": "true", "node_name": "Oasis_Local"},
+	}
+	for _, section := range invalid {
+		_, err := incidents.LoadSettings(map[string]map[string]string{
+			"incidents": section})
+		if err == nil {
+			t.Errorf("Settings %v were accepted", section)
+		}
+	}
+}
+
+func TestLoadSettings_EntityFormats(t *testing.T) {
+	const base64ID = "L/GJ+laBc0bj+C2fiSIpRgOSZuaDyGWyNZFf8GpVCAc="
+	var expected signature.PublicKey
+	if err := expected.UnmarshalText([]byte(base64ID)); err != nil {
+		t.Fatal(err)
+	}
+
+	// The INI parser drops the padding of base64 entity IDs
+	mainConf := map[string]map[string]string{}
+	err := ini.DecodeFile("../../config/example_user_config_main.ini",
+		&mainConf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mainConf["incidents"]["enabled"] = "true"
+	settings, err := incidents.LoadSettings(mainConf)
+	if err != nil {
+		t.Fatalf("Failed to load example settings : %v", err)
+	}
+	if len(settings.Entities) != 1 || !settings.Entities[0].Equal(expected) {
+		t.Errorf("Loaded unexpected entities %v", settings.Entities)
+	}
+
+	for _, entities := range []string{base64ID,
+		"2ff189fa56817346e3f82d9f89222946039266e683c865b235915ff06a550807"} {
+		settings, err := incidents.LoadSettings(map[string]map[string]string{
+			"incidents": {"entities": entities}})
+		if err != nil || len(settings.Entities) != 1 ||
+			!settings.Entities[0].Equal(expected) {
+			t.Errorf("Failed to load entity %s : %v", entities, err)
+		}
+	}
+}
@@ -0,0 +1,355 @@
+package incidents
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SimplyVC/oasis_api_server/src/config"
+	"github.com/SimplyVC/oasis_api_server/src/journal"
+	lgr "github.com/SimplyVC/oasis_api_server/src/logger"
+	"github.com/SimplyVC/oasis_api_server/src/rpc"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+// Default settings used when the incidents section leaves them empty
+const (
+	defaultPollInterval  = 10 * time.Second
+	defaultStorageDir    = "../data/incidents"
+	defaultWarningEpochs = 2
+)
+
+var (
+	mutex  sync.RWMutex
+	store  *Store
+	poller journal.Poller
+)
+
+// Settings configure the monitor, they are read from the incidents section
+// of the main configuration
+type Settings struct {
+	Enabled       bool
+	NodeName      string
+	Entities      []signature.PublicKey
+	WarningEpochs uint64
+	PollInterval  time.Duration
+	StorageDir    string
+}
+
+// LoadSettings parses the incidents section of the main configuration
+func LoadSettings(mainConf map[string]map[string]string) (*Settings, error) {
+	section := mainConf["incidents"]
+	settings := &Settings{
+		NodeName:      section["node_name"],
+		WarningEpochs: defaultWarningEpochs,
+		PollInterval:  defaultPollInterval,
+		StorageDir:    defaultStorageDir,
+	}
+
+	if enabled := section["enabled"]; enabled != "" {
+		value, err := strconv.ParseBool(enabled)
+		if err != nil {
+			return nil, fmt.Errorf("incidents enabled needs to be true or "+
+				"false, got %s", enabled)
+		}
+		settings.Enabled = value
+	}
+	for _, field := range strings.Split(section["entities"], ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		entityID, err := parseEntityID(field)
+		if err != nil {
+			return nil, fmt.Errorf("incidents entity %s is invalid : %w",
+				field, err)
+		}
+		settings.Entities = append(settings.Entities, entityID)
+	}
+	if epochs := section["expiry_warning_epochs"]; epochs != "" {
+		value, err := strconv.ParseUint(epochs, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("incidents expiry_warning_epochs needs "+
+				"to be a number of epochs, got %s", epochs)
+		}
+		settings.WarningEpochs = value
+	}
+	if interval := section["poll_interval"]; interval != "" {
+		seconds, err := strconv.ParseUint(interval, 10, 32)
+		if err != nil || seconds == 0 {
+			return nil, fmt.Errorf("incidents poll_interval needs to be a "+
+				"positive number of seconds, got %s", interval)
+		}
+		settings.PollInterval = time.Duration(seconds) * time.Second
+	}
+	if dir := section["storage_dir"]; dir != "" {
+		settings.StorageDir = dir
+	}
+
+	if settings.Enabled {
+		if settings.NodeName == "" {
+			return nil, fmt.Errorf("incidents node_name needs to be set")
+		}
+		if len(settings.Entities) == 0 {
+			return nil, fmt.Errorf("incidents entities needs at least one " +
+				"entity")
+		}
+	}
+	return settings, nil
+}
+
+// parseEntityID parses an entity ID written in base64 or in hex. The padding
+// of base64 IDs is optional as the INI files can't hold an equals sign in a
+// value, so it is added back when missing.
+func parseEntityID(text string) (signature.PublicKey, error) {
+	var entityID signature.PublicKey
+	if len(text) == 2*signature.PublicKeySize {
+		return entityID, entityID.UnmarshalHex(text)
+	}
+	if missing := len(text) % 4; missing != 0 {
+		text += strings.Repeat("=", 4-missing)
+	}
+	return entityID, entityID.UnmarshalText([]byte(text))
+}
+
+// GetStore returns the incident store if incidents are being monitored
+func GetStore() (*Store, bool) {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return store, store != nil
+}
+
+// Start opens the incident store and starts monitoring the configured
+// entities in the background until Stop is called
+func Start(settings *Settings) error {
+	var socket string
+	if cfg := config.Get(); cfg != nil {
+		for _, node := range cfg.Nodes {
+			if node.Name == settings.NodeName {
+				socket = node.ISocketPath
+			}
+		}
+	}
+	if socket == "" {
+		return fmt.Errorf("incidents node %s isn't configured",
+			settings.NodeName)
+	}
+
+	if err := os.MkdirAll(settings.StorageDir, 0700); err != nil {
+		return err
+	}
+	path := filepath.Join(settings.StorageDir, "incidents.jsonl")
+	opened, err := OpenStore(path)
+	if err != nil {
+		return err
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	store = opened
+
+	lgr.Info.Printf("Monitoring incidents of %d entities from node %s in %s",
+		len(settings.Entities), settings.NodeName, path)
+	poller.Go(func(ctx context.Context) {
+		run(ctx, settings, socket, opened)
+	})
+	return nil
+}
+
+// Stop stops monitoring, waits for in progress heights to be written and
+// closes the store
+func Stop() {
+	poller.Stop()
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if store != nil {
+		if err := store.Close(); err != nil {
+			lgr.Error.Printf("Failed to close incident store : %s", err)
+		}
+	}
+	store = nil
+}
+
+// monitor holds the clients and watched entities used to monitor a node
+type monitor struct {
+	consensus     consensus.ClientBackend
+	registry      registry.Backend
+	staking       staking.Backend
+	entities      map[signature.PublicKey]bool
+	addresses     map[staking.Address]signature.PublicKey
+	warningEpochs uint64
+}
+
+// run polls the node every poll interval until ctx is cancelled
+func run(ctx context.Context, settings *Settings, socket string,
+	store *Store) {
+
+	consensusConn, co, err := rpc.ConsensusClient(socket)
+	if err != nil {
+		lgr.Error.Printf("Incident monitor failed to connect to node %s : %s",
+			settings.NodeName, err)
+		return
+	}
+	defer consensusConn.Close()
+	registryConn, ro, err := rpc.RegistryClient(socket)
+	if err != nil {
+		lgr.Error.Printf("Incident monitor failed to connect to node %s : %s",
+			settings.NodeName, err)
+		return
+	}
+	defer registryConn.Close()
+	stakingConn, so, err := rpc.StakingClient(socket)
+	if err != nil {
+		lgr.Error.Printf("Incident monitor failed to connect to node %s : %s",
+			settings.NodeName, err)
+		return
+	}
+	defer stakingConn.Close()
+
+	m := &monitor{
+		consensus:     co,
+		registry:      ro,
+		staking:       so,
+		entities:      map[signature.PublicKey]bool{},
+		addresses:     map[staking.Address]signature.PublicKey{},
+		warningEpochs: settings.WarningEpochs,
+	}
+	for _, entityID := range settings.Entities {
+		m.entities[entityID] = true
+		m.addresses[staking.NewAddress(entityID)] = entityID
+	}
+
+	journal.Every(ctx, settings.PollInterval,
+		"Incident monitor failed to poll node "+settings.NodeName,
+		func(ctx context.Context) error {
+			return m.poll(ctx, store)
+		})
+}
+
+// poll scans the staking events of the heights reached since the last poll
+// for slashing and checks the nodes of the watched entities at the last of
+// them. A new store starts at the latest height.
+func (m *monitor) poll(ctx context.Context, store *Store) error {
+	status, err := m.consensus.GetStatus(ctx)
+	if err != nil {
+		return err
+	}
+
+	state := store.State()
+	start, end := journal.Heights(state.Height, status.LatestHeight, 0)
+	if end < start {
+		return nil
+	}
+
+	incidents := []Incident{}
+	for height := start; height <= end; height++ {
+		if ctx.Err() != nil {
+			return nil
+		}
+		found, err := m.scanHeight(ctx, height)
+		if err != nil {
+			return fmt.Errorf("height %d : %w", height, err)
+		}
+		incidents = append(incidents, found...)
+	}
+
+	block, err := m.block(ctx, end)
+	if err != nil {
+		return fmt.Errorf("height %d : %w", end, err)
+	}
+	nodes, err := m.nodes(ctx, end)
+	if err != nil {
+		return fmt.Errorf("height %d : %w", end, err)
+	}
+	incidents = append(incidents, NodeIncidents(*block, state, m.entities,
+		nodes, m.warningEpochs)...)
+
+	for _, incident := range incidents {
+		lgr.Warning.Printf("Incident at height %d : %s", incident.Height,
+			incident.Description)
+	}
+	state.Height = end
+	return store.Commit(state, incidents)
+}
+
+// scanHeight finds slashing of the watched entities at height
+func (m *monitor) scanHeight(ctx context.Context,
+	height int64) ([]Incident, error) {
+
+	events, err := m.staking.GetEvents(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+
+	// Look up the block and nodes only when a watched entity was slashed
+	slashed := false
+	for _, event := range events {
+		if event.Escrow != nil && event.Escrow.Take != nil {
+			if _, ok := m.addresses[event.Escrow.Take.Owner]; ok {
+				slashed = true
+			}
+		}
+	}
+	if !slashed {
+		return nil, nil
+	}
+
+	block, err := m.block(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := m.nodes(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	return SlashingIncidents(*block, events, m.addresses, nodes), nil
+}
+
+// block retrieves the time and epoch of height
+func (m *monitor) block(ctx context.Context, height int64) (*Block, error) {
+	blk, err := m.consensus.GetBlock(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	epoch, err := m.consensus.Beacon().GetEpoch(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	return &Block{Height: height, Time: blk.Time, Epoch: epoch}, nil
+}
+
+// nodes retrieves the descriptors and statuses of the nodes of the watched
+// entities at height
+func (m *monitor) nodes(ctx context.Context,
+	height int64) ([]NodeSnapshot, error) {
+
+	registered, err := m.registry.GetNodes(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := []NodeSnapshot{}
+	for _, n := range registered {
+		if !m.entities[n.EntityID] {
+			continue
+		}
+		status, err := m.registry.GetNodeStatus(ctx, &registry.IDQuery{
+			Height: height,
+			ID:     n.ID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, NodeSnapshot{Node: n, Status: status})
+	}
+	return snapshots, nil
+}
@@ -0,0 +1,87 @@
+package incidents
+
+import (
+	"fmt"
+
+	"github.com/SimplyVC/oasis_api_server/src/journal"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+)
+
+// Query selects incidents, zero values match everything
+type Query struct {
+	EntityID   *signature.PublicKey
+	NodeID     *signature.PublicKey
+	Type       string
+	FromHeight int64
+	ToHeight   int64
+}
+
+// matches checks whether incident is selected by the query
+func (q *Query) matches(incident *Incident) bool {
+	if q.EntityID != nil && !incident.EntityID.Equal(*q.EntityID) {
+		return false
+	}
+	if q.NodeID != nil && (incident.NodeID == nil ||
+		!incident.NodeID.Equal(*q.NodeID)) {
+		return false
+	}
+	if q.Type != "" && incident.Type != q.Type {
+		return false
+	}
+	if q.FromHeight != 0 && incident.Height < q.FromHeight {
+		return false
+	}
+	if q.ToHeight != 0 && incident.Height > q.ToHeight {
+		return false
+	}
+	return true
+}
+
+// Store keeps the incident history in memory and in a JSON lines file. The
+// monitor state is committed along with the incidents detected up to its
+// height so that changes aren't reported again after a restart, and
+// incidents of heights that failed to commit aren't kept twice once they are
+// scanned again.
+type Store struct {
+	journal *journal.Store[Incident]
+}
+
+// OpenStore loads the incidents kept in path and the state kept next to it,
+// creating both if they don't exist
+func OpenStore(path string) (*Store, error) {
+	opened, err := journal.Open(path, func(incident *Incident) int64 {
+		return incident.Height
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+	if err := opened.State(NewState()); err != nil {
+		opened.Close()
+		return nil, fmt.Errorf("%s state : %w", path, err)
+	}
+	return &Store{journal: opened}, nil
+}
+
+// State returns a copy of the monitor state
+func (s *Store) State() *State {
+	// The state was decoded when the store was opened or encoded by Commit
+	state := NewState()
+	_ = s.journal.State(state)
+	return state
+}
+
+// Commit appends the incidents detected up to the height of state and
+// replaces the stored state
+func (s *Store) Commit(state *State, incidents []Incident) error {
+	return s.journal.Commit(state.Height, incidents, state)
+}
+
+// Range returns a copy of the incidents matching query in height order
+func (s *Store) Range(query Query) []Incident {
+	return s.journal.Range(query.matches)
+}
+
+// Close closes the file backing the store
+func (s *Store) Close() error {
+	return s.journal.Close()
+}
@@ -0,0 +1,155 @@
+package journal_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/SimplyVC/oasis_api_server/src/journal"
+)
+
+// record is stored in the tests, Height is the height it was found at
+type record struct {
+	Height int64  `json:"height"`
+	Name   string `json:"name"`
+}
+
+// state is committed along with the records in the tests
+type state struct {
+	Seen int `json:"seen"`
+}
+
+func heightOf(r *record) int64 {
+	return r.Height
+}
+
+func all(*record) bool {
+	return true
+}
+
+// open opens the store kept in path, failing the test on error
+func open(t *testing.T, path string, limit int) *journal.Store[record] {
+	store, err := journal.Open(path, heightOf, limit)
+	if err != nil {
+		t.Fatalf("Failed to open store : %v", err)
+	}
+	return store
+}
+
+func TestStore_CommitAndReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "records.jsonl")
+	store := open(t, path, 0)
+	store.Commit(1, []record{{1, "a"}, {1, "b"}}, &state{Seen: 2})
+	store.Commit(2, nil, &state{Seen: 2})
+	store.Commit(3, []record{{3, "c"}}, &state{Seen: 3})
+
+	// Heights that were already committed are ignored
+	store.Commit(3, []record{{3, "c"}}, &state{Seen: 4})
+	store.Close()
+
+	reopened := open(t, path, 0)
+	defer reopened.Close()
+	var saved state
+	if err := reopened.State(&saved); err != nil || saved.Seen != 3 {
+		t.Errorf("Reopened store got state %v %v want 3", saved, err)
+	}
+	if reopened.Height() != 3 {
+		t.Errorf("Reopened store got height %v want 3", reopened.Height())
+	}
+	if records := reopened.Range(all); len(records) != 3 {
+		t.Errorf("Reopened store got %v records want 3", len(records))
+	}
+}
+
+func TestStore_IncompleteCommit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "records.jsonl")
+	store := open(t, path, 0)
+	store.Commit(1, []record{{1, "a"}}, nil)
+	store.Close()
+
+	// A crash before the state file was replaced leaves the records of
+	// height 2 behind, the last one cut short
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.WriteString("{\"height\":2,\"name\":\"b\"}\n{\"height\":2,")
+	file.Close()
+
+	reopened := open(t, path, 0)
+	if reopened.Height() != 1 {
+		t.Errorf("Reopened store got height %v want 1", reopened.Height())
+	}
+	if err := reopened.Commit(2, []record{{2, "b"}, {2, "c"}},
+		nil); err != nil {
+		t.Fatalf("Failed to commit : %v", err)
+	}
+	reopened.Close()
+
+	// Committing the height again doesn't duplicate its records
+	reopened = open(t, path, 0)
+	defer reopened.Close()
+	records := reopened.Range(all)
+	if len(records) != 3 || records[1].Name != "b" || records[2].Name != "c" {
+		t.Errorf("Reopened store got records %v", records)
+	}
+}
+
+func TestStore_WithoutState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "records.jsonl")
+	err := os.WriteFile(path, []byte("{\"height\":4,\"name\":\"a\"}\n"+
+		"{\"height\":7,\"name\":\"b\"}\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Every record of a file without a state file is committed
+	store := open(t, path, 0)
+	defer store.Close()
+	if store.Height() != 7 || len(store.Range(all)) != 2 {
+		t.Errorf("Store got height %v and records %v", store.Height(),
+			store.Range(all))
+	}
+}
+
+func TestStore_Limit(t *testing.T) {
+	store := open(t, filepath.Join(t.TempDir(), "records.jsonl"), 2)
+	defer store.Close()
+	for height := int64(1); height <= 5; height++ {
+		store.Commit(height, []record{{Height: height}}, nil)
+	}
+
+	records := store.Range(all)
+	if len(records) != 2 || records[0].Height != 4 {
+		t.Errorf("Limited store got records %v", records)
+	}
+	if last, ok := store.Last(); !ok || last.Height != 5 {
+		t.Errorf("Last record got height %v want 5", last.Height)
+	}
+}
+
+func TestHeights(t *testing.T) {
+	tests := []struct {
+		committed, latest, start int64
+		first, last              int64
+	}{
+		// Nothing committed starts at the start height or the latest
+		{0, 50, 0, 50, 50},
+		{0, 50, 10, 10, 50},
+		{0, 500, 10, 10, 109},
+
+		// Otherwise after the committed height
+		{20, 50, 10, 21, 50},
+		{50, 50, 0, 51, 50},
+		{20, 500, 0, 21, 120},
+	}
+	for _, test := range tests {
+		first, last := journal.Heights(test.committed, test.latest,
+			test.start)
+		if first != test.first || last != test.last {
+			t.Errorf("Heights(%v, %v, %v) got %v %v want %v %v",
+				test.committed, test.latest, test.start, first, last,
+				test.first, test.last)
+		}
+	}
+}
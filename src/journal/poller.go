@@ -0,0 +1,93 @@
+// Package journal keeps what the background workers find on the chain, such
+// as samples, ledger entries and incidents, and polls the heights they
+// process
+package journal
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	lgr "github.com/SimplyVC/oasis_api_server/src/logger"
+)
+
+// MaxHeightsPerPoll bounds how many heights are processed in one poll so
+// that catching up after downtime doesn't starve the API of the node
+const MaxHeightsPerPoll = 100
+
+// Heights returns the first and last height to process in a poll once
+// committed was processed and the node reached latest, there is nothing to
+// process if last is lower than first. Nothing being committed yet, the
+// first poll starts at start or else at the latest height.
+func Heights(committed, latest, start int64) (first, last int64) {
+	first = committed + 1
+	if committed == 0 {
+		first = latest
+		if start != 0 {
+			first = start
+		}
+	}
+	last = latest
+	if last >= first+MaxHeightsPerPoll {
+		last = first + MaxHeightsPerPoll - 1
+	}
+	return first, last
+}
+
+// Poller runs polling loops in the background until it is stopped
+type Poller struct {
+	mutex  sync.Mutex
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// Go runs fn in the background with a context that is cancelled by Stop
+func (p *Poller) Go(fn func(ctx context.Context)) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.ctx == nil {
+		p.ctx, p.cancel = context.WithCancel(context.Background())
+	}
+	p.wg.Add(1)
+	go func(ctx context.Context) {
+		defer p.wg.Done()
+		fn(ctx)
+	}(p.ctx)
+}
+
+// Stop cancels the loops started by Go and waits for them to return, after
+// which new ones can be started
+func (p *Poller) Stop() {
+	p.mutex.Lock()
+	if p.cancel != nil {
+		p.cancel()
+	}
+	p.mutex.Unlock()
+
+	p.wg.Wait()
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.ctx, p.cancel = nil, nil
+}
+
+// Every calls poll right away and then every interval until ctx is done,
+// logging its errors after failure unless ctx was cancelled
+func Every(ctx context.Context, interval time.Duration, failure string,
+	poll func(ctx context.Context) error) {
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := poll(ctx); err != nil && ctx.Err() == nil {
+			lgr.Error.Printf("%s : %s", failure, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,259 @@
+package journal
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// progress is what is kept in the state file of a store, the last committed
+// height and the state of whoever commits to the store
+type progress struct {
+	Height int64           `json:"height"`
+	State  json.RawMessage `json:"state,omitempty"`
+}
+
+// Store keeps records appended to a JSON lines file, in the order of the
+// heights they were found at, along with the last height that was committed
+// and an optional state kept in a state file next to it.
+//
+// A commit first appends its records and then replaces the state file, so
+// records above the committed height were written by a commit that didn't
+// complete. They are cut off when the store is opened, and right away when
+// the commit fails, so that committing the height again doesn't duplicate
+// them.
+type Store[T any] struct {
+	mutex     sync.RWMutex
+	file      *os.File
+	size      int64
+	statePath string
+	heightOf  func(*T) int64
+	limit     int
+	records   []T
+	height    int64
+	state     json.RawMessage
+	broken    error
+}
+
+// Open loads the records kept in path and the progress kept next to it,
+// creating both if they don't exist. heightOf returns the height a record
+// was found at. Only the latest limit records are kept in memory when limit
+// is positive, the file keeps all of them.
+func Open[T any](path string, heightOf func(*T) int64,
+	limit int) (*Store[T], error) {
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	store := &Store[T]{
+		file:      file,
+		statePath: path + ".state",
+		heightOf:  heightOf,
+		limit:     limit,
+	}
+	if err := store.load(path); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+// load reads the state file and the records committed up to its height,
+// cutting off the records of a commit that didn't complete. Without a state
+// file every record is committed.
+func (s *Store[T]) load(path string) error {
+	encoded, err := os.ReadFile(s.statePath)
+	committed := err == nil
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		return err
+	default:
+		var saved progress
+		if err := json.Unmarshal(encoded, &saved); err != nil {
+			return fmt.Errorf("%s : %w", s.statePath, err)
+		}
+		s.height, s.state = saved.Height, saved.State
+	}
+
+	reader := bufio.NewReader(s.file)
+	for line := 1; ; line++ {
+		data, err := reader.ReadBytes('\n')
+
+		// A last line without a line break was cut short while written
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		var record T
+		if err := json.Unmarshal(data, &record); err != nil {
+			return fmt.Errorf("%s line %d : %w", path, line, err)
+		}
+		height := s.heightOf(&record)
+		if committed && height > s.height {
+			break
+		}
+		if !committed && height > s.height {
+			s.height = height
+		}
+		s.keep(record)
+		s.size += int64(len(data))
+	}
+
+	info, err := s.file.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() > s.size {
+		return s.file.Truncate(s.size)
+	}
+	return nil
+}
+
+// keep adds a record to those kept in memory, dropping the oldest ones once
+// there are twice as many as the limit so that they aren't copied every time
+func (s *Store[T]) keep(records ...T) {
+	s.records = append(s.records, records...)
+	if s.limit > 0 && len(s.records) >= 2*s.limit {
+		s.records = append([]T(nil), s.records[len(s.records)-s.limit:]...)
+	}
+}
+
+// kept returns the records kept in memory that are within the limit
+func (s *Store[T]) kept() []T {
+	if s.limit > 0 && len(s.records) > s.limit {
+		return s.records[len(s.records)-s.limit:]
+	}
+	return s.records
+}
+
+// Height returns the last committed height
+func (s *Store[T]) Height() int64 {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.height
+}
+
+// State decodes the state saved by the last commit into state, leaving it
+// as it is if no state was saved
+func (s *Store[T]) State(state interface{}) error {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	if len(s.state) == 0 {
+		return nil
+	}
+	return json.Unmarshal(s.state, state)
+}
+
+// Last returns the latest record
+func (s *Store[T]) Last() (T, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var last T
+	records := s.kept()
+	if len(records) == 0 {
+		return last, false
+	}
+	return records[len(records)-1], true
+}
+
+// Commit appends the records found up to height and marks it as committed
+// along with state, which can be nil. Heights that were already committed
+// are ignored.
+func (s *Store[T]) Commit(height int64, records []T,
+	state interface{}) error {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.broken != nil {
+		return s.broken
+	}
+	if height <= s.height {
+		return nil
+	}
+
+	var lines bytes.Buffer
+	for _, record := range records {
+		encoded, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+		lines.Write(append(encoded, '\n'))
+	}
+	saved := progress{Height: height}
+	if state != nil {
+		encoded, err := json.Marshal(state)
+		if err != nil {
+			return err
+		}
+		saved.State = encoded
+	}
+	encoded, err := json.Marshal(saved)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.file.Write(lines.Bytes()); err != nil {
+		return s.rollback(err)
+	}
+
+	// The state file is replaced by renaming a temporary file over it, so
+	// that it is never seen half written
+	tmpPath := s.statePath + ".tmp"
+	if err := os.WriteFile(tmpPath, encoded, 0600); err != nil {
+		return s.rollback(err)
+	}
+	if err := os.Rename(tmpPath, s.statePath); err != nil {
+		return s.rollback(err)
+	}
+
+	s.size += int64(lines.Len())
+	s.keep(records...)
+	s.height, s.state = height, saved.State
+	return nil
+}
+
+// rollback cuts off the records written by a commit that failed. If that
+// fails too the store refuses further commits, as they would follow records
+// that are committed again on retry.
+func (s *Store[T]) rollback(err error) error {
+	if truncateErr := s.file.Truncate(s.size); truncateErr != nil {
+		s.broken = fmt.Errorf("store needs to be opened again after a "+
+			"failed commit : %w", errors.Join(err, truncateErr))
+		return s.broken
+	}
+	return err
+}
+
+// Range returns a copy of the records kept in memory that match, in the
+// order they were committed
+func (s *Store[T]) Range(match func(*T) bool) []T {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	result := []T{}
+	records := s.kept()
+	for i := range records {
+		if match(&records[i]) {
+			result = append(result, records[i])
+		}
+	}
+	return result
+}
+
+// Close closes the file backing the store
+func (s *Store[T]) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.file.Close()
+}
@@ -0,0 +1,139 @@
+package ledger
+
+import (
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+// Kinds of balance changes recorded in the ledger
+const (
+	KindTransfer        = "transfer"
+	KindFee             = "fee"
+	KindReward          = "reward"
+	KindBurn            = "burn"
+	KindEscrowAdd       = "escrow_add"
+	KindDebondingStart  = "debonding_start"
+	KindEscrowReclaim   = "escrow_reclaim"
+	KindSlashing        = "slashing"
+	KindAllowanceChange = "allowance_change"
+)
+
+// Balances of an account that a change can apply to
+const (
+	AccountGeneral   = "general"
+	AccountEscrow    = "escrow"
+	AccountAllowance = "allowance"
+)
+
+// Directions of a change from the point of view of the watched address
+const (
+	DirectionIn   = "in"
+	DirectionOut  = "out"
+	DirectionNone = "none"
+)
+
+// Entry is a single balance change of a watched address
+type Entry struct {
+	Height       int64             `json:"height"`
+	Time         time.Time         `json:"time"`
+	TxHash       hash.Hash         `json:"tx_hash"`
+	Address      staking.Address   `json:"address"`
+	Kind         string            `json:"kind"`
+	Account      string            `json:"account"`
+	Direction    string            `json:"direction"`
+	Counterparty staking.Address   `json:"counterparty"`
+	Amount       quantity.Quantity `json:"amount"`
+}
+
+// isRewardSource checks whether tokens coming from address are rewards
+func isRewardSource(address staking.Address) bool {
+	return address.Equal(staking.CommonPoolAddress) ||
+		address.Equal(staking.FeeAccumulatorAddress)
+}
+
+// EntriesFromEvent derives the ledger entries of the watched addresses from a
+// staking event. An address taking part in an event in two roles, such as
+// delegating to itself, gets an entry for each role.
+func EntriesFromEvent(event *staking.Event, blockTime time.Time,
+	watched map[staking.Address]bool) []Entry {
+
+	entries := []Entry{}
+	add := func(address staking.Address, kind string, account string,
+		direction string, counterparty staking.Address,
+		amount quantity.Quantity) {
+
+		if !watched[address] {
+			return
+		}
+		entries = append(entries, Entry{
+			Height:       event.Height,
+			Time:         blockTime,
+			TxHash:       event.TxHash,
+			Address:      address,
+			Kind:         kind,
+			Account:      account,
+			Direction:    direction,
+			Counterparty: counterparty,
+			Amount:       amount,
+		})
+	}
+
+	switch {
+	case event.Transfer != nil:
+		e := event.Transfer
+		outKind, inKind := KindTransfer, KindTransfer
+		if e.To.Equal(staking.FeeAccumulatorAddress) {
+			outKind = KindFee
+		}
+		if isRewardSource(e.From) {
+			inKind = KindReward
+		}
+		add(e.From, outKind, AccountGeneral, DirectionOut, e.To, e.Amount)
+		add(e.To, inKind, AccountGeneral, DirectionIn, e.From, e.Amount)
+	case event.Burn != nil:
+		e := event.Burn
+		add(e.Owner, KindBurn, AccountGeneral, DirectionOut, e.Owner,
+			e.Amount)
+	case event.Escrow != nil && event.Escrow.Add != nil:
+		e := event.Escrow.Add
+		inKind := KindEscrowAdd
+		if e.Owner.Equal(staking.CommonPoolAddress) {
+			inKind = KindReward
+		}
+		add(e.Owner, KindEscrowAdd, AccountGeneral, DirectionOut, e.Escrow,
+			e.Amount)
+		add(e.Escrow, inKind, AccountEscrow, DirectionIn, e.Owner, e.Amount)
+	case event.Escrow != nil && event.Escrow.Take != nil:
+		e := event.Escrow.Take
+		add(e.Owner, KindSlashing, AccountEscrow, DirectionOut, e.Owner,
+			e.Amount)
+	case event.Escrow != nil && event.Escrow.DebondingStart != nil:
+		// Debonding only moves stake between the active and debonding
+		// pools of the escrow, no balance changes until it is reclaimed
+		e := event.Escrow.DebondingStart
+		add(e.Owner, KindDebondingStart, AccountEscrow, DirectionNone,
+			e.Escrow, e.Amount)
+		add(e.Escrow, KindDebondingStart, AccountEscrow, DirectionNone,
+			e.Owner, e.Amount)
+	case event.Escrow != nil && event.Escrow.Reclaim != nil:
+		e := event.Escrow.Reclaim
+		add(e.Owner, KindEscrowReclaim, AccountGeneral, DirectionIn, e.Escrow,
+			e.Amount)
+		add(e.Escrow, KindEscrowReclaim, AccountEscrow, DirectionOut, e.Owner,
+			e.Amount)
+	case event.AllowanceChange != nil:
+		e := event.AllowanceChange
+		direction := DirectionIn
+		if e.Negative {
+			direction = DirectionOut
+		}
+		add(e.Owner, KindAllowanceChange, AccountAllowance, direction,
+			e.Beneficiary, e.AmountChange)
+		add(e.Beneficiary, KindAllowanceChange, AccountAllowance, direction,
+			e.Owner, e.AmountChange)
+	}
+	return entries
+}
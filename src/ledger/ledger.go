@@ -0,0 +1,257 @@
+package ledger
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SimplyVC/oasis_api_server/src/config"
+	"github.com/SimplyVC/oasis_api_server/src/journal"
+	lgr "github.com/SimplyVC/oasis_api_server/src/logger"
+	"github.com/SimplyVC/oasis_api_server/src/rpc"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+// Default settings used when the ledger section leaves them empty
+const (
+	defaultPollInterval = 10 * time.Second
+	defaultStorageDir   = "../data/ledger"
+)
+
+var (
+	mutex   sync.RWMutex
+	store   *Store
+	watched map[staking.Address]bool
+	poller  journal.Poller
+)
+
+// Settings configure the ledger, they are read from the ledger section of the
+// main configuration
+type Settings struct {
+	Enabled      bool
+	NodeName     string
+	Addresses    []staking.Address
+	StartHeight  int64
+	PollInterval time.Duration
+	StorageDir   string
+}
+
+// LoadSettings parses the ledger section of the main configuration
+func LoadSettings(mainConf map[string]map[string]string) (*Settings, error) {
+	section := mainConf["ledger"]
+	settings := &Settings{
+		NodeName:     section["node_name"],
+		PollInterval: defaultPollInterval,
+		StorageDir:   defaultStorageDir,
+	}
+
+	if enabled := section["enabled"]; enabled != "" {
+		value, err := strconv.ParseBool(enabled)
+		if err != nil {
+			return nil, fmt.Errorf("ledger enabled needs to be true or "+
+				"false, got %s", enabled)
+		}
+		settings.Enabled = value
+	}
+	for _, field := range strings.Split(section["addresses"], ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		var address staking.Address
+		if err := address.UnmarshalText([]byte(field)); err != nil {
+			return nil, fmt.Errorf("ledger address %s is invalid : %w",
+				field, err)
+		}
+		settings.Addresses = append(settings.Addresses, address)
+	}
+	if start := section["start_height"]; start != "" {
+		height, err := strconv.ParseInt(start, 10, 64)
+		if err != nil || height <= 0 {
+			return nil, fmt.Errorf("ledger start_height needs to be a "+
+				"positive height, got %s", start)
+		}
+		settings.StartHeight = height
+	}
+	if interval := section["poll_interval"]; interval != "" {
+		seconds, err := strconv.ParseUint(interval, 10, 32)
+		if err != nil || seconds == 0 {
+			return nil, fmt.Errorf("ledger poll_interval needs to be a "+
+				"positive number of seconds, got %s", interval)
+		}
+		settings.PollInterval = time.Duration(seconds) * time.Second
+	}
+	if dir := section["storage_dir"]; dir != "" {
+		settings.StorageDir = dir
+	}
+
+	if settings.Enabled {
+		if settings.NodeName == "" {
+			return nil, fmt.Errorf("ledger node_name needs to be set")
+		}
+		if len(settings.Addresses) == 0 {
+			return nil, fmt.Errorf("ledger addresses needs at least one " +
+				"address")
+		}
+	}
+	return settings, nil
+}
+
+// GetStore returns the ledger store if the ledger is being kept
+func GetStore() (*Store, bool) {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return store, store != nil
+}
+
+// IsWatched checks whether the ledger is kept for address
+func IsWatched(address staking.Address) bool {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return watched[address]
+}
+
+// Start opens the ledger store and starts scanning the staking events of the
+// configured node in the background until Stop is called
+func Start(settings *Settings) error {
+	var socket string
+	if cfg := config.Get(); cfg != nil {
+		for _, node := range cfg.Nodes {
+			if node.Name == settings.NodeName {
+				socket = node.ISocketPath
+			}
+		}
+	}
+	if socket == "" {
+		return fmt.Errorf("ledger node %s isn't configured",
+			settings.NodeName)
+	}
+
+	if err := os.MkdirAll(settings.StorageDir, 0700); err != nil {
+		return err
+	}
+	path := filepath.Join(settings.StorageDir, "ledger.jsonl")
+	opened, err := OpenStore(path)
+	if err != nil {
+		return err
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	store = opened
+	watched = map[staking.Address]bool{}
+	for _, address := range settings.Addresses {
+		watched[address] = true
+	}
+
+	lgr.Info.Printf("Keeping ledger of %d addresses from node %s in %s",
+		len(watched), settings.NodeName, path)
+	addresses := watched
+	poller.Go(func(ctx context.Context) {
+		run(ctx, settings, socket, opened, addresses)
+	})
+	return nil
+}
+
+// Stop stops scanning, waits for in progress heights to be written and closes
+// the store
+func Stop() {
+	poller.Stop()
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if store != nil {
+		if err := store.Close(); err != nil {
+			lgr.Error.Printf("Failed to close ledger store : %s", err)
+		}
+	}
+	store = nil
+	watched = nil
+}
+
+// run scans the node every poll interval until ctx is cancelled
+func run(ctx context.Context, settings *Settings, socket string, store *Store,
+	watched map[staking.Address]bool) {
+
+	consensusConn, co, err := rpc.ConsensusClient(socket)
+	if err != nil {
+		lgr.Error.Printf("Ledger failed to connect to node %s : %s",
+			settings.NodeName, err)
+		return
+	}
+	defer consensusConn.Close()
+	stakingConn, so, err := rpc.StakingClient(socket)
+	if err != nil {
+		lgr.Error.Printf("Ledger failed to connect to node %s : %s",
+			settings.NodeName, err)
+		return
+	}
+	defer stakingConn.Close()
+
+	journal.Every(ctx, settings.PollInterval,
+		"Ledger failed to scan node "+settings.NodeName,
+		func(ctx context.Context) error {
+			return poll(ctx, co, so, store, watched, settings.StartHeight)
+		})
+}
+
+// poll scans the heights that were reached since the last processed height,
+// a new store starts at the start height or else at the latest height
+func poll(ctx context.Context, co consensus.ClientBackend,
+	so staking.Backend, store *Store, watched map[staking.Address]bool,
+	startHeight int64) error {
+
+	status, err := co.GetStatus(ctx)
+	if err != nil {
+		return err
+	}
+
+	first, last := journal.Heights(store.Height(), status.LatestHeight,
+		startHeight)
+	for height := first; height <= last; height++ {
+		if ctx.Err() != nil {
+			return nil
+		}
+		entries, err := scanHeight(ctx, co, so, height, watched)
+		if err != nil {
+			return fmt.Errorf("height %d : %w", height, err)
+		}
+		if err := store.Commit(height, entries); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scanHeight derives the ledger entries of the watched addresses from the
+// staking events emitted at height
+func scanHeight(ctx context.Context, co consensus.ClientBackend,
+	so staking.Backend, height int64,
+	watched map[staking.Address]bool) ([]Entry, error) {
+
+	events, err := so.GetEvents(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, nil
+	}
+
+	block, err := co.GetBlock(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []Entry{}
+	for _, event := range events {
+		entries = append(entries, EntriesFromEvent(event, block.Time,
+			watched)...)
+	}
+	return entries, nil
+}
@@ -0,0 +1,320 @@
+package ledger_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SimplyVC/oasis_api_server/src/ledger"
+	lgr "github.com/SimplyVC/oasis_api_server/src/logger"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+func TestMain(m *testing.M) {
+	lgr.SetLogger(os.Stdout, os.Stdout, os.Stderr)
+	os.Exit(m.Run())
+}
+
+// newAddress derives a distinct address from a single byte public key
+func newAddress(b byte) staking.Address {
+	var pk signature.PublicKey
+	pk[0] = b
+	return staking.NewAddress(pk)
+}
+
+var (
+	watchedAddress = newAddress(1)
+	otherAddress   = newAddress(2)
+	watched        = map[staking.Address]bool{watchedAddress: true}
+	blockTime      = time.Unix(600, 0).UTC()
+)
+
+func TestEntriesFromEvent(t *testing.T) {
+	amount := *quantity.NewFromUint64(100)
+	tests := []struct {
+		name      string
+		event     staking.Event
+		kinds     []string
+		accounts  []string
+		direction []string
+	}{
+		{
+			name: "transfer out",
+			event: staking.Event{Transfer: &staking.TransferEvent{
+				From: watchedAddress, To: otherAddress, Amount: amount}},
+			kinds:     []string{ledger.KindTransfer},
+			accounts:  []string{ledger.AccountGeneral},
+			direction: []string{ledger.DirectionOut},
+		},
+		{
+			name: "fee",
+			event: staking.Event{Transfer: &staking.TransferEvent{
+				From: watchedAddress, To: staking.FeeAccumulatorAddress,
+				Amount: amount}},
+			kinds:     []string{ledger.KindFee},
+			accounts:  []string{ledger.AccountGeneral},
+			direction: []string{ledger.DirectionOut},
+		},
+		{
+			name: "reward to general",
+			event: staking.Event{Transfer: &staking.TransferEvent{
+				From: staking.CommonPoolAddress, To: watchedAddress,
+				Amount: amount}},
+			kinds:     []string{ledger.KindReward},
+			accounts:  []string{ledger.AccountGeneral},
+			direction: []string{ledger.DirectionIn},
+		},
+		{
+			name: "reward to escrow",
+			event: staking.Event{Escrow: &staking.EscrowEvent{
+				Add: &staking.AddEscrowEvent{
+					Owner: staking.CommonPoolAddress, Escrow: watchedAddress,
+					Amount: amount}}},
+			kinds:     []string{ledger.KindReward},
+			accounts:  []string{ledger.AccountEscrow},
+			direction: []string{ledger.DirectionIn},
+		},
+		{
+			name: "self delegation",
+			event: staking.Event{Escrow: &staking.EscrowEvent{
+				Add: &staking.AddEscrowEvent{
+					Owner: watchedAddress, Escrow: watchedAddress,
+					Amount: amount}}},
+			kinds: []string{ledger.KindEscrowAdd, ledger.KindEscrowAdd},
+			accounts: []string{ledger.AccountGeneral,
+				ledger.AccountEscrow},
+			direction: []string{ledger.DirectionOut, ledger.DirectionIn},
+		},
+		{
+			name: "slashing",
+			event: staking.Event{Escrow: &staking.EscrowEvent{
+				Take: &staking.TakeEscrowEvent{
+					Owner: watchedAddress, Amount: amount}}},
+			kinds:     []string{ledger.KindSlashing},
+			accounts:  []string{ledger.AccountEscrow},
+			direction: []string{ledger.DirectionOut},
+		},
+		{
+			name: "reclaim",
+			event: staking.Event{Escrow: &staking.EscrowEvent{
+				Reclaim: &staking.ReclaimEscrowEvent{
+					Owner: watchedAddress, Escrow: otherAddress,
+					Amount: amount}}},
+			kinds:     []string{ledger.KindEscrowReclaim},
+			accounts:  []string{ledger.AccountGeneral},
+			direction: []string{ledger.DirectionIn},
+		},
+		{
+			name: "burn",
+			event: staking.Event{Burn: &staking.BurnEvent{
+				Owner: watchedAddress, Amount: amount}},
+			kinds:     []string{ledger.KindBurn},
+			accounts:  []string{ledger.AccountGeneral},
+			direction: []string{ledger.DirectionOut},
+		},
+		{
+			name: "allowance decrease",
+			event: staking.Event{AllowanceChange: &staking.AllowanceChangeEvent{
+				Owner: watchedAddress, Beneficiary: otherAddress,
+				Negative: true, AmountChange: amount}},
+			kinds:     []string{ledger.KindAllowanceChange},
+			accounts:  []string{ledger.AccountAllowance},
+			direction: []string{ledger.DirectionOut},
+		},
+		{
+			name: "unwatched",
+			event: staking.Event{Transfer: &staking.TransferEvent{
+				From: otherAddress, To: otherAddress, Amount: amount}},
+		},
+	}
+
+	for _, test := range tests {
+		test.event.Height = 7
+		entries := ledger.EntriesFromEvent(&test.event, blockTime, watched)
+		if len(entries) != len(test.kinds) {
+			t.Errorf("%s got %v entries want %v", test.name, len(entries),
+				len(test.kinds))
+			continue
+		}
+		for i, entry := range entries {
+			if entry.Kind != test.kinds[i] ||
+				entry.Account != test.accounts[i] ||
+				entry.Direction != test.direction[i] {
+				t.Errorf("%s entry %d got %s %s %s want %s %s %s",
+					test.name, i, entry.Kind, entry.Account,
+					entry.Direction, test.kinds[i], test.accounts[i],
+					test.direction[i])
+			}
+			if entry.Height != 7 || !entry.Time.Equal(blockTime) ||
+				entry.Amount.Cmp(&amount) != 0 {
+				t.Errorf("%s entry %d got height %v time %v amount %v",
+					test.name, i, entry.Height, entry.Time, entry.Amount)
+			}
+		}
+	}
+}
+
+// newEntry creates an entry of the watched address at height that wasn't
+// caused by a transaction
+func newEntry(height int64, kind string) ledger.Entry {
+	entry := ledger.Entry{
+		Height:       height,
+		Time:         blockTime,
+		Address:      watchedAddress,
+		Kind:         kind,
+		Account:      ledger.AccountGeneral,
+		Direction:    ledger.DirectionIn,
+		Counterparty: otherAddress,
+		Amount:       *quantity.NewFromUint64(uint64(height)),
+	}
+	entry.TxHash.Empty()
+	return entry
+}
+
+func TestStore_CommitAndReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ledger.jsonl")
+	store, err := ledger.OpenStore(path)
+	if err != nil {
+		t.Fatalf("Failed to open store : %v", err)
+	}
+	if err := store.Commit(1, []ledger.Entry{
+		newEntry(1, ledger.KindTransfer)}); err != nil {
+		t.Fatalf("Failed to commit height : %v", err)
+	}
+	if err := store.Commit(2, nil); err != nil {
+		t.Fatalf("Failed to commit height : %v", err)
+	}
+	if err := store.Commit(3, []ledger.Entry{
+		newEntry(3, ledger.KindReward)}); err != nil {
+		t.Fatalf("Failed to commit height : %v", err)
+	}
+
+	// Heights already processed are ignored
+	if err := store.Commit(3, []ledger.Entry{
+		newEntry(3, ledger.KindReward)}); err != nil {
+		t.Fatalf("Failed to commit height : %v", err)
+	}
+	store.Close()
+
+	reopened, err := ledger.OpenStore(path)
+	if err != nil {
+		t.Fatalf("Failed to reopen store : %v", err)
+	}
+	defer reopened.Close()
+
+	if height := reopened.Height(); height != 3 {
+		t.Errorf("Reopened store got height %v want 3", height)
+	}
+	if entries := reopened.Range(ledger.Query{}); len(entries) != 2 {
+		t.Errorf("Reopened store got %v entries want 2", len(entries))
+	}
+	rewards := reopened.Range(ledger.Query{Kind: ledger.KindReward})
+	if len(rewards) != 1 || rewards[0].Height != 3 {
+		t.Errorf("Kind query got %v want the entry at height 3", rewards)
+	}
+	if entries := reopened.Range(ledger.Query{Address: &otherAddress}); len(entries) != 0 {
+		t.Errorf("Address query got %v entries want 0", len(entries))
+	}
+	if entries := reopened.Range(ledger.Query{ToHeight: 2}); len(entries) != 1 {
+		t.Errorf("Height query got %v entries want 1", len(entries))
+	}
+}
+
+func TestStore_FailedCommit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ledger.jsonl")
+	store, err := ledger.OpenStore(path)
+	if err != nil {
+		t.Fatalf("Failed to open store : %v", err)
+	}
+	defer store.Close()
+	entries := []ledger.Entry{newEntry(1, ledger.KindTransfer),
+		newEntry(1, ledger.KindReward)}
+
+	// The processed height can't be written while a directory is in the
+	// way of its temporary file
+	if err := os.Mkdir(path+".state.tmp", 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Commit(1, entries); err == nil {
+		t.Fatalf("Expected commit to fail")
+	}
+	os.Remove(path + ".state.tmp")
+
+	// Scanning the height again doesn't duplicate its entries
+	if err := store.Commit(1, entries); err != nil {
+		t.Fatalf("Failed to commit height : %v", err)
+	}
+	reopened, err := ledger.OpenStore(path)
+	if err != nil {
+		t.Fatalf("Failed to reopen store : %v", err)
+	}
+	defer reopened.Close()
+	if entries := reopened.Range(ledger.Query{}); len(entries) != 2 {
+		t.Errorf("Reopened store got %v entries want 2", len(entries))
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	err := ledger.WriteCSV(&buf, []ledger.Entry{
+		newEntry(5, ledger.KindTransfer)})
+	if err != nil {
+		t.Fatalf("Failed to write CSV : %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("CSV got %v lines want 2", len(lines))
+	}
+	if lines[0] != "height,time,tx_hash,address,kind,account,direction,"+
+		"counterparty,amount" {
+		t.Errorf("CSV got header %s", lines[0])
+	}
+	expected := "5,1970-01-01T00:10:00Z,," + watchedAddress.String() +
+		",transfer,general,in," + otherAddress.String() + ",5"
+	if lines[1] != expected {
+		t.Errorf("CSV got row %s want %s", lines[1], expected)
+	}
+}
+
+func TestLoadSettings(t *testing.T) {
+	settings, err := ledger.LoadSettings(map[string]map[string]string{
+		"ledger": {
+			"enabled":   "true",
+			"node_name": "Oasis_Local",
+			"addresses": watchedAddress.String() + ", " +
+				otherAddress.String(),
+			"start_height":  "42",
+			"poll_interval": "30",
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to load settings : %v", err)
+	}
+	if !settings.Enabled || len(settings.Addresses) != 2 ||
+		settings.StartHeight != 42 ||
+		settings.PollInterval != 30*time.Second {
+		t.Errorf("Loaded unexpected settings %+v", settings)
+	}
+
+	invalid := []map[string]string{
+		{"enabled": "maybe"},
+		{"addresses": "oasis1unicorn"},
+		{"start_height": "-1"},
+		{"poll_interval": "0"},
+		{"enabled": "true", "node_name": "Oasis_Local"},
+		{"enabled": "true", "addresses": watchedAddress.String()},
+	}
+	for _, section := range invalid {
+		_, err := ledger.LoadSettings(map[string]map[string]string{
+			"ledger": section})
+		if err == nil {
+			t.Errorf("Settings %v were accepted", section)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package ledger
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/SimplyVC/oasis_api_server/src/journal"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+// Query selects ledger entries, zero values match everything
+type Query struct {
+	Address    *staking.Address
+	Kind       string
+	FromHeight int64
+	ToHeight   int64
+}
+
+// matches checks whether entry is selected by the query
+func (q *Query) matches(entry *Entry) bool {
+	if q.Address != nil && !entry.Address.Equal(*q.Address) {
+		return false
+	}
+	if q.Kind != "" && entry.Kind != q.Kind {
+		return false
+	}
+	if q.FromHeight != 0 && entry.Height < q.FromHeight {
+		return false
+	}
+	if q.ToHeight != 0 && entry.Height > q.ToHeight {
+		return false
+	}
+	return true
+}
+
+// Store keeps the ledger in memory and in a JSON lines file. The last height
+// whose events were processed is committed along with its entries so that
+// heights without entries aren't scanned again after a restart, and entries
+// of a height that failed to commit aren't kept twice once it is scanned
+// again.
+type Store struct {
+	journal *journal.Store[Entry]
+}
+
+// OpenStore loads the ledger kept in path and the processed height kept next
+// to it, creating both if they don't exist
+func OpenStore(path string) (*Store, error) {
+	opened, err := journal.Open(path, func(entry *Entry) int64 {
+		return entry.Height
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &Store{journal: opened}, nil
+}
+
+// Height returns the last height whose events were processed
+func (s *Store) Height() int64 {
+	return s.journal.Height()
+}
+
+// Commit appends the entries found at height and marks it as processed
+func (s *Store) Commit(height int64, entries []Entry) error {
+	return s.journal.Commit(height, entries, nil)
+}
+
+// Range returns a copy of the entries matching query in height order
+func (s *Store) Range(query Query) []Entry {
+	return s.journal.Range(query.matches)
+}
+
+// Close closes the file backing the store
+func (s *Store) Close() error {
+	return s.journal.Close()
+}
+
+// WriteCSV writes entries as CSV with a header row
+func WriteCSV(w io.Writer, entries []Entry) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"height", "time", "tx_hash", "address", "kind",
+		"account", "direction", "counterparty", "amount"})
+
+	for _, entry := range entries {
+		txHash := ""
+		if !entry.TxHash.IsEmpty() {
+			txHash = entry.TxHash.String()
+		}
+		writer.Write([]string{
+			strconv.FormatInt(entry.Height, 10),
+			entry.Time.UTC().Format(time.RFC3339),
+			txHash,
+			entry.Address.String(),
+			entry.Kind,
+			entry.Account,
+			entry.Direction,
+			entry.Counterparty.String(),
+			entry.Amount.String(),
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
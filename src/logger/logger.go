@@ -1,32 +1,171 @@
 package logger
 
 import (
+	"context"
 	"io"
 	"log"
+	"log/slog"
+	"os"
+	"sync"
+)
+
+// Indexes of the handler of each level
+const (
+	debugIndex = iota
+	infoIndex
+	warningIndex
+	errorIndex
+)
+
+// 4 Types of loggers to be used, they stay the same when the loggers are
+// configured again so they can be used from any goroutine
+var (
+	Debug   = newLogger(debugIndex, slog.LevelDebug, nil)
+	Info    = newLogger(infoIndex, slog.LevelInfo, nil)
+	Warning = newLogger(warningIndex, slog.LevelWarn, nil)
+	Error   = newLogger(errorIndex, slog.LevelError, nil)
 )
 
-// 3 Types of loggers to be used
 var (
+	mutex    sync.RWMutex
+	level    = new(slog.LevelVar)
+	handlers [4]slog.Handler
+	output   io.Closer
+)
+
+// Loggers holds a logger of every level, FromContext returns them with the
+// request ID of a request attached to every line
+type Loggers struct {
+	Debug   *log.Logger
 	Info    *log.Logger
 	Warning *log.Logger
 	Error   *log.Logger
-)
+}
 
-// SetLogger creates loggers that will be used through out API
+func init() {
+	SetLogger(os.Stdout, os.Stdout, os.Stderr)
+}
+
+// SetLogger creates loggers that will be used through out API, writing text
+// lines at INFO level and above
 func SetLogger(
 	infoHandle io.Writer,
 	warningHandle io.Writer,
 	errorHandle io.Writer) {
 
-	Info = log.New(infoHandle,
-		"INFO: ",
-		log.Ldate|log.Ltime|log.Lshortfile)
+	level.Set(slog.LevelInfo)
+	setHandlers([4]slog.Handler{
+		newHandler(infoHandle, FormatText),
+		newHandler(infoHandle, FormatText),
+		newHandler(warningHandle, FormatText),
+		newHandler(errorHandle, FormatText),
+	}, nil)
+}
+
+// Configure sets the level, format and output of the loggers. Errors are
+// written to standard error and other levels to standard output unless a
+// file is set, which then receives every level and is rotated by size.
+func Configure(settings *Settings) error {
+	level.Set(settings.Level)
+
+	if settings.File == "" {
+		out := newHandler(os.Stdout, settings.Format)
+		setHandlers([4]slog.Handler{out, out, out,
+			newHandler(os.Stderr, settings.Format)}, nil)
+		return nil
+	}
 
-	Warning = log.New(warningHandle,
-		"WARNING: ",
-		log.Ldate|log.Ltime|log.Lshortfile)
+	file, err := OpenRotatingFile(settings.File, settings.MaxSize,
+		settings.MaxBackups)
+	if err != nil {
+		return err
+	}
+	out := newHandler(file, settings.Format)
+	setHandlers([4]slog.Handler{out, out, out, out}, file)
+	return nil
+}
+
+// FromContext returns loggers attaching the request ID of a context to every
+// line, or the package loggers if it has none
+func FromContext(ctx context.Context) *Loggers {
+	id := RequestID(ctx)
+	if id == "" {
+		return &Loggers{Debug: Debug, Info: Info, Warning: Warning,
+			Error: Error}
+	}
+
+	attrs := []slog.Attr{slog.String("request_id", id)}
+	return &Loggers{
+		Debug:   newLogger(debugIndex, slog.LevelDebug, attrs),
+		Info:    newLogger(infoIndex, slog.LevelInfo, attrs),
+		Warning: newLogger(warningIndex, slog.LevelWarn, attrs),
+		Error:   newLogger(errorIndex, slog.LevelError, attrs),
+	}
+}
+
+// newLogger returns a logger writing lines of a level through the current
+// handler of that level
+func newLogger(index int, lineLevel slog.Level,
+	attrs []slog.Attr) *log.Logger {
+
+	return slog.NewLogLogger(&levelHandler{index: index, attrs: attrs},
+		lineLevel)
+}
+
+// newHandler returns a handler writing lines in the given format, with the
+// file and line they were logged from
+func newHandler(w io.Writer, format string) slog.Handler {
+	options := &slog.HandlerOptions{AddSource: true, Level: level}
+	if format == FormatJSON {
+		return slog.NewJSONHandler(w, options)
+	}
+	return slog.NewTextHandler(w, options)
+}
+
+// setHandlers swaps the handlers of every level and closes the previous
+// output file, if any
+func setHandlers(newHandlers [4]slog.Handler, file io.Closer) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	handlers = newHandlers
+	if output != nil {
+		output.Close()
+	}
+	output = file
+}
+
+// levelHandler writes records through the current handler of a level, so
+// that loggers keep working when the handlers are swapped
+type levelHandler struct {
+	index int
+	attrs []slog.Attr
+}
+
+// Enabled reports whether records of a level are logged
+func (h *levelHandler) Enabled(_ context.Context, l slog.Level) bool {
+	return l >= level.Level()
+}
+
+// Handle writes a record through the current handler
+func (h *levelHandler) Handle(ctx context.Context, record slog.Record) error {
+	mutex.RLock()
+	defer mutex.RUnlock()
+
+	handler := handlers[h.index]
+	if len(h.attrs) != 0 {
+		handler = handler.WithAttrs(h.attrs)
+	}
+	return handler.Handle(ctx, record)
+}
+
+// WithAttrs returns a handler adding attributes to every record
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &levelHandler{index: h.index,
+		attrs: append(append([]slog.Attr{}, h.attrs...), attrs...)}
+}
 
-	Error = log.New(errorHandle,
-		"ERROR: ",
-		log.Ldate|log.Ltime|log.Lshortfile)
+// WithGroup isn't used by the loggers, groups are left out
+func (h *levelHandler) WithGroup(string) slog.Handler {
+	return h
 }
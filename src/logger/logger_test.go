@@ -0,0 +1,146 @@
+package logger_test
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	lgr "github.com/SimplyVC/oasis_api_server/src/logger"
+)
+
+// readLines returns the JSON lines of a log file
+func readLines(t *testing.T, path string) []map[string]interface{} {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var lines []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(string(data)),
+		"\n") {
+		if line == "" {
+			continue
+		}
+		entry := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Failed to parse log line %q : %v", line, err)
+		}
+		lines = append(lines, entry)
+	}
+	return lines
+}
+
+func TestLoadSettings_Success(t *testing.T) {
+	settings, err := lgr.LoadSettings(map[string]map[string]string{
+		"logging": {"level": "DEBUG", "format": "json",
+			"file": "/var/log/oasis_api.log", "max_size_mb": "10",
+			"max_backups": "0"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if settings.Level != slog.LevelDebug ||
+		settings.Format != lgr.FormatJSON || settings.MaxSize != 10<<20 ||
+		settings.MaxBackups != 0 {
+		t.Errorf("Unexpected settings %+v", settings)
+	}
+
+	settings, err = lgr.LoadSettings(map[string]map[string]string{})
+	if err != nil || settings.Level != slog.LevelInfo ||
+		settings.Format != lgr.FormatText || settings.File != "" {
+		t.Errorf("Unexpected default settings %+v %v", settings, err)
+	}
+}
+
+func TestLoadSettings_Failure(t *testing.T) {
+	for _, section := range []map[string]string{
+		{"level": "verbose"},
+		{"format": "xml"},
+		{"max_size_mb": "0"},
+		{"max_backups": "-1"},
+	} {
+		if _, err := lgr.LoadSettings(map[string]map[string]string{
+			"logging": section}); err == nil {
+			t.Errorf("Failed to reject logging section %v", section)
+		}
+	}
+}
+
+func TestConfigure_JSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api.log")
+	if err := lgr.Configure(&lgr.Settings{Level: slog.LevelInfo,
+		Format: lgr.FormatJSON, File: path, MaxSize: 1 << 20}); err != nil {
+		t.Fatal(err)
+	}
+	defer lgr.SetLogger(os.Stdout, os.Stdout, os.Stderr)
+
+	log := lgr.FromContext(lgr.WithRequestID(context.Background(),
+		"4f1c2a9e"))
+	log.Debug.Println("Hidden at INFO level")
+	log.Error.Println("Request failed")
+
+	// Configuring the loggers again changes the level of every logger
+	if err := lgr.Configure(&lgr.Settings{Level: slog.LevelDebug,
+		Format: lgr.FormatJSON, File: path, MaxSize: 1 << 20}); err != nil {
+		t.Fatal(err)
+	}
+	lgr.Debug.Println("Shown at DEBUG level")
+
+	lines := readLines(t, path)
+	if len(lines) != 2 {
+		t.Fatalf("Unexpected log lines %v", lines)
+	}
+	if lines[0]["level"] != "ERROR" || lines[0]["msg"] != "Request failed" ||
+		lines[0]["request_id"] != "4f1c2a9e" || lines[0]["source"] == nil {
+		t.Errorf("Unexpected log line %v", lines[0])
+	}
+	if lines[1]["level"] != "DEBUG" || lines[1]["request_id"] != nil {
+		t.Errorf("Unexpected log line %v", lines[1])
+	}
+}
+
+func TestConfigure_Rotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api.log")
+	if err := lgr.Configure(&lgr.Settings{Level: slog.LevelInfo,
+		Format: lgr.FormatText, File: path, MaxSize: 300,
+		MaxBackups: 2}); err != nil {
+		t.Fatal(err)
+	}
+	defer lgr.SetLogger(os.Stdout, os.Stdout, os.Stderr)
+
+	for i := 0; i < 20; i++ {
+		lgr.Info.Println("Line that fills up the log file")
+	}
+
+	for _, name := range []string{path, path + ".1", path + ".2"} {
+		info, err := os.Stat(name)
+		if err != nil {
+			t.Fatalf("Failed to rotate log file : %v", err)
+		}
+		if info.Size() > 300 {
+			t.Errorf("Log file %s has %d bytes", name, info.Size())
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("Kept more backups than configured")
+	}
+}
+
+func TestNewRequestID(t *testing.T) {
+	if id := lgr.NewRequestID("trace-42.a_b"); id != "trace-42.a_b" {
+		t.Errorf("Failed to keep valid request ID, got %s", id)
+	}
+	for _, received := range []string{"", "line\nbreak",
+		strings.Repeat("a", 65)} {
+		id := lgr.NewRequestID(received)
+		if id == received || len(id) != 16 {
+			t.Errorf("Failed to replace request ID %q, got %q", received,
+				id)
+		}
+	}
+	if lgr.NewRequestID("") == lgr.NewRequestID("") {
+		t.Errorf("Generated the same request ID twice")
+	}
+}
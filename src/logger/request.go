@@ -0,0 +1,41 @@
+package logger
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+)
+
+// RequestIDHeader is the HTTP header and, lowercased, the gRPC metadata key
+// carrying the request ID
+const RequestIDHeader = "X-Request-ID"
+
+// validRequestID matches request IDs that are accepted from clients, others
+// are replaced so that they can't inject anything into the logs
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// requestIDKey is the context key of the request ID
+type requestIDKey struct{}
+
+// NewRequestID returns the request ID sent by a client if it is valid, or
+// a new random one
+func NewRequestID(received string) string {
+	if validRequestID.MatchString(received) {
+		return received
+	}
+	id := make([]byte, 8)
+	rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+// WithRequestID returns a context carrying a request ID
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID carried by a context, if any
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
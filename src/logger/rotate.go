@@ -0,0 +1,109 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// rotatingFile is a log file that is renamed to <file>.1 once it would grow
+// beyond its maximum size, older files being shifted up to <file>.<backups>
+type rotatingFile struct {
+	mutex   sync.Mutex
+	path    string
+	maxSize int64
+	backups int
+	file    *os.File
+	size    int64
+}
+
+// OpenRotatingFile opens a log file for appending that is rotated once it
+// would grow beyond maxSize bytes, keeping the given number of backups
+func OpenRotatingFile(path string, maxSize int64,
+	backups int) (io.WriteCloser, error) {
+
+	r := &rotatingFile{path: path, maxSize: maxSize, backups: backups}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Write writes a line, rotating the file first if it would become too big
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.file == nil {
+		return 0, os.ErrClosed
+	}
+	if r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if err := r.rotate(); err != nil && r.file == nil {
+			return 0, err
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// Close closes the file
+func (r *rotatingFile) Close() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
+
+// open opens the file, keeping track of the size it already has
+func (r *rotatingFile) open() error {
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND,
+		0640)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	r.file, r.size = file, info.Size()
+	return nil
+}
+
+// rotate shifts the backups, renames the file to the first backup and opens
+// a new one, the file is truncated instead when no backups are kept. The file
+// is opened again even if rotating it failed, so that logging goes on.
+func (r *rotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+	r.file = nil
+
+	var err error
+	if r.backups == 0 {
+		err = os.Truncate(r.path, 0)
+	} else {
+		for i := r.backups - 1; i > 0 && err == nil; i-- {
+			err = os.Rename(fmt.Sprintf("%s.%d", r.path, i),
+				fmt.Sprintf("%s.%d", r.path, i+1))
+			if os.IsNotExist(err) {
+				err = nil
+			}
+		}
+		if err == nil {
+			err = os.Rename(r.path, r.path+".1")
+		}
+	}
+
+	if openErr := r.open(); openErr != nil {
+		return openErr
+	}
+	return err
+}
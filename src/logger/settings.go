@@ -0,0 +1,83 @@
+package logger
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// Formats of the log lines
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Defaults of the log file rotation
+const (
+	defaultMaxSize    = 100
+	defaultMaxBackups = 5
+)
+
+// levels maps the names of levels accepted in the configuration
+var levels = map[string]slog.Level{
+	"debug":   slog.LevelDebug,
+	"info":    slog.LevelInfo,
+	"warning": slog.LevelWarn,
+	"error":   slog.LevelError,
+}
+
+// Settings configure the loggers, they are read from the logging section of
+// the main configuration
+type Settings struct {
+	Level      slog.Level
+	Format     string
+	File       string
+	MaxSize    int64
+	MaxBackups int
+}
+
+// LoadSettings parses the logging section of the main configuration
+func LoadSettings(mainConf map[string]map[string]string) (*Settings, error) {
+	section := mainConf["logging"]
+	settings := &Settings{
+		Level:      slog.LevelInfo,
+		Format:     FormatText,
+		File:       section["file"],
+		MaxSize:    defaultMaxSize << 20,
+		MaxBackups: defaultMaxBackups,
+	}
+
+	if name := strings.ToLower(section["level"]); name != "" {
+		value, ok := levels[name]
+		if !ok {
+			return nil, fmt.Errorf("logging level needs to be debug, info, "+
+				"warning or error, got %s", name)
+		}
+		settings.Level = value
+	}
+	if format := strings.ToLower(section["format"]); format != "" {
+		if format != FormatText && format != FormatJSON {
+			return nil, fmt.Errorf("logging format needs to be %s or %s, "+
+				"got %s", FormatText, FormatJSON, format)
+		}
+		settings.Format = format
+	}
+	if size := section["max_size_mb"]; size != "" {
+		megabytes, err := strconv.ParseUint(size, 10, 32)
+		if err != nil || megabytes == 0 {
+			return nil, fmt.Errorf("logging max_size_mb needs to be a "+
+				"positive number of megabytes, got %s", size)
+		}
+		settings.MaxSize = int64(megabytes) << 20
+	}
+	if backups := section["max_backups"]; backups != "" {
+		value, err := strconv.ParseUint(backups, 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("logging max_backups needs to be a "+
+				"number, got %s", backups)
+		}
+		settings.MaxBackups = int(value)
+	}
+	return settings, nil
+}
@@ -1,8 +1,10 @@
 package main
 
 import (
+	"flag"
 	"os"
 
+	conf "github.com/SimplyVC/oasis_api_server/src/config"
 	lgr "github.com/SimplyVC/oasis_api_server/src/logger"
 	"github.com/SimplyVC/oasis_api_server/src/router"
 )
@@ -13,9 +15,40 @@ func main() {
 	// Set Logger that will be used by API through all packages
 	lgr.SetLogger(os.Stdout, os.Stdout, os.Stderr)
 
-	// Start server
+	// Command line flags take precedence over the environment and files
+	flag.Func("config", "YAML or TOML file holding the whole "+
+		"configuration, replacing the INI files", func(value string) error {
+		conf.SetFile(value)
+		return nil
+	})
+	flag.Func("main-config", "INI file holding the main configuration",
+		func(value string) error {
+			conf.SetMainFile(value)
+			return nil
+		})
+	flag.Func("nodes-config", "INI file holding the nodes configuration",
+		func(value string) error {
+			conf.SetNodesFile(value)
+			return nil
+		})
+	flag.Func("sentry-config", "INI file holding the sentry configuration",
+		func(value string) error {
+			conf.SetSentryFile(value)
+			return nil
+		})
+	flag.Func("port", "Port to serve the API on", func(value string) error {
+		conf.SetOverride("api_server", "port", value)
+		return nil
+	})
+	flag.Func("set", "Override of the main configuration as "+
+		"section.key=value, can be repeated", conf.ParseOverride)
+	flag.Parse()
+
+	// Start server, exiting with a failure status if it didn't stop cleanly
 	err := router.StartServer()
 	if err != nil {
-		lgr.Info.Println("Server Stopped")
+		lgr.Error.Println("Server Stopped : ", err)
+		os.Exit(1)
 	}
+	lgr.Info.Println("Server Stopped")
 }
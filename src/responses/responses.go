@@ -1,6 +1,14 @@
 package responses
 
 import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"github.com/SimplyVC/oasis_api_server/src/config"
+	"github.com/SimplyVC/oasis_api_server/src/incidents"
+	"github.com/SimplyVC/oasis_api_server/src/ledger"
+	"github.com/SimplyVC/oasis_api_server/src/sampler"
 	tmed "github.com/cometbft/cometbft/crypto"
 	mint_types "github.com/cometbft/cometbft/types"
 	"github.com/mackerelio/go-osstat/cpu"
@@ -8,16 +16,25 @@ import (
 	"github.com/mackerelio/go-osstat/memory"
 	"github.com/mackerelio/go-osstat/network"
 	beacon_api "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	common_namespace "github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	common_signature "github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	common_entity "github.com/oasisprotocol/oasis-core/go/common/entity"
 	common_node "github.com/oasisprotocol/oasis-core/go/common/node"
 	common_quantity "github.com/oasisprotocol/oasis-core/go/common/quantity"
 	consensus_api "github.com/oasisprotocol/oasis-core/go/consensus/api"
 	document_api "github.com/oasisprotocol/oasis-core/go/genesis/api"
 	gen_api "github.com/oasisprotocol/oasis-core/go/genesis/api"
+	governance_api "github.com/oasisprotocol/oasis-core/go/governance/api"
+	keymanager_api "github.com/oasisprotocol/oasis-core/go/keymanager/api"
 	registry_api "github.com/oasisprotocol/oasis-core/go/registry/api"
+	roothash_api "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	runtime_block "github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	runtime_message "github.com/oasisprotocol/oasis-core/go/roothash/api/message"
 	scheduler_api "github.com/oasisprotocol/oasis-core/go/scheduler/api"
 	sentry_api "github.com/oasisprotocol/oasis-core/go/sentry/api"
 	staking_api "github.com/oasisprotocol/oasis-core/go/staking/api"
+	upgrade_api "github.com/oasisprotocol/oasis-core/go/upgrade/api"
 )
 
 // StakingEvents responds with a list of events
@@ -223,5 +240,432 @@ type GenesisDocumentResponse struct {
 	GenesisDocument *document_api.Document `json:"result"`
 }
 
+// CommissionResponse responds with the commission schedule overview of an
+// account
+type CommissionResponse struct {
+	Commission *CommissionOverview `json:"result"`
+}
+
+// CommissionOverview describes the commission rate and bounds in effect at an
+// epoch, the steps that are still to come and the outcome of validating a
+// proposed amendment against the staking consensus parameters.
+type CommissionOverview struct {
+	Epoch                 beacon_api.EpochTime                  `json:"epoch"`
+	RateDenominator       *common_quantity.Quantity             `json:"rate_denominator"`
+	CurrentRate           *common_quantity.Quantity             `json:"current_rate"`
+	CurrentRatePercentage string                                `json:"current_rate_percentage"`
+	CurrentBound          *staking_api.CommissionRateBoundStep  `json:"current_bound"`
+	UpcomingRates         []staking_api.CommissionRateStep      `json:"upcoming_rates"`
+	UpcomingBounds        []staking_api.CommissionRateBoundStep `json:"upcoming_bounds"`
+	Rules                 *staking_api.CommissionScheduleRules  `json:"rules"`
+	Amendment             *CommissionAmendmentValidation        `json:"amendment,omitempty"`
+}
+
+// CommissionAmendmentValidation responds with whether a proposed commission
+// schedule amendment would be accepted and, if not, why
+type CommissionAmendmentValidation struct {
+	Valid             bool                            `json:"valid"`
+	Error             string                          `json:"error,omitempty"`
+	ResultingSchedule *staking_api.CommissionSchedule `json:"resulting_schedule,omitempty"`
+}
+
+// DenominatedResponse responds with a result whose token amounts are
+// rendered as DenominatedQuantity
+type DenominatedResponse struct {
+	Result interface{} `json:"result"`
+}
+
+// DenominatedQuantity holds a token amount in base units together with its
+// decimal value in the token denomination reported by the node
+type DenominatedQuantity struct {
+	Base   common_quantity.Quantity `json:"base"`
+	Amount string                   `json:"amount"`
+	Symbol string                   `json:"symbol"`
+}
+
+// OrderedField is a single named JSON object member
+type OrderedField struct {
+	Name  string
+	Value interface{}
+}
+
+// OrderedFields is a JSON object which keeps its members in the order they
+// were added, unlike a map which is encoded with sorted keys
+type OrderedFields []OrderedField
+
+// MarshalJSON encodes the fields as a JSON object
+func (fields OrderedFields) MarshalJSON() ([]byte, error) {
+	buf := bytes.NewBufferString("{")
+	for i, field := range fields {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(field.Name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(field.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// SeriesResponse responds with staking supply samples
+type SeriesResponse struct {
+	Series []sampler.Sample `json:"result"`
+}
+
+// LedgerResponse responds with balance changes of watched addresses
+type LedgerResponse struct {
+	Ledger []ledger.Entry `json:"result"`
+}
+
+// IncidentsResponse responds with incidents of monitored entities
+type IncidentsResponse struct {
+	Incidents []incidents.Incident `json:"result"`
+}
+
+// ReloadStatusResponse responds with the configuration reload status
+type ReloadStatusResponse struct {
+	Status config.ReloadStatus `json:"result"`
+}
+
+// AdminNodesResponse responds with the configured nodes
+type AdminNodesResponse struct {
+	Nodes []config.Node `json:"result"`
+}
+
+// AdminSentriesResponse responds with the configured sentries
+type AdminSentriesResponse struct {
+	Sentries []config.Sentry `json:"result"`
+}
+
+// ProposalsResponse responds with a list of governance proposals
+type ProposalsResponse struct {
+	Proposals []*governance_api.Proposal `json:"result"`
+}
+
+// ProposalResponse responds with a single governance proposal
+type ProposalResponse struct {
+	Proposal *governance_api.Proposal `json:"result"`
+}
+
+// VotesResponse responds with the votes cast for a governance proposal
+type VotesResponse struct {
+	Votes []*governance_api.VoteEntry `json:"result"`
+}
+
+// PendingUpgradesResponse responds with the upgrades pending execution
+type PendingUpgradesResponse struct {
+	PendingUpgrades []*upgrade_api.Descriptor `json:"result"`
+}
+
+// GovernanceEventsResponse responds with governance events at a height
+type GovernanceEventsResponse struct {
+	Events []*governance_api.Event `json:"result"`
+}
+
+// GovernanceConsensusParametersResponse responds with the governance
+// consensus parameters
+type GovernanceConsensusParametersResponse struct {
+	ConsensusParameters *governance_api.ConsensusParameters `json:"result"`
+}
+
+// HeightResponse responds with a block height
+type HeightResponse struct {
+	Height int64 `json:"result"`
+}
+
+// FutureEpochResponse responds with the future epoch scheduled at a height,
+// which is null when none is scheduled
+type FutureEpochResponse struct {
+	FutureEpoch *beacon_api.EpochTimeState `json:"result"`
+}
+
+// BeaconParametersResponse responds with the beacon consensus parameters
+type BeaconParametersResponse struct {
+	BeaconParameters *beacon_api.ConsensusParameters `json:"result"`
+}
+
+// BeaconStateResponse responds with the random beacon at a height
+type BeaconStateResponse struct {
+	BeaconState *BeaconState `json:"result"`
+}
+
+// BeaconState holds the random beacon of a height together with the epoch
+// and beacon backend it was produced in
+type BeaconState struct {
+	Height  int64                `json:"height"`
+	Epoch   beacon_api.EpochTime `json:"epoch"`
+	Backend string               `json:"backend"`
+	Beacon  []byte               `json:"beacon"`
+}
+
+// EpochTimeResponse responds with the start time of an epoch
+type EpochTimeResponse struct {
+	EpochTime *EpochTimeEstimate `json:"result"`
+}
+
+// EpochTimeEstimate holds the start height and time of an epoch, which are
+// extrapolated from recent block times when the epoch is in the future
+type EpochTimeEstimate struct {
+	Epoch            beacon_api.EpochTime `json:"epoch"`
+	CurrentEpoch     beacon_api.EpochTime `json:"current_epoch"`
+	StartHeight      int64                `json:"start_height"`
+	StartTime        time.Time            `json:"start_time"`
+	Estimated        bool                 `json:"estimated"`
+	BlocksPerEpoch   int64                `json:"blocks_per_epoch"`
+	AverageBlockTime float64              `json:"average_block_time,omitempty"`
+	SampledBlocks    int64                `json:"sampled_blocks,omitempty"`
+}
+
+// RuntimeBlockResponse responds with a runtime block
+type RuntimeBlockResponse struct {
+	Block *runtime_block.Block `json:"result"`
+}
+
+// RuntimeStateResponse responds with the roothash state of a runtime
+type RuntimeStateResponse struct {
+	RuntimeState *roothash_api.RuntimeState `json:"result"`
+}
+
+// RoothashEventsResponse responds with roothash events at a height
+type RoothashEventsResponse struct {
+	Events []*roothash_api.Event `json:"result"`
+}
+
+// RoundResultsResponse responds with the results of the last normal round
+// of a runtime, including the results of the runtime messages it emitted
+type RoundResultsResponse struct {
+	RoundResults *roothash_api.RoundResults `json:"result"`
+}
+
+// IncomingMessagesResponse responds with the incoming message queue of a
+// runtime
+type IncomingMessagesResponse struct {
+	IncomingMessages *IncomingMessages `json:"result"`
+}
+
+// IncomingMessages holds the incoming message queue metadata of a runtime
+// together with the queued messages
+type IncomingMessages struct {
+	Meta     *runtime_message.IncomingMessageQueueMeta `json:"meta"`
+	Messages []*runtime_message.IncomingMessage        `json:"messages"`
+}
+
+// KeyManagerStatusesResponse responds with the statuses of all key managers
+type KeyManagerStatusesResponse struct {
+	Statuses []*keymanager_api.Status `json:"result"`
+}
+
+// KeyManagerStatusResponse responds with the status of a key manager
+type KeyManagerStatusResponse struct {
+	Status *keymanager_api.Status `json:"result"`
+}
+
+// KeyManagerPolicyResponse responds with the signed policy of a key manager
+type KeyManagerPolicyResponse struct {
+	Policy *keymanager_api.SignedPolicySGX `json:"result"`
+}
+
+// KeyManagerNodeCheckResponse responds with whether node IDs are in the node
+// set of a key manager
+type KeyManagerNodeCheckResponse struct {
+	NodeCheck *KeyManagerNodeCheck `json:"result"`
+}
+
+// KeyManagerNodeCheck holds the membership of each checked node ID in the
+// node set of a key manager, AllPresent is only true when every node is in it
+type KeyManagerNodeCheck struct {
+	RuntimeID  common_namespace.Namespace `json:"runtime_id"`
+	AllPresent bool                       `json:"all_present"`
+	Nodes      []KeyManagerNodeMembership `json:"nodes"`
+}
+
+// KeyManagerNodeMembership holds whether a node ID is in the node set of a
+// key manager. Configured nodes whose identity couldn't be retrieved have an
+// error instead of a node ID.
+type KeyManagerNodeMembership struct {
+	NodeName  string                      `json:"node_name,omitempty"`
+	NodeID    *common_signature.PublicKey `json:"node_id,omitempty"`
+	InNodeSet bool                        `json:"in_node_set"`
+	Error     string                      `json:"error,omitempty"`
+}
+
+// EntityOverviewResponse responds with an overview of an entity's nodes
+type EntityOverviewResponse struct {
+	Overview *EntityOverview `json:"result"`
+}
+
+// EntityOverview holds an entity with an overview of each of its registered
+// nodes, nodes the entity lists that aren't registered are listed by ID
+type EntityOverview struct {
+	Entity            *common_entity.Entity        `json:"entity"`
+	CurrentEpoch      beacon_api.EpochTime         `json:"current_epoch"`
+	Nodes             []NodeOverview               `json:"nodes"`
+	UnregisteredNodes []common_signature.PublicKey `json:"unregistered_nodes"`
+}
+
+// NodeOverview holds the roles, runtimes and addresses of a registered node
+// together with its registration expiry, freeze status and validator set
+// membership
+type NodeOverview struct {
+	ID                 common_signature.PublicKey   `json:"id"`
+	Roles              []string                     `json:"roles"`
+	Runtimes           []common_namespace.Namespace `json:"runtimes"`
+	TLSPubKey          common_signature.PublicKey   `json:"tls_pub_key"`
+	P2PID              common_signature.PublicKey   `json:"p2p_id"`
+	P2PAddresses       []string                     `json:"p2p_addresses"`
+	ConsensusID        common_signature.PublicKey   `json:"consensus_id"`
+	ConsensusAddresses []string                     `json:"consensus_addresses"`
+	Expiration         uint64                       `json:"expiration"`
+	EpochsUntilExpiry  int64                        `json:"epochs_until_expiry"`
+	Expired            bool                         `json:"expired"`
+	Frozen             bool                         `json:"frozen"`
+	FreezeEndTime      beacon_api.EpochTime         `json:"freeze_end_time,omitempty"`
+	InValidatorSet     bool                         `json:"in_validator_set"`
+	VotingPower        int64                        `json:"voting_power,omitempty"`
+}
+
+// NodeLookupResponse responds with a node resolved from one of its
+// identifiers
+type NodeLookupResponse struct {
+	Lookup *NodeLookup `json:"result"`
+}
+
+// NodeLookup holds a node descriptor with its owning entity and the kind of
+// identifier that matched it
+type NodeLookup struct {
+	MatchedBy string                `json:"matched_by"`
+	Node      *common_node.Node     `json:"node"`
+	Entity    *common_entity.Entity `json:"entity"`
+}
+
+// NodeCommitteesResponse responds with the committee memberships of a node
+type NodeCommitteesResponse struct {
+	NodeCommittees *NodeCommittees `json:"result"`
+}
+
+// NodeCommittees holds the committees a node is a member of in an epoch and
+// the changes in its membership over the preceding epochs, latest first
+type NodeCommittees struct {
+	NodeID      common_signature.PublicKey  `json:"node_id"`
+	Epoch       beacon_api.EpochTime        `json:"epoch"`
+	Memberships []CommitteeMembership       `json:"memberships"`
+	History     []CommitteeMembershipChange `json:"history"`
+}
+
+// CommitteeMembership holds the role of a node in the committee of a runtime
+type CommitteeMembership struct {
+	RuntimeID common_namespace.Namespace  `json:"runtime_id"`
+	Kind      scheduler_api.CommitteeKind `json:"kind"`
+	Role      scheduler_api.Role          `json:"role"`
+}
+
+// CommitteeMembershipChange holds the committees a node joined and left
+// at the start of an epoch compared with the epoch before it
+type CommitteeMembershipChange struct {
+	Epoch  beacon_api.EpochTime  `json:"epoch"`
+	Height int64                 `json:"height"`
+	Joined []CommitteeMembership `json:"joined"`
+	Left   []CommitteeMembership `json:"left"`
+}
+
+// ValidatorsDiffResponse responds with the differences between two
+// validator sets
+type ValidatorsDiffResponse struct {
+	ValidatorsDiff *ValidatorsDiff `json:"result"`
+}
+
+// ValidatorsDiff holds the validators added to and removed from the
+// validator set between two heights and those whose voting power changed
+type ValidatorsDiff struct {
+	FromHeight int64                      `json:"from_height"`
+	ToHeight   int64                      `json:"to_height"`
+	Added      []*scheduler_api.Validator `json:"added"`
+	Removed    []*scheduler_api.Validator `json:"removed"`
+	Changed    []VotingPowerChange        `json:"changed"`
+}
+
+// VotingPowerChange holds the voting power of a validator before and after
+// it changed
+type VotingPowerChange struct {
+	ID              common_signature.PublicKey `json:"id"`
+	EntityID        common_signature.PublicKey `json:"entity_id"`
+	FromVotingPower int64                      `json:"from_voting_power"`
+	ToVotingPower   int64                      `json:"to_voting_power"`
+}
+
+// VotingPowerSeriesResponse responds with the voting power of a node per
+// epoch
+type VotingPowerSeriesResponse struct {
+	Series []VotingPowerSample `json:"result"`
+}
+
+// VotingPowerSample holds the voting power of a node at the start of an
+// epoch, it is zero when the node isn't in the validator set
+type VotingPowerSample struct {
+	Epoch          beacon_api.EpochTime `json:"epoch"`
+	Height         int64                `json:"height"`
+	InValidatorSet bool                 `json:"in_validator_set"`
+	VotingPower    int64                `json:"voting_power"`
+}
+
+// ExpectedValidatorsResponse responds with the validator set expected to be
+// elected at the next epoch
+type ExpectedValidatorsResponse struct {
+	ExpectedValidators *ExpectedValidators `json:"result"`
+}
+
+// ExpectedValidators holds the entities eligible for the validator set of an
+// epoch ranked by escrow balance, the balance of the last elected entity and
+// the scheduler parameters the election follows
+type ExpectedValidators struct {
+	Height            int64                              `json:"height"`
+	Epoch             beacon_api.EpochTime               `json:"epoch"`
+	Parameters        *scheduler_api.ConsensusParameters `json:"parameters"`
+	ElectedValidators int                                `json:"elected_validators"`
+	CutoffBalance     *common_quantity.Quantity          `json:"cutoff_balance"`
+	Entity            *RankedEntity                      `json:"entity,omitempty"`
+	Entities          []RankedEntity                     `json:"entities"`
+}
+
+// RankedEntity holds the rank of an entity in the validator election, its
+// eligible validator nodes and how far its escrow balance is from the cutoff
+type RankedEntity struct {
+	Rank          int                          `json:"rank"`
+	EntityID      common_signature.PublicKey   `json:"entity_id"`
+	EscrowBalance common_quantity.Quantity     `json:"escrow_balance"`
+	Nodes         []common_signature.PublicKey `json:"nodes"`
+	Elected       bool                         `json:"elected"`
+	ElectedNodes  int                          `json:"elected_nodes"`
+	VotingPower   int64                        `json:"voting_power"`
+	AboveCutoff   bool                         `json:"above_cutoff"`
+	Margin        common_quantity.Quantity     `json:"margin"`
+}
+
+// RegistryChangesResponse responds with the registry changes of a height
+// range
+type RegistryChangesResponse struct {
+	Changes []RegistryChange `json:"result"`
+}
+
+// RegistryChange is an entity, node or runtime registry change flattened
+// from a registry event, IDs that don't apply to a change are left out
+type RegistryChange struct {
+	Height    int64                       `json:"height"`
+	TxHash    *hash.Hash                  `json:"tx_hash,omitempty"`
+	Type      string                      `json:"type"`
+	EntityID  *common_signature.PublicKey `json:"entity_id,omitempty"`
+	NodeID    *common_signature.PublicKey `json:"node_id,omitempty"`
+	RuntimeID *common_namespace.Namespace `json:"runtime_id,omitempty"`
+}
+
 // SuccessResponsed Assinging Variable Responses that do not need to be changed.
 var SuccessResponsed = SuccessResponse{Result: "pong"}
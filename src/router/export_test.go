@@ -0,0 +1,7 @@
+package router
+
+// ValidateSettings exposes validateSettings to the tests
+var ValidateSettings = validateSettings
+
+// RestartBackground exposes restartBackground to the tests
+var RestartBackground = restartBackground
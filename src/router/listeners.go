@@ -0,0 +1,151 @@
+package router
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	conf "github.com/SimplyVC/oasis_api_server/src/config"
+	lgr "github.com/SimplyVC/oasis_api_server/src/logger"
+)
+
+// adminPrefix is the path prefix of the admin API
+const adminPrefix = "/api/admin/"
+
+// Serve serves a router on every listener of the configuration until ctx is
+// cancelled or one of them fails, TCP listeners are served over HTTPS when a
+// certificate is set. Middleware wraps the router in the given order, also
+// seeing requests refused by a listener. Once stopped, listeners stop
+// accepting connections and requests in flight are given the shutdown
+// timeout to finish before their connections are closed.
+func Serve(ctx context.Context, cfg *conf.Config, router http.Handler,
+	middleware ...func(http.Handler) http.Handler) error {
+
+	var certs *CertReloader
+	if len(cfg.APIServer.TLSCertPath) != 0 {
+		var err error
+		certs, err = NewCertReloader(cfg.APIServer)
+		if err != nil {
+			return err
+		}
+	}
+
+	var servers []*http.Server
+	errs := make(chan error, len(cfg.Listeners))
+	for _, listener := range cfg.Listeners {
+		ln, err := listen(listener, certs)
+		if err != nil {
+			shutdown(servers, 0)
+			return err
+		}
+
+		handler := restrictRoutes(router, listener.Admin)
+		for i := len(middleware) - 1; i >= 0; i-- {
+			handler = middleware[i](handler)
+		}
+		server := &http.Server{Handler: handler}
+		servers = append(servers, server)
+		go func() {
+			errs <- server.Serve(ln)
+		}()
+	}
+
+	var err error
+	select {
+	case <-ctx.Done():
+		lgr.Info.Println("Stopping API, waiting up to ",
+			cfg.APIServer.ShutdownTimeout, " for requests in flight")
+	case err = <-errs:
+		lgr.Error.Println("Serving API has failed, stopping it : ", err)
+	}
+	if shutdownErr := shutdown(servers,
+		cfg.APIServer.ShutdownTimeout); err == nil {
+		err = shutdownErr
+	}
+	return err
+}
+
+// shutdown stops servers from accepting connections and waits for their
+// requests in flight to finish, closing the connections left after timeout
+func shutdown(servers []*http.Server, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, len(servers))
+	for _, server := range servers {
+		wg.Add(1)
+		go func(server *http.Server) {
+			defer wg.Done()
+			if err := server.Shutdown(ctx); err != nil {
+				server.Close()
+				errs <- err
+			}
+		}(server)
+	}
+	wg.Wait()
+	close(errs)
+
+	if err := <-errs; err != nil {
+		return fmt.Errorf("requests in flight didn't finish within %s : %v",
+			timeout, err)
+	}
+	return nil
+}
+
+// listen opens a listener, removing a unix socket left behind by a previous
+// run and setting the permissions of the new one
+func listen(listener conf.Listener, certs *CertReloader) (net.Listener,
+	error) {
+
+	path, unix := strings.CutPrefix(listener.Address, "unix:")
+	if !unix {
+		ln, err := net.Listen("tcp", listener.Address)
+		if err != nil {
+			return nil, err
+		}
+		if certs == nil {
+			lgr.Info.Println("Serving API on ", listener.Address)
+			return ln, nil
+		}
+		lgr.Info.Println("Serving API over HTTPS on ", listener.Address)
+		return tls.NewListener(ln, certs.TLSConfig()), nil
+	}
+
+	if info, err := os.Lstat(path); err == nil &&
+		info.Mode()&os.ModeSocket != 0 {
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, listener.SocketMode); err != nil {
+		ln.Close()
+		return nil, err
+	}
+	lgr.Info.Printf("Serving API on unix socket %s with permissions %#o",
+		path, listener.SocketMode)
+	return ln, nil
+}
+
+// restrictRoutes only serves the admin API on admin listeners and every
+// other route on the other listeners, so the admin API isn't served at all
+// without an admin listener
+func restrictRoutes(router http.Handler, admin bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, adminPrefix) != admin {
+			http.NotFound(w, r)
+			return
+		}
+		router.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,224 @@
+package router_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	conf "github.com/SimplyVC/oasis_api_server/src/config"
+	"github.com/SimplyVC/oasis_api_server/src/router"
+)
+
+// unixClient returns a client sending every request to a unix socket
+func unixClient(path string) *http.Client {
+	return &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn,
+			error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", path)
+		}}}
+}
+
+// status returns the status code of a request to a unix socket
+func status(t *testing.T, path, url string) int {
+	resp, err := unixClient(path).Get("http://localhost" + url)
+	if err != nil {
+		t.Fatalf("Failed to request %s on %s : %v", url, path, err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+// waitForSocket waits until a unix socket exists with the given permissions
+func waitForSocket(t *testing.T, path string, mode os.FileMode) {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		info, err := os.Stat(path)
+		if err == nil && info.Mode().Perm() == mode {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Failed to listen on unix socket %s", path)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestServe_Success(t *testing.T) {
+	dir := t.TempDir()
+	apiSocket := filepath.Join(dir, "api.sock")
+	adminSocket := filepath.Join(dir, "admin.sock")
+
+	// A socket left behind by a previous run is replaced
+	stale, err := net.Listen("unix", apiSocket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/ping", func(http.ResponseWriter, *http.Request) {})
+	mux.HandleFunc("/api/admin/reload",
+		func(http.ResponseWriter, *http.Request) {})
+	// Middleware also sees the requests refused by a listener
+	var seen atomic.Int32
+	counter := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter,
+			r *http.Request) {
+			seen.Add(1)
+			next.ServeHTTP(w, r)
+		})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go router.Serve(ctx, &conf.Config{Listeners: []conf.Listener{
+		{Address: "unix:" + apiSocket, SocketMode: 0600},
+		{Address: "unix:" + adminSocket, SocketMode: 0660, Admin: true},
+	}}, mux, counter)
+
+	waitForSocket(t, apiSocket, 0600)
+	waitForSocket(t, adminSocket, 0660)
+
+	for _, request := range []struct {
+		socket   string
+		url      string
+		expected int
+	}{
+		{apiSocket, "/api/ping", http.StatusOK},
+		{apiSocket, "/api/admin/reload", http.StatusNotFound},
+		{adminSocket, "/api/admin/reload", http.StatusOK},
+		{adminSocket, "/api/ping", http.StatusNotFound},
+	} {
+		if code := status(t, request.socket, request.url); code !=
+			request.expected {
+			t.Errorf("Request of %s on %s got status %d want %d",
+				request.url, request.socket, code, request.expected)
+		}
+	}
+	if seen.Load() != 4 {
+		t.Errorf("Middleware saw %d requests want 4", seen.Load())
+	}
+}
+
+func TestServe_WithoutAdmin(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "api.sock")
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/ping", func(http.ResponseWriter, *http.Request) {})
+	mux.HandleFunc("/api/admin/reload",
+		func(http.ResponseWriter, *http.Request) {})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go router.Serve(ctx, &conf.Config{Listeners: []conf.Listener{
+		{Address: "unix:" + socket, SocketMode: 0600},
+	}}, mux)
+	waitForSocket(t, socket, 0600)
+
+	// The admin API is only served on admin listeners
+	if code := status(t, socket, "/api/ping"); code != http.StatusOK {
+		t.Errorf("Request of /api/ping got status %d want 200", code)
+	}
+	if code := status(t, socket, "/api/admin/reload"); code !=
+		http.StatusNotFound {
+		t.Errorf("Request of /api/admin/reload got status %d want 404",
+			code)
+	}
+}
+
+func TestServe_Shutdown(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "api.sock")
+
+	// The handler only returns once the shutdown has started
+	started := make(chan struct{})
+	release := make(chan struct{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/slow", func(w http.ResponseWriter, _ *http.Request) {
+		close(started)
+		<-release
+		w.Write([]byte("done"))
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- router.Serve(ctx, &conf.Config{
+			APIServer: conf.APIServer{ShutdownTimeout: 5 * time.Second},
+			Listeners: []conf.Listener{
+				{Address: "unix:" + socket, SocketMode: 0600}},
+		}, mux)
+	}()
+	waitForSocket(t, socket, 0600)
+
+	codes := make(chan int, 1)
+	go func() {
+		resp, err := unixClient(socket).Get("http://localhost/api/slow")
+		if err != nil {
+			codes <- 0
+			return
+		}
+		resp.Body.Close()
+		codes <- resp.StatusCode
+	}()
+	<-started
+	cancel()
+
+	// New connections are refused while the request in flight finishes
+	time.Sleep(100 * time.Millisecond)
+	if _, err := net.Dial("unix", socket); err == nil {
+		t.Errorf("Connection accepted after shutdown started")
+	}
+	close(release)
+
+	if code := <-codes; code != http.StatusOK {
+		t.Errorf("Request in flight got status %d want %d", code,
+			http.StatusOK)
+	}
+	if err := <-served; err != nil {
+		t.Errorf("Failed to shut down : %v", err)
+	}
+}
+
+func TestServe_ShutdownTimeout(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "api.sock")
+
+	// The handler never finishes on its own
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/stuck", func(http.ResponseWriter, *http.Request) {
+		close(started)
+		<-release
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- router.Serve(ctx, &conf.Config{
+			APIServer: conf.APIServer{
+				ShutdownTimeout: 100 * time.Millisecond},
+			Listeners: []conf.Listener{
+				{Address: "unix:" + socket, SocketMode: 0600}},
+		}, mux)
+	}()
+	waitForSocket(t, socket, 0600)
+
+	go unixClient(socket).Get("http://localhost/api/stuck")
+	<-started
+	cancel()
+
+	select {
+	case err := <-served:
+		if err == nil {
+			t.Errorf("Expected error after shutdown timeout")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Shutdown didn't stop after its timeout")
+	}
+}
@@ -0,0 +1,18 @@
+package router
+
+import (
+	"net/http"
+
+	lgr "github.com/SimplyVC/oasis_api_server/src/logger"
+)
+
+// AssignRequestID gives every request an ID, taken from its X-Request-ID
+// header when valid, that is echoed in the response and attached to the log
+// lines and node calls made while serving it
+func AssignRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := lgr.NewRequestID(r.Header.Get(lgr.RequestIDHeader))
+		w.Header().Set(lgr.RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(lgr.WithRequestID(r.Context(), id)))
+	})
+}
@@ -0,0 +1,36 @@
+package router_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	lgr "github.com/SimplyVC/oasis_api_server/src/logger"
+	"github.com/SimplyVC/oasis_api_server/src/router"
+)
+
+func TestAssignRequestID(t *testing.T) {
+	var received string
+	handler := router.AssignRequestID(http.HandlerFunc(
+		func(_ http.ResponseWriter, r *http.Request) {
+			received = lgr.RequestID(r.Context())
+		}))
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/api/ping", nil)
+	request.Header.Set("X-Request-ID", "trace-42")
+	handler.ServeHTTP(recorder, request)
+	if received != "trace-42" ||
+		recorder.Header().Get("X-Request-ID") != "trace-42" {
+		t.Errorf("Failed to keep request ID, got %q and header %q",
+			received, recorder.Header().Get("X-Request-ID"))
+	}
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet,
+		"/api/ping", nil))
+	if received == "" || recorder.Header().Get("X-Request-ID") != received {
+		t.Errorf("Failed to assign request ID, got %q and header %q",
+			received, recorder.Header().Get("X-Request-ID"))
+	}
+}
@@ -1,44 +1,62 @@
 package router
 
 import (
-	"log"
+	"context"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/gorilla/mux"
 
+	"github.com/SimplyVC/oasis_api_server/src/accesslog"
 	conf "github.com/SimplyVC/oasis_api_server/src/config"
 	handler "github.com/SimplyVC/oasis_api_server/src/handlers"
+	"github.com/SimplyVC/oasis_api_server/src/incidents"
+	"github.com/SimplyVC/oasis_api_server/src/ledger"
 	lgr "github.com/SimplyVC/oasis_api_server/src/logger"
-	"github.com/zenazn/goji/graceful"
+	"github.com/SimplyVC/oasis_api_server/src/sampler"
+	"github.com/SimplyVC/oasis_api_server/src/tracing"
 )
 
-// StartServer starts server by setting router and all endpoints
+// StartServer starts server by setting router and all endpoints, it serves
+// the API until SIGINT or SIGTERM is received and returns once requests in
+// flight finished and background work stopped
 func StartServer() error {
 
-	// Load port configurations
-	mainConf, err2 := conf.LoadMainConfiguration()
+	// Load and validate configuration files, environment and flags, the
+	// settings of every feature included
+	conf.AddValidator(validateSettings)
+	cfg, err2 := conf.Load()
 	if err2 != nil {
-		lgr.Error.Println("Loading of Port configuration has failed!")
-		// Abort Program no Port configured to run API on
-		os.Exit(0)
+		lgr.Error.Println("Loading of configuration has failed : ", err2)
+		// Abort Program no valid configuration to run API with
+		return err2
 	}
+	mainConf := cfg.Sections
+	configureLogging(mainConf)
+	configureAccessLog(mainConf)
+	configureTracing(mainConf)
 
-	// Load socket configuration but do not use them
-	_, err3 := conf.LoadNodesConfiguration()
-	if err3 != nil {
-		lgr.Error.Println("Loading of Socket configuration has failed!")
-		// Abort Program no Sockets configured to run API on
-		os.Exit(0)
-	}
+	// Stop gracefully on the first signal, a second one kills the process
+	// as stopping restores the default signal handling
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt,
+		syscall.SIGTERM)
+	defer stop()
+	context.AfterFunc(ctx, stop)
 
-	// Load sentry configuration
-	_, err4 := conf.LoadSentryConfiguration()
-	if err4 != nil {
-		lgr.Error.Println("Loading of Sentry configuration has failed!")
-	}
+	startBackground(mainConf)
+	defer stopBackground()
 
-	apiPort := mainConf["api_server"]["port"]
-	lgr.Info.Println("Loaded port : ", apiPort)
+	// Reload configuration when its files change or on SIGHUP, handlers
+	// read the nodes on every request so only background work restarts
+	conf.OnReload(reconfigureLogging)
+	conf.OnReload(reconfigureAccessLog)
+	conf.OnReload(reconfigureTracing)
+	conf.OnReload(restartBackground)
+	if err := conf.StartWatching(); err != nil {
+		lgr.Error.Println("Watching of configuration files has failed : ",
+			err)
+	}
 
 	// Router object to handle requests
 	router := mux.NewRouter().StrictSlash(true)
@@ -47,6 +65,8 @@ func StartServer() error {
 	router.HandleFunc("/api/ping", handler.Pong).Methods("Get")
 	router.HandleFunc("/api/getconnectionslist",
 		handler.GetConnections).Methods("Get")
+	router.HandleFunc("/api/incidents",
+		handler.GetIncidents).Methods("Get")
 
 	// Router Handlers to handle Consensus API Calls
 	router.HandleFunc("/api/consensus/genesis",
@@ -89,6 +109,12 @@ func StartServer() error {
 		handler.GetNode).Methods("Get")
 	router.HandleFunc("/api/registry/runtime",
 		handler.GetRuntime).Methods("Get")
+	router.HandleFunc("/api/registry/entityoverview",
+		handler.GetEntityOverview).Methods("Get")
+	router.HandleFunc("/api/registry/nodelookup",
+		handler.GetNodeLookup).Methods("Get")
+	router.HandleFunc("/api/registry/changes",
+		handler.GetRegistryChanges).Methods("Get")
 
 	// Router Handlers to handle Staking API Calls
 	router.HandleFunc("/api/staking/totalsupply",
@@ -115,6 +141,12 @@ func StartServer() error {
 		handler.GetDebondingDelegations).Methods("Get")
 	router.HandleFunc("/api/staking/events",
 		handler.GetEvents).Methods("Get")
+	router.HandleFunc("/api/staking/commission",
+		handler.GetCommission).Methods("Get")
+	router.HandleFunc("/api/staking/series",
+		handler.GetStakingSeries).Methods("Get")
+	router.HandleFunc("/api/staking/ledger",
+		handler.GetLedger).Methods("Get")
 
 	// Router Handlers to handle NodeController API Calls
 	router.HandleFunc("/api/nodecontroller/synced",
@@ -127,6 +159,64 @@ func StartServer() error {
 		handler.GetCommittees).Methods("Get")
 	router.HandleFunc("/api/scheduler/genesis",
 		handler.GetSchedulerStateToGenesis).Methods("Get")
+	router.HandleFunc("/api/scheduler/nodecommittees",
+		handler.GetNodeCommittees).Methods("Get")
+	router.HandleFunc("/api/scheduler/validatorsdiff",
+		handler.GetValidatorsDiff).Methods("Get")
+	router.HandleFunc("/api/scheduler/votingpower",
+		handler.GetVotingPowerSeries).Methods("Get")
+	router.HandleFunc("/api/scheduler/expectedvalidators",
+		handler.GetExpectedValidators).Methods("Get")
+
+	// Router Handlers to handle RootHash API Calls
+	router.HandleFunc("/api/roothash/latestblock",
+		handler.GetRuntimeLatestBlock).Methods("Get")
+	router.HandleFunc("/api/roothash/runtimestate",
+		handler.GetRuntimeState).Methods("Get")
+	router.HandleFunc("/api/roothash/events",
+		handler.GetRoothashEvents).Methods("Get")
+	router.HandleFunc("/api/roothash/lastroundresults",
+		handler.GetLastRoundResults).Methods("Get")
+	router.HandleFunc("/api/roothash/incomingmessages",
+		handler.GetIncomingMessages).Methods("Get")
+
+	// Router Handlers to handle KeyManager API Calls
+	router.HandleFunc("/api/keymanager/statuses",
+		handler.GetKeyManagerStatuses).Methods("Get")
+	router.HandleFunc("/api/keymanager/status",
+		handler.GetKeyManagerStatus).Methods("Get")
+	router.HandleFunc("/api/keymanager/policy",
+		handler.GetKeyManagerPolicy).Methods("Get")
+	router.HandleFunc("/api/keymanager/nodecheck",
+		handler.GetKeyManagerNodeCheck).Methods("Get")
+
+	// Router Handlers to handle Beacon API Calls
+	router.HandleFunc("/api/beacon/baseepoch",
+		handler.GetBaseEpoch).Methods("Get")
+	router.HandleFunc("/api/beacon/epochblock",
+		handler.GetEpochBlock).Methods("Get")
+	router.HandleFunc("/api/beacon/futureepoch",
+		handler.GetFutureEpoch).Methods("Get")
+	router.HandleFunc("/api/beacon/parameters",
+		handler.GetBeaconParameters).Methods("Get")
+	router.HandleFunc("/api/beacon/state",
+		handler.GetBeaconState).Methods("Get")
+	router.HandleFunc("/api/beacon/epochtime",
+		handler.GetEpochTime).Methods("Get")
+
+	// Router Handlers to handle Governance API Calls
+	router.HandleFunc("/api/governance/activeproposals",
+		handler.GetActiveProposals).Methods("Get")
+	router.HandleFunc("/api/governance/proposal",
+		handler.GetProposal).Methods("Get")
+	router.HandleFunc("/api/governance/votes",
+		handler.GetVotes).Methods("Get")
+	router.HandleFunc("/api/governance/pendingupgrades",
+		handler.GetPendingUpgrades).Methods("Get")
+	router.HandleFunc("/api/governance/events",
+		handler.GetGovernanceEvents).Methods("Get")
+	router.HandleFunc("/api/governance/consensusparameters",
+		handler.GetGovernanceConsensusParameters).Methods("Get")
 
 	// Router Handlers to handle Prometheus API Calls
 	router.HandleFunc("/api/prometheus/gauge",
@@ -140,10 +230,206 @@ func StartServer() error {
 	router.HandleFunc("/api/exporter/counter",
 		handler.NodeExporterQueryCounter).Methods("Get")
 
+	// Router Handlers to handle Admin API Calls, all of them require the
+	// admin token
+	admin := router.PathPrefix("/api/admin").Subrouter()
+	admin.Use(handler.RequireAdminToken)
+	admin.HandleFunc("/reload",
+		handler.GetReloadStatus).Methods("Get")
+	admin.HandleFunc("/nodes",
+		handler.GetAdminNodes).Methods("Get")
+	admin.HandleFunc("/nodes",
+		handler.AddAdminNode).Methods("Post")
+	admin.HandleFunc("/nodes",
+		handler.UpdateAdminNode).Methods("Put")
+	admin.HandleFunc("/nodes",
+		handler.RemoveAdminNode).Methods("Delete")
+	admin.HandleFunc("/sentries",
+		handler.GetAdminSentries).Methods("Get")
+	admin.HandleFunc("/sentries",
+		handler.AddAdminSentry).Methods("Post")
+	admin.HandleFunc("/sentries",
+		handler.UpdateAdminSentry).Methods("Put")
+	admin.HandleFunc("/sentries",
+		handler.RemoveAdminSentry).Methods("Delete")
+
 	// Router Handlers to handle Sentry API Calls
 	router.HandleFunc("/api/sentry/addresses",
 		handler.GetSentryAddresses).Methods("Get")
 
-	log.Fatal(graceful.ListenAndServe(":"+apiPort, router))
-	return nil
+	// Serve the API on every configured listener
+	return Serve(ctx, cfg, router, AssignRequestID, tracing.Handler,
+		accesslog.Handler)
+}
+
+// validateSettings checks the sections of the main configuration that
+// configure logging, the access log, tracing and the background work, so
+// that a configuration with invalid settings is refused instead of leaving
+// the feature off
+func validateSettings(mainConf map[string]map[string]string) []string {
+	var problems []string
+	if _, err := lgr.LoadSettings(mainConf); err != nil {
+		problems = append(problems, err.Error())
+	}
+	if _, err := accesslog.LoadSettings(mainConf); err != nil {
+		problems = append(problems, err.Error())
+	}
+	if _, err := tracing.LoadSettings(mainConf); err != nil {
+		problems = append(problems, err.Error())
+	}
+	if _, err := sampler.LoadSettings(mainConf); err != nil {
+		problems = append(problems, err.Error())
+	}
+	if _, err := ledger.LoadSettings(mainConf); err != nil {
+		problems = append(problems, err.Error())
+	}
+	if _, err := incidents.LoadSettings(mainConf); err != nil {
+		problems = append(problems, err.Error())
+	}
+	return problems
+}
+
+// configureLogging sets the level, format and output of the loggers from
+// the logging section of the main configuration
+func configureLogging(mainConf map[string]map[string]string) {
+	settings, err := lgr.LoadSettings(mainConf)
+	if err != nil {
+		lgr.Error.Println("Loading of Logging configuration has failed : ",
+			err)
+		return
+	}
+	if err := lgr.Configure(settings); err != nil {
+		lgr.Error.Println("Configuring of Logging has failed : ", err)
+	}
+}
+
+// reconfigureLogging configures the loggers again after a configuration
+// reload changed the logging section, so that the level can be changed
+// without a restart
+func reconfigureLogging(previous, next *conf.Config) {
+	if conf.SectionsChanged(previous, next, "logging") {
+		configureLogging(next.Sections)
+		lgr.Info.Println("Reconfigured logging with reloaded configuration")
+	}
+}
+
+// configureAccessLog sets how requests are logged from the access_log
+// section of the main configuration
+func configureAccessLog(mainConf map[string]map[string]string) {
+	settings, err := accesslog.LoadSettings(mainConf)
+	if err != nil {
+		lgr.Error.Println("Loading of Access Log configuration has "+
+			"failed : ", err)
+		return
+	}
+	if err := accesslog.Configure(settings); err != nil {
+		lgr.Error.Println("Configuring of Access Log has failed : ", err)
+	}
+}
+
+// reconfigureAccessLog configures the access log again after a
+// configuration reload changed the access_log section
+func reconfigureAccessLog(previous, next *conf.Config) {
+	if conf.SectionsChanged(previous, next, "access_log") {
+		configureAccessLog(next.Sections)
+		lgr.Info.Println("Reconfigured access log with reloaded " +
+			"configuration")
+	}
+}
+
+// configureTracing sets how spans are recorded and exported from the
+// tracing section of the main configuration
+func configureTracing(mainConf map[string]map[string]string) {
+	settings, err := tracing.LoadSettings(mainConf)
+	if err != nil {
+		lgr.Error.Println("Loading of Tracing configuration has failed : ",
+			err)
+		return
+	}
+	if err := tracing.Configure(settings); err != nil {
+		lgr.Error.Println("Configuring of Tracing has failed : ", err)
+	}
+}
+
+// reconfigureTracing configures tracing again after a configuration reload
+// changed the tracing section
+func reconfigureTracing(previous, next *conf.Config) {
+	if conf.SectionsChanged(previous, next, "tracing") {
+		configureTracing(next.Sections)
+		lgr.Info.Println("Reconfigured tracing with reloaded configuration")
+	}
+}
+
+// startBackground starts the sampler, ledger and incident monitor that are
+// enabled in the main configuration
+func startBackground(mainConf map[string]map[string]string) {
+
+	// Load sampler configuration and start sampling if it is enabled
+	samplerSettings, err1 := sampler.LoadSettings(mainConf)
+	if err1 != nil {
+		lgr.Error.Println("Loading of Sampler configuration has failed : ",
+			err1)
+	} else if samplerSettings.Enabled {
+		if err := sampler.Start(samplerSettings); err != nil {
+			lgr.Error.Println("Starting of Sampler has failed : ", err)
+		}
+	}
+
+	// Load ledger configuration and start keeping it if it is enabled
+	ledgerSettings, err2 := ledger.LoadSettings(mainConf)
+	if err2 != nil {
+		lgr.Error.Println("Loading of Ledger configuration has failed : ",
+			err2)
+	} else if ledgerSettings.Enabled {
+		if err := ledger.Start(ledgerSettings); err != nil {
+			lgr.Error.Println("Starting of Ledger has failed : ", err)
+		}
+	}
+
+	// Load incident monitor configuration and start it if it is enabled
+	incidentSettings, err3 := incidents.LoadSettings(mainConf)
+	if err3 != nil {
+		lgr.Error.Println("Loading of Incident configuration has failed : ",
+			err3)
+	} else if incidentSettings.Enabled {
+		if err := incidents.Start(incidentSettings); err != nil {
+			lgr.Error.Println("Starting of Incident monitor has failed : ",
+				err)
+		}
+	}
+}
+
+// restartBackground restarts the sampler, ledger and incident monitor after
+// a configuration reload changed their nodes or their settings, so that they
+// pick up added and removed nodes
+func restartBackground(previous, next *conf.Config) {
+	added, removed, changed := conf.DiffNodes(previous, next)
+	if len(added)+len(removed)+len(changed) == 0 &&
+		!conf.SectionsChanged(previous, next, "sampler", "ledger",
+			"incidents") {
+		return
+	}
+
+	lgr.Info.Println("Restarting Sampler, Ledger and Incident monitor " +
+		"with reloaded configuration")
+	sampler.Stop()
+	ledger.Stop()
+	incidents.Stop()
+	startBackground(next.Sections)
+}
+
+// stopBackground stops watching the configuration so that no reload starts
+// background work again and closes the access log, then stops the sampler,
+// ledger and incident monitor which closes their node connections and
+// flushes their stores, and finally flushes the recorded spans. There is
+// nothing else to drain, as the API has no streaming endpoints and handlers
+// close the node connections they open before they return.
+func stopBackground() {
+	conf.StopWatching()
+	accesslog.Close()
+	sampler.Stop()
+	ledger.Stop()
+	incidents.Stop()
+	lgr.Info.Println("Stopped Sampler, Ledger and Incident monitor")
+	tracing.Close()
 }
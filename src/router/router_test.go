@@ -0,0 +1,89 @@
+package router_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	conf "github.com/SimplyVC/oasis_api_server/src/config"
+	"github.com/SimplyVC/oasis_api_server/src/router"
+	"github.com/SimplyVC/oasis_api_server/src/sampler"
+)
+
+func TestValidateSettings(t *testing.T) {
+	problems := router.ValidateSettings(map[string]map[string]string{
+		"logging":    {"level": "loud"},
+		"access_log": {"format": "xml"},
+		"tracing":    {"exporter": "jaeger"},
+		"sampler":    {"enabled": "sometimes"},
+		"ledger":     {"enabled": "true"},
+		"incidents":  {"enabled": "true", "node_name": "Oasis_Local"},
+	})
+	for _, section := range []string{"logging", "access_log", "tracing",
+		"sampler", "ledger", "incidents"} {
+		found := false
+		for _, problem := range problems {
+			found = found || strings.HasPrefix(problem, section+" ")
+		}
+		if !found {
+			t.Errorf("Failed to report problem of %s in %v", section,
+				problems)
+		}
+	}
+
+	// Features left out of the configuration are valid
+	if problems := router.ValidateSettings(
+		map[string]map[string]string{}); len(problems) != 0 {
+		t.Errorf("Unexpected problems %v", problems)
+	}
+}
+
+// loadSampled loads a configuration sampling the given node into dir
+func loadSampled(t *testing.T, dir, name string) *conf.Config {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	os.WriteFile(path, []byte("api_server:\n  port: 3000\n"+
+		"sampler:\n  enabled: true\n  poll_interval: 3600\n"+
+		"  storage_dir: "+dir+"\n"+
+		"nodes:\n  - node_name: "+name+"\n"+
+		"    isocket_path: unix:"+filepath.Join(dir, "internal.sock")+"\n"),
+		0o600)
+	conf.SetFile(path)
+	cfg, err := conf.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+func TestRestartBackground(t *testing.T) {
+	dir := t.TempDir()
+	t.Cleanup(func() {
+		conf.SetFile("")
+		sampler.Stop()
+	})
+
+	// Nothing is restarted when neither the nodes nor the settings changed
+	previous := loadSampled(t, dir, "Oasis_Local")
+	router.RestartBackground(previous, previous)
+	if _, ok := sampler.GetStore("Oasis_Local"); ok {
+		t.Errorf("Unexpected restart of unchanged configuration.")
+	}
+
+	// A renamed node is sampled under its new name
+	next := loadSampled(t, dir, "Oasis_Renamed")
+	router.RestartBackground(previous, next)
+	if _, ok := sampler.GetStore("Oasis_Renamed"); !ok {
+		t.Errorf("Failed to restart sampler with added node.")
+	}
+
+	// Only the nodes of the new configuration are sampled after another
+	// restart
+	router.RestartBackground(next, loadSampled(t, dir, "Oasis_Local"))
+	if _, ok := sampler.GetStore("Oasis_Renamed"); ok {
+		t.Errorf("Failed to stop sampling removed node.")
+	}
+	if _, ok := sampler.GetStore("Oasis_Local"); !ok {
+		t.Errorf("Failed to restart sampler with added node.")
+	}
+}
@@ -0,0 +1,122 @@
+package router
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	conf "github.com/SimplyVC/oasis_api_server/src/config"
+	lgr "github.com/SimplyVC/oasis_api_server/src/logger"
+)
+
+// CertReloader serves the TLS certificate and client certificate
+// authorities of the API from disk, loading them again whenever their files
+// change so that certificates can be rotated without a restart
+type CertReloader struct {
+	certPath     string
+	keyPath      string
+	clientCAPath string
+	minVersion   uint16
+
+	mutex     sync.RWMutex
+	modTimes  [3]time.Time
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+}
+
+// NewCertReloader loads the TLS certificate, key and client certificate
+// authorities configured in the api_server section
+func NewCertReloader(settings conf.APIServer) (*CertReloader, error) {
+	c := &CertReloader{
+		certPath:     settings.TLSCertPath,
+		keyPath:      settings.TLSKeyPath,
+		clientCAPath: settings.TLSClientCAPath,
+		minVersion:   settings.TLSMinVersion,
+	}
+	if err := c.reload(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// TLSConfig returns a TLS configuration that checks for changed files on
+// every handshake
+func (c *CertReloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion:         c.minVersion,
+		GetConfigForClient: c.configForClient,
+	}
+}
+
+// configForClient returns the TLS configuration of a handshake with the
+// latest certificate, keeping the previous one if reloading it failed
+func (c *CertReloader) configForClient(*tls.ClientHelloInfo) (*tls.Config,
+	error) {
+
+	if err := c.reload(); err != nil {
+		lgr.Error.Println("Reloading of TLS certificate has failed, "+
+			"keeping the current one : ", err)
+	}
+
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	config := &tls.Config{
+		MinVersion:   c.minVersion,
+		Certificates: []tls.Certificate{*c.cert},
+	}
+	if c.clientCAs != nil {
+		config.ClientCAs = c.clientCAs
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return config, nil
+}
+
+// reload loads the certificate, key and client certificate authorities
+// again if any of their files changed since they were last loaded
+func (c *CertReloader) reload() error {
+	var modTimes [3]time.Time
+	for i, path := range []string{c.certPath, c.keyPath, c.clientCAPath} {
+		if path == "" {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		modTimes[i] = info.ModTime()
+	}
+
+	c.mutex.RLock()
+	unchanged := c.cert != nil && modTimes == c.modTimes
+	c.mutex.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(c.certPath, c.keyPath)
+	if err != nil {
+		return err
+	}
+	var clientCAs *x509.CertPool
+	if c.clientCAPath != "" {
+		pem, err := os.ReadFile(c.clientCAPath)
+		if err != nil {
+			return err
+		}
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in %s", c.clientCAPath)
+		}
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.cert = &cert
+	c.clientCAs = clientCAs
+	c.modTimes = modTimes
+	lgr.Info.Println("Loaded TLS certificate ", c.certPath)
+	return nil
+}
@@ -0,0 +1,182 @@
+package router_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	conf "github.com/SimplyVC/oasis_api_server/src/config"
+	lgr "github.com/SimplyVC/oasis_api_server/src/logger"
+	"github.com/SimplyVC/oasis_api_server/src/router"
+)
+
+func TestMain(m *testing.M) {
+
+	// Set Logger that will be used by API through all packages
+	lgr.SetLogger(os.Stdout, os.Stdout, os.Stderr)
+	os.Exit(m.Run())
+}
+
+// writeCertificate writes a self signed certificate for localhost with the
+// given serial number and its key, returning the certificate
+func writeCertificate(t *testing.T, certPath, keyPath string,
+	serial int64) *x509.Certificate {
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(serial),
+		Subject:               pkix.Name{CommonName: "localhost"},
+		DNSNames:              []string{"localhost"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth,
+			x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template,
+		&key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{
+		Type: "CERTIFICATE", Bytes: der}), 0o600)
+	os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{
+		Type: "EC PRIVATE KEY", Bytes: keyDer}), 0o600)
+
+	cert, _ := x509.ParseCertificate(der)
+	return cert
+}
+
+// serveTLS serves an empty response over TLS with the certificates of the
+// reloader, returning the address it listens on
+func serveTLS(t *testing.T, certs *router.CertReloader) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &http.Server{Handler: http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {})}
+	go server.Serve(tls.NewListener(listener, certs.TLSConfig()))
+	t.Cleanup(func() { server.Close() })
+	return listener.Addr().String()
+}
+
+func TestCertReloader_Success(t *testing.T) {
+	dir := t.TempDir()
+	certPath := filepath.Join(dir, "cert.pem")
+	keyPath := filepath.Join(dir, "key.pem")
+	first := writeCertificate(t, certPath, keyPath, 1)
+
+	certs, err := router.NewCertReloader(conf.APIServer{
+		TLSCertPath:   certPath,
+		TLSKeyPath:    keyPath,
+		TLSMinVersion: tls.VersionTLS12,
+	})
+	if err != nil {
+		t.Fatalf("Failed to load TLS certificate : %v", err)
+	}
+	address := serveTLS(t, certs)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(first)
+	conn, err := tls.Dial("tcp", address, &tls.Config{RootCAs: roots,
+		ServerName: "localhost"})
+	if err != nil {
+		t.Fatalf("Failed to connect over TLS : %v", err)
+	}
+	conn.Close()
+
+	// A rotated certificate is served without restarting
+	second := writeCertificate(t, certPath, keyPath, 2)
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(certPath, later, later)
+	os.Chtimes(keyPath, later, later)
+
+	roots.AddCert(second)
+	conn, err = tls.Dial("tcp", address, &tls.Config{RootCAs: roots,
+		ServerName: "localhost"})
+	if err != nil {
+		t.Fatalf("Failed to connect over TLS : %v", err)
+	}
+	defer conn.Close()
+	serial := conn.ConnectionState().PeerCertificates[0].SerialNumber
+	if serial.Int64() != 2 {
+		t.Errorf("Failed to serve rotated certificate, got serial %v",
+			serial)
+	}
+}
+
+func TestCertReloader_ClientAuth(t *testing.T) {
+	dir := t.TempDir()
+	certPath := filepath.Join(dir, "cert.pem")
+	keyPath := filepath.Join(dir, "key.pem")
+	clientCertPath := filepath.Join(dir, "client.pem")
+	clientKeyPath := filepath.Join(dir, "client_key.pem")
+	serverCert := writeCertificate(t, certPath, keyPath, 1)
+	writeCertificate(t, clientCertPath, clientKeyPath, 2)
+
+	certs, err := router.NewCertReloader(conf.APIServer{
+		TLSCertPath:     certPath,
+		TLSKeyPath:      keyPath,
+		TLSMinVersion:   tls.VersionTLS12,
+		TLSClientCAPath: clientCertPath,
+	})
+	if err != nil {
+		t.Fatalf("Failed to load TLS certificate : %v", err)
+	}
+	address := serveTLS(t, certs)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(serverCert)
+	clientCert, err := tls.LoadX509KeyPair(clientCertPath, clientKeyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	get := func(certificates []tls.Certificate) error {
+		client := &http.Client{Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: roots,
+				ServerName: "localhost", Certificates: certificates}}}
+		resp, err := client.Get("https://" + address + "/api/ping")
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+	if err := get([]tls.Certificate{clientCert}); err != nil {
+		t.Errorf("Failed to connect with client certificate : %v", err)
+	}
+	if err := get(nil); err == nil {
+		t.Errorf("Failed to refuse connection without client certificate")
+	}
+}
+
+func TestNewCertReloader_Failure(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := router.NewCertReloader(conf.APIServer{
+		TLSCertPath: filepath.Join(dir, "cert.pem"),
+		TLSKeyPath:  filepath.Join(dir, "key.pem"),
+	}); err == nil {
+		t.Errorf("Failed to reject missing certificate")
+	}
+}
@@ -0,0 +1,43 @@
+package rpc
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	lgr "github.com/SimplyVC/oasis_api_server/src/logger"
+)
+
+// requestIDOptions pass on the request ID of every call's context as gRPC
+// metadata, so that calls can be matched with the API request making them
+var requestIDOptions = []grpc.DialOption{
+	grpc.WithChainUnaryInterceptor(unaryRequestID),
+	grpc.WithChainStreamInterceptor(streamRequestID),
+}
+
+// withRequestID adds the request ID of a context to its outgoing metadata
+func withRequestID(ctx context.Context) context.Context {
+	if id := lgr.RequestID(ctx); id != "" {
+		return metadata.AppendToOutgoingContext(ctx,
+			strings.ToLower(lgr.RequestIDHeader), id)
+	}
+	return ctx
+}
+
+// unaryRequestID passes on the request ID of unary calls
+func unaryRequestID(ctx context.Context, method string, req,
+	reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker,
+	opts ...grpc.CallOption) error {
+
+	return invoker(withRequestID(ctx), method, req, reply, cc, opts...)
+}
+
+// streamRequestID passes on the request ID of streaming calls
+func streamRequestID(ctx context.Context, desc *grpc.StreamDesc,
+	cc *grpc.ClientConn, method string, streamer grpc.Streamer,
+	opts ...grpc.CallOption) (grpc.ClientStream, error) {
+
+	return streamer(withRequestID(ctx), desc, cc, method, opts...)
+}
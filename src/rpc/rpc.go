@@ -9,11 +9,16 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 
+	"github.com/SimplyVC/oasis_api_server/src/config"
+
 	cmnGrpc "github.com/oasisprotocol/oasis-core/go/common/grpc"
 	"github.com/oasisprotocol/oasis-core/go/common/identity"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
 	control "github.com/oasisprotocol/oasis-core/go/control/api"
+	governance "github.com/oasisprotocol/oasis-core/go/governance/api"
+	keymanager "github.com/oasisprotocol/oasis-core/go/keymanager/api"
 	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
 	scheduler "github.com/oasisprotocol/oasis-core/go/scheduler/api"
 	sentry "github.com/oasisprotocol/oasis-core/go/sentry/api"
 	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
@@ -25,7 +30,6 @@ func SentryClient(address string, tlsPath string) (*grpc.ClientConn,
 
 	conn, err := ConnectTLS(address, tlsPath)
 	if err != nil {
-		conn.Close()
 		return nil, nil, fmt.Errorf("Failed to establish Sentry "+
 			"Connection with node %s", address)
 	}
@@ -101,11 +105,69 @@ func StakingClient(address string) (*grpc.ClientConn, staking.Backend, error) {
 	return conn, client, nil
 }
 
+// GovernanceClient - initiate new governance client
+func GovernanceClient(address string) (*grpc.ClientConn,
+	governance.Backend, error) {
+
+	conn, err := Connect(address)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to establish Governance "+
+			"Client Connection with node %s", address)
+	}
+
+	client := governance.NewGovernanceClient(conn)
+	return conn, client, nil
+}
+
+// RootHashClient - initiate new roothash client
+func RootHashClient(address string) (*grpc.ClientConn,
+	roothash.Backend, error) {
+
+	conn, err := Connect(address)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to establish RootHash "+
+			"Client Connection with node %s", address)
+	}
+
+	client := roothash.NewRootHashClient(conn)
+	return conn, client, nil
+}
+
+// KeyManagerClient - initiate new key manager client
+func KeyManagerClient(address string) (*grpc.ClientConn,
+	*keymanager.KeymanagerClient, error) {
+
+	conn, err := Connect(address)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to establish KeyManager "+
+			"Client Connection with node %s", address)
+	}
+
+	client := keymanager.NewKeymanagerClient(conn)
+	return conn, client, nil
+}
+
+// NodeTLS configures a TLS connection to a node, the node certificate is
+// pinned and a client certificate is presented for mutual TLS when set
+type NodeTLS struct {
+	CertPath       string
+	ClientCertPath string
+	ClientKeyPath  string
+	ServerName     string
+}
+
 // ConnectTLS connects to server using TLS Certificate
 func ConnectTLS(address string, tlsPath string) (*grpc.ClientConn, error) {
+	return ConnectNodeTLS(address, NodeTLS{CertPath: tlsPath})
+}
+
+// ConnectNodeTLS connects to server trusting only its TLS certificate and
+// presenting a client certificate if one is configured
+func ConnectNodeTLS(address string, settings NodeTLS) (*grpc.ClientConn,
+	error) {
 
 	// Open and read tls file containing connection information
-	b, err := ioutil.ReadFile(tlsPath)
+	b, err := ioutil.ReadFile(settings.CertPath)
 	if err != nil {
 		return nil, err
 	}
@@ -117,17 +179,31 @@ func ConnectTLS(address string, tlsPath string) (*grpc.ClientConn, error) {
 			"certificates")
 	}
 
-	// Create new TLS credentials
-	creds := credentials.NewTLS(&tls.Config{
+	// Oasis nodes use self signed certificates with a fixed common name
+	tlsConfig := &tls.Config{
 		RootCAs:    certPool,
 		ServerName: identity.CommonName,
-	})
+		MinVersion: tls.VersionTLS12,
+	}
+	if settings.ServerName != "" {
+		tlsConfig.ServerName = settings.ServerName
+	}
+	if settings.ClientCertPath != "" {
+		cert, err := tls.LoadX509KeyPair(settings.ClientCertPath,
+			settings.ClientKeyPath)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
 
 	// Add Credentials to grpc options to be used for TLS Connection
-	opts := grpc.WithTransportCredentials(creds)
+	opts := append([]grpc.DialOption{grpc.WithTransportCredentials(
+		credentials.NewTLS(tlsConfig))}, requestIDOptions...)
+	opts = append(opts, tracingOptions...)
 	conn, err := cmnGrpc.Dial(
 		address,
-		opts,
+		opts...,
 	)
 	if err != nil {
 		return nil, err
@@ -136,13 +212,40 @@ func ConnectTLS(address string, tlsPath string) (*grpc.ClientConn, error) {
 	return conn, nil
 }
 
-// Connect - connect to grpc
+// nodeTLS returns the TLS settings of the node configured with an address,
+// if it has any
+func nodeTLS(address string) (NodeTLS, bool) {
+	cfg := config.Get()
+	if cfg == nil {
+		return NodeTLS{}, false
+	}
+	for _, node := range cfg.Nodes {
+		if node.ISocketPath == address && node.TLSCertPath != "" {
+			return NodeTLS{
+				CertPath:       node.TLSCertPath,
+				ClientCertPath: node.TLSClientCertPath,
+				ClientKeyPath:  node.TLSClientKeyPath,
+				ServerName:     node.TLSServerName,
+			}, true
+		}
+	}
+	return NodeTLS{}, false
+}
+
+// Connect - connect to grpc, over TLS when the node configured with the
+// address has a TLS certificate
 // Add grpc.WithBlock() and grpc.WithTimeout()
 // to have dial to constantly try and establish connection
 func Connect(address string) (*grpc.ClientConn, error) {
+	if settings, ok := nodeTLS(address); ok {
+		return ConnectNodeTLS(address, settings)
+	}
+
 	opts := []grpc.DialOption{grpc.WithInsecure()}
 	opts = append(opts, grpc.WithDefaultCallOptions(
 		grpc.WaitForReady(false)))
+	opts = append(opts, requestIDOptions...)
+	opts = append(opts, tracingOptions...)
 
 	conn, err := cmnGrpc.Dial(
 		address,
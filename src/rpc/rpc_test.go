@@ -2,10 +2,31 @@ package rpc_test
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"fmt"
+	"math/big"
+	"net"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/oasisprotocol/oasis-core/go/common/identity"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+
+	lgr "github.com/SimplyVC/oasis_api_server/src/logger"
 	"github.com/SimplyVC/oasis_api_server/src/rpc"
 )
 
@@ -79,6 +100,33 @@ func TestConsensusClient_Success(t *testing.T) {
 	}
 }
 
+// Testing if Governance Client Connects
+func TestGovernanceClient_Success(t *testing.T) {
+	_, _, err := rpc.GovernanceClient(isocket_path)
+	if err != nil {
+		t.Errorf("Failed to create GovernanceClient for socket %v got %v",
+			isocket_path, err)
+	}
+}
+
+// Testing if RootHash Client Connects
+func TestRootHashClient_Success(t *testing.T) {
+	_, _, err := rpc.RootHashClient(isocket_path)
+	if err != nil {
+		t.Errorf("Failed to create RootHashClient for socket %v got %v",
+			isocket_path, err)
+	}
+}
+
+// Testing if KeyManager Client Connects
+func TestKeyManagerClient_Success(t *testing.T) {
+	_, _, err := rpc.KeyManagerClient(isocket_path)
+	if err != nil {
+		t.Errorf("Failed to create KeyManagerClient for socket %v got %v",
+			isocket_path, err)
+	}
+}
+
 // Testing connection function
 func TestConnect_Success(t *testing.T) {
 	_, err := rpc.Connect(isocket_path)
@@ -87,3 +135,178 @@ func TestConnect_Success(t *testing.T) {
 			isocket_path, err)
 	}
 }
+
+// writeCertificate writes a self signed certificate and its key for the
+// given name into dir, returning their paths
+func writeCertificate(t *testing.T, dir, name string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth,
+			x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template,
+		&key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certPath := filepath.Join(dir, name+".pem")
+	keyPath := filepath.Join(dir, name+"_key.pem")
+	os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{
+		Type: "CERTIFICATE", Bytes: der}), 0o600)
+	os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{
+		Type: "EC PRIVATE KEY", Bytes: keyDer}), 0o600)
+	return certPath, keyPath
+}
+
+// Testing if a node requiring a client certificate can be reached with
+// mutual TLS, and can't be reached without the client certificate
+func TestConnectNodeTLS_Success(t *testing.T) {
+	dir := t.TempDir()
+	serverCert, serverKey := writeCertificate(t, dir, identity.CommonName)
+	clientCert, clientKey := writeCertificate(t, dir, "client")
+
+	cert, err := tls.LoadX509KeyPair(serverCert, serverKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientPEM, _ := os.ReadFile(clientCert)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AppendCertsFromPEM(clientPEM)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer(grpc.Creds(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    clientCAs,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	})))
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go server.Serve(listener)
+	defer server.Stop()
+
+	address := listener.Addr().String()
+	conn, err := rpc.ConnectNodeTLS(address, rpc.NodeTLS{
+		CertPath:       serverCert,
+		ClientCertPath: clientCert,
+		ClientKeyPath:  clientKey,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create connection for address %v got %v",
+			address, err)
+	}
+	defer conn.Close()
+
+	checkCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if _, err := healthpb.NewHealthClient(conn).Check(checkCtx,
+		&healthpb.HealthCheckRequest{}); err != nil {
+		t.Errorf("Failed to call node over mutual TLS got %v", err)
+	}
+
+	conn, err = rpc.ConnectNodeTLS(address, rpc.NodeTLS{
+		CertPath: serverCert})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if _, err := healthpb.NewHealthClient(conn).Check(checkCtx,
+		&healthpb.HealthCheckRequest{}); err == nil {
+		t.Errorf("Failed to refuse call without client certificate")
+	}
+}
+
+func TestConnect_RequestID(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	received := make(chan []string, 1)
+	server := grpc.NewServer(grpc.UnaryInterceptor(func(
+		ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+
+		md, _ := metadata.FromIncomingContext(ctx)
+		received <- md.Get("x-request-id")
+		return handler(ctx, req)
+	}))
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go server.Serve(listener)
+	defer server.Stop()
+
+	conn, err := rpc.Connect(listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	checkCtx, cancel := context.WithTimeout(
+		lgr.WithRequestID(ctx, "4f1c2a9e"), 5*time.Second)
+	defer cancel()
+	if _, err := healthpb.NewHealthClient(conn).Check(checkCtx,
+		&healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatalf("Failed to call node got %v", err)
+	}
+	if ids := <-received; len(ids) != 1 || ids[0] != "4f1c2a9e" {
+		t.Errorf("Unexpected request ID metadata %v", ids)
+	}
+}
+
+func TestConnect_TraceContext(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	received := make(chan []string, 1)
+	server := grpc.NewServer(grpc.UnaryInterceptor(func(
+		ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+
+		md, _ := metadata.FromIncomingContext(ctx)
+		received <- md.Get("traceparent")
+		return handler(ctx, req)
+	}))
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go server.Serve(listener)
+	defer server.Stop()
+
+	conn, err := rpc.Connect(listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// Calls made while serving a traced request carry on its trace
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	traced := trace.ContextWithSpanContext(ctx, trace.NewSpanContext(
+		trace.SpanContextConfig{TraceID: traceID, SpanID: spanID,
+			TraceFlags: trace.FlagsSampled}))
+
+	checkCtx, cancel := context.WithTimeout(traced, 5*time.Second)
+	defer cancel()
+	if _, err := healthpb.NewHealthClient(conn).Check(checkCtx,
+		&healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatalf("Failed to call node got %v", err)
+	}
+	parents := <-received
+	if len(parents) != 1 || !strings.HasPrefix(parents[0],
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-") {
+		t.Errorf("Unexpected traceparent metadata %v", parents)
+	}
+}
@@ -0,0 +1,17 @@
+package rpc
+
+import (
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+
+	"github.com/SimplyVC/oasis_api_server/src/tracing"
+)
+
+// tracingOptions record a span for every call made to a node and pass on
+// its trace context as gRPC metadata, so that the node can continue the
+// trace of the API request making the call
+var tracingOptions = []grpc.DialOption{
+	grpc.WithStatsHandler(otelgrpc.NewClientHandler(
+		otelgrpc.WithTracerProvider(tracing.Provider),
+		otelgrpc.WithPropagators(tracing.Propagator))),
+}
@@ -0,0 +1,302 @@
+package sampler
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/SimplyVC/oasis_api_server/src/config"
+	"github.com/SimplyVC/oasis_api_server/src/journal"
+	lgr "github.com/SimplyVC/oasis_api_server/src/logger"
+	"github.com/SimplyVC/oasis_api_server/src/rpc"
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+// Modes in which samples can be taken
+const (
+	ModeBlock = "block"
+	ModeEpoch = "epoch"
+)
+
+// Default settings used when the sampler section leaves them empty
+const (
+	defaultPollInterval = 10 * time.Second
+	defaultStorageDir   = "../data/samples"
+	defaultMaxSamples   = 100000
+)
+
+var (
+	mutex  sync.RWMutex
+	stores = map[string]*Store{}
+	poller journal.Poller
+)
+
+// Settings configure the sampler, they are read from the sampler section of
+// the main configuration
+type Settings struct {
+	Enabled      bool
+	Mode         string
+	PollInterval time.Duration
+	StorageDir   string
+	MaxSamples   int
+}
+
+// LoadSettings parses the sampler section of the main configuration
+func LoadSettings(mainConf map[string]map[string]string) (*Settings, error) {
+	section := mainConf["sampler"]
+	settings := &Settings{
+		Mode:         ModeBlock,
+		PollInterval: defaultPollInterval,
+		StorageDir:   defaultStorageDir,
+		MaxSamples:   defaultMaxSamples,
+	}
+
+	if enabled := section["enabled"]; enabled != "" {
+		value, err := strconv.ParseBool(enabled)
+		if err != nil {
+			return nil, fmt.Errorf("sampler enabled needs to be true or "+
+				"false, got %s", enabled)
+		}
+		settings.Enabled = value
+	}
+	if mode := strings.ToLower(section["mode"]); mode != "" {
+		if mode != ModeBlock && mode != ModeEpoch {
+			return nil, fmt.Errorf("sampler mode needs to be %s or %s, "+
+				"got %s", ModeBlock, ModeEpoch, mode)
+		}
+		settings.Mode = mode
+	}
+	if interval := section["poll_interval"]; interval != "" {
+		seconds, err := strconv.ParseUint(interval, 10, 32)
+		if err != nil || seconds == 0 {
+			return nil, fmt.Errorf("sampler poll_interval needs to be a "+
+				"positive number of seconds, got %s", interval)
+		}
+		settings.PollInterval = time.Duration(seconds) * time.Second
+	}
+	if dir := section["storage_dir"]; dir != "" {
+		settings.StorageDir = dir
+	}
+	if max := section["max_samples"]; max != "" {
+		samples, err := strconv.ParseUint(max, 10, 31)
+		if err != nil {
+			return nil, fmt.Errorf("sampler max_samples needs to be a "+
+				"number of samples, got %s", max)
+		}
+		settings.MaxSamples = int(samples)
+	}
+	return settings, nil
+}
+
+// GetStore returns the sample store of a node if it is being sampled
+func GetStore(nodeName string) (*Store, bool) {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	store, ok := stores[nodeName]
+	return store, ok
+}
+
+// Start opens a store for every configured node and starts sampling them in
+// the background until Stop is called
+func Start(settings *Settings) error {
+	if err := os.MkdirAll(settings.StorageDir, 0700); err != nil {
+		return err
+	}
+
+	cfg := config.Get()
+	if cfg == nil {
+		return fmt.Errorf("sampler needs the configuration to be loaded")
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	for _, node := range cfg.Nodes {
+		name := node.Name
+		path := filepath.Join(settings.StorageDir,
+			filepath.Base(name)+".jsonl")
+		store, err := OpenStore(path, settings.MaxSamples)
+		if err != nil {
+			lgr.Error.Printf("Failed to open sample store of node %s : %s",
+				name, err)
+			continue
+		}
+		stores[name] = store
+
+		lgr.Info.Printf("Sampling node %s per %s into %s", name,
+			settings.Mode, path)
+		socket := node.ISocketPath
+		poller.Go(func(ctx context.Context) {
+			run(ctx, settings, name, socket, store)
+		})
+	}
+	return nil
+}
+
+// Stop stops sampling, waits for in progress samples to be written and
+// closes all stores
+func Stop() {
+	poller.Stop()
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	for name, store := range stores {
+		if err := store.Close(); err != nil {
+			lgr.Error.Printf("Failed to close sample store of node %s : %s",
+				name, err)
+		}
+		delete(stores, name)
+	}
+}
+
+// nodeClients holds the clients used to sample a node
+type nodeClients struct {
+	consensusConn *grpc.ClientConn
+	stakingConn   *grpc.ClientConn
+	consensus     consensus.ClientBackend
+	staking       staking.Backend
+}
+
+// close closes the connections of the clients
+func (c *nodeClients) close() {
+	c.consensusConn.Close()
+	c.stakingConn.Close()
+}
+
+// run samples a node every poll interval until ctx is cancelled
+func run(ctx context.Context, settings *Settings, name string, socket string,
+	store *Store) {
+
+	consensusConn, co, err := rpc.ConsensusClient(socket)
+	if err != nil {
+		lgr.Error.Printf("Sampler failed to connect to node %s : %s", name,
+			err)
+		return
+	}
+	stakingConn, so, err := rpc.StakingClient(socket)
+	if err != nil {
+		consensusConn.Close()
+		lgr.Error.Printf("Sampler failed to connect to node %s : %s", name,
+			err)
+		return
+	}
+	clients := &nodeClients{
+		consensusConn: consensusConn,
+		stakingConn:   stakingConn,
+		consensus:     co,
+		staking:       so,
+	}
+	defer clients.close()
+
+	journal.Every(ctx, settings.PollInterval,
+		"Sampler failed to sample node "+name,
+		func(ctx context.Context) error {
+			return poll(ctx, settings.Mode, clients, store)
+		})
+}
+
+// poll samples the heights that were reached since the last sample
+func poll(ctx context.Context, mode string, clients *nodeClients,
+	store *Store) error {
+
+	status, err := clients.consensus.GetStatus(ctx)
+	if err != nil {
+		return err
+	}
+
+	heights, err := pendingHeights(ctx, mode, clients, store,
+		status.LatestHeight)
+	if err != nil {
+		return err
+	}
+
+	for _, height := range heights {
+		if ctx.Err() != nil {
+			return nil
+		}
+		sample, err := takeSample(ctx, clients, height)
+		if err != nil {
+			return fmt.Errorf("height %d : %w", height, err)
+		}
+		if err := store.Append(*sample); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pendingHeights lists the heights to sample, a new store starts at the
+// latest height rather than sampling the whole chain
+func pendingHeights(ctx context.Context, mode string, clients *nodeClients,
+	store *Store, latest int64) ([]int64, error) {
+
+	last, _ := store.Last()
+	heights := []int64{}
+
+	if mode == ModeBlock {
+		first, end := journal.Heights(last.Height, latest, 0)
+		for height := first; height <= end; height++ {
+			heights = append(heights, height)
+		}
+		return heights, nil
+	}
+
+	current, err := clients.consensus.Beacon().GetEpoch(ctx, latest)
+	if err != nil {
+		return nil, err
+	}
+	first, end := journal.Heights(int64(last.Epoch), int64(current), 0)
+	for epoch := first; epoch <= end; epoch++ {
+		height, err := clients.consensus.Beacon().GetEpochBlock(ctx,
+			beacon.EpochTime(epoch))
+		if err != nil {
+			return nil, err
+		}
+		heights = append(heights, height)
+	}
+	return heights, nil
+}
+
+// takeSample retrieves the staking supply figures at height
+func takeSample(ctx context.Context, clients *nodeClients,
+	height int64) (*Sample, error) {
+
+	block, err := clients.consensus.GetBlock(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	var epoch beacon.EpochTime
+	if epoch, err = clients.consensus.Beacon().GetEpoch(ctx, height); err != nil {
+		return nil, err
+	}
+	totalSupply, err := clients.staking.TotalSupply(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	commonPool, err := clients.staking.CommonPool(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	lastBlockFees, err := clients.staking.LastBlockFees(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Sample{
+		Height:        block.Height,
+		Time:          block.Time,
+		Epoch:         epoch,
+		TotalSupply:   *totalSupply,
+		CommonPool:    *commonPool,
+		LastBlockFees: *lastBlockFees,
+	}, nil
+}
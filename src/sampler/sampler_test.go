@@ -0,0 +1,180 @@
+package sampler_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	lgr "github.com/SimplyVC/oasis_api_server/src/logger"
+	"github.com/SimplyVC/oasis_api_server/src/sampler"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+)
+
+func TestMain(m *testing.M) {
+	lgr.SetLogger(os.Stdout, os.Stdout, os.Stderr)
+	os.Exit(m.Run())
+}
+
+// newSample creates a sample at height with fees equal to the height
+func newSample(height int64) sampler.Sample {
+	return sampler.Sample{
+		Height:        height,
+		Time:          time.Unix(height*6, 0).UTC(),
+		TotalSupply:   *quantity.NewFromUint64(uint64(1000 + height)),
+		CommonPool:    *quantity.NewFromUint64(uint64(500 - height)),
+		LastBlockFees: *quantity.NewFromUint64(uint64(height)),
+	}
+}
+
+func TestStore_AppendAndReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "node.jsonl")
+	store, err := sampler.OpenStore(path, 0)
+	if err != nil {
+		t.Fatalf("Failed to open store : %v", err)
+	}
+	for height := int64(1); height <= 5; height++ {
+		if err := store.Append(newSample(height)); err != nil {
+			t.Fatalf("Failed to append sample : %v", err)
+		}
+	}
+
+	// Samples at or below the latest height are ignored
+	if err := store.Append(newSample(3)); err != nil {
+		t.Fatalf("Failed to append sample : %v", err)
+	}
+	store.Close()
+
+	reopened, err := sampler.OpenStore(path, 0)
+	if err != nil {
+		t.Fatalf("Failed to reopen store : %v", err)
+	}
+	defer reopened.Close()
+
+	last, ok := reopened.Last()
+	if !ok || last.Height != 5 {
+		t.Errorf("Last sample got height %v want 5", last.Height)
+	}
+	if samples := reopened.Range(sampler.Query{}); len(samples) != 5 {
+		t.Errorf("Reopened store got %v samples want 5", len(samples))
+	}
+}
+
+func TestStore_MaxSamples(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "node.jsonl")
+	store, err := sampler.OpenStore(path, 3)
+	if err != nil {
+		t.Fatalf("Failed to open store : %v", err)
+	}
+	for height := int64(1); height <= 10; height++ {
+		store.Append(newSample(height))
+	}
+
+	samples := store.Range(sampler.Query{})
+	if len(samples) != 3 || samples[0].Height != 8 {
+		t.Errorf("Limited store returned unexpected samples %v", samples)
+	}
+	store.Close()
+
+	// The file keeps every sample
+	reopened, err := sampler.OpenStore(path, 0)
+	if err != nil {
+		t.Fatalf("Failed to reopen store : %v", err)
+	}
+	defer reopened.Close()
+	if samples := reopened.Range(sampler.Query{}); len(samples) != 10 {
+		t.Errorf("Reopened store got %v samples want 10", len(samples))
+	}
+}
+
+func TestStore_Range(t *testing.T) {
+	store, err := sampler.OpenStore(filepath.Join(t.TempDir(), "node.jsonl"),
+		0)
+	if err != nil {
+		t.Fatalf("Failed to open store : %v", err)
+	}
+	defer store.Close()
+	for height := int64(1); height <= 10; height++ {
+		store.Append(newSample(height))
+	}
+
+	samples := store.Range(sampler.Query{FromHeight: 3, ToHeight: 6})
+	if len(samples) != 4 || samples[0].Height != 3 || samples[3].Height != 6 {
+		t.Errorf("Height range returned unexpected samples %v", samples)
+	}
+
+	samples = store.Range(sampler.Query{
+		FromTime: time.Unix(48, 0),
+		ToTime:   time.Unix(54, 0),
+	})
+	if len(samples) != 2 || samples[0].Height != 8 || samples[1].Height != 9 {
+		t.Errorf("Time range returned unexpected samples %v", samples)
+	}
+}
+
+func TestDownsample(t *testing.T) {
+	samples := []sampler.Sample{}
+	for height := int64(1); height <= 10; height++ {
+		samples = append(samples, newSample(height))
+	}
+
+	if got := sampler.Downsample(samples, 0); len(got) != 10 {
+		t.Errorf("Downsample without limit got %v points want 10", len(got))
+	}
+
+	points := sampler.Downsample(samples, 3)
+	if len(points) != 3 {
+		t.Fatalf("Downsample got %v points want 3", len(points))
+	}
+
+	// Buckets are heights 1-3, 4-6 and 7-10
+	wantHeights := []int64{3, 6, 10}
+	wantFees := []uint64{6, 15, 34}
+	for i, point := range points {
+		if point.Height != wantHeights[i] {
+			t.Errorf("Point %d got height %v want %v", i, point.Height,
+				wantHeights[i])
+		}
+		if point.LastBlockFees.Cmp(quantity.NewFromUint64(wantFees[i])) != 0 {
+			t.Errorf("Point %d got fees %v want %v", i,
+				point.LastBlockFees, wantFees[i])
+		}
+	}
+}
+
+func TestLoadSettings(t *testing.T) {
+	settings, err := sampler.LoadSettings(map[string]map[string]string{})
+	if err != nil || settings.Enabled || settings.Mode != sampler.ModeBlock {
+		t.Errorf("Default settings unexpected %v %v", settings, err)
+	}
+
+	settings, err = sampler.LoadSettings(map[string]map[string]string{
+		"sampler": {"enabled": "true", "mode": "Epoch",
+			"poll_interval": "30"},
+	})
+	if err != nil || !settings.Enabled || settings.Mode != sampler.ModeEpoch ||
+		settings.PollInterval != 30*time.Second {
+		t.Errorf("Settings unexpected %v %v", settings, err)
+	}
+
+	_, err = sampler.LoadSettings(map[string]map[string]string{
+		"sampler": {"mode": "hourly"},
+	})
+	if err == nil {
+		t.Errorf("Expected invalid mode to fail")
+	}
+
+	settings, err = sampler.LoadSettings(map[string]map[string]string{
+		"sampler": {"max_samples": "0"},
+	})
+	if err != nil || settings.MaxSamples != 0 {
+		t.Errorf("Settings unexpected %v %v", settings, err)
+	}
+
+	_, err = sampler.LoadSettings(map[string]map[string]string{
+		"sampler": {"max_samples": "-1"},
+	})
+	if err == nil {
+		t.Errorf("Expected negative max_samples to fail")
+	}
+}
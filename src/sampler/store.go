@@ -0,0 +1,110 @@
+package sampler
+
+import (
+	"time"
+
+	"github.com/SimplyVC/oasis_api_server/src/journal"
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+)
+
+// Sample holds the staking supply figures of a single block
+type Sample struct {
+	Height        int64             `json:"height"`
+	Time          time.Time         `json:"time"`
+	Epoch         beacon.EpochTime  `json:"epoch"`
+	TotalSupply   quantity.Quantity `json:"total_supply"`
+	CommonPool    quantity.Quantity `json:"common_pool"`
+	LastBlockFees quantity.Quantity `json:"last_block_fees"`
+}
+
+// Query selects samples by height and time, zero values leave a bound open
+type Query struct {
+	FromHeight int64
+	ToHeight   int64
+	FromTime   time.Time
+	ToTime     time.Time
+}
+
+// matches checks whether sample falls within the query bounds
+func (q *Query) matches(sample *Sample) bool {
+	if q.FromHeight != 0 && sample.Height < q.FromHeight {
+		return false
+	}
+	if q.ToHeight != 0 && sample.Height > q.ToHeight {
+		return false
+	}
+	if !q.FromTime.IsZero() && sample.Time.Before(q.FromTime) {
+		return false
+	}
+	if !q.ToTime.IsZero() && sample.Time.After(q.ToTime) {
+		return false
+	}
+	return true
+}
+
+// Store keeps the samples of a node ordered by height in a JSON lines file so
+// that they survive restarts. Only the latest samples are kept in memory and
+// served when the store is limited.
+type Store struct {
+	journal *journal.Store[Sample]
+}
+
+// OpenStore loads the latest maxSamples samples found in path, or all of
+// them if maxSamples is zero, and opens it for appending, creating it if it
+// doesn't exist
+func OpenStore(path string, maxSamples int) (*Store, error) {
+	opened, err := journal.Open(path, func(sample *Sample) int64 {
+		return sample.Height
+	}, maxSamples)
+	if err != nil {
+		return nil, err
+	}
+	return &Store{journal: opened}, nil
+}
+
+// Append records a sample, samples at or below the latest height are ignored
+func (s *Store) Append(sample Sample) error {
+	return s.journal.Commit(sample.Height, []Sample{sample}, nil)
+}
+
+// Last returns the sample with the highest height
+func (s *Store) Last() (Sample, bool) {
+	return s.journal.Last()
+}
+
+// Range returns a copy of the samples matching query in height order
+func (s *Store) Range(query Query) []Sample {
+	return s.journal.Range(query.matches)
+}
+
+// Close closes the file backing the store
+func (s *Store) Close() error {
+	return s.journal.Close()
+}
+
+// Downsample reduces samples to at most maxPoints by splitting them into
+// consecutive buckets of equal size. Each bucket is represented by its last
+// sample, since supply and common pool are balances, with the fees of all
+// blocks in the bucket added up so that no fees are lost.
+func Downsample(samples []Sample, maxPoints int) []Sample {
+	if maxPoints <= 0 || len(samples) <= maxPoints {
+		return samples
+	}
+
+	result := make([]Sample, 0, maxPoints)
+	for bucket := 0; bucket < maxPoints; bucket++ {
+		start := bucket * len(samples) / maxPoints
+		end := (bucket + 1) * len(samples) / maxPoints
+
+		point := samples[end-1]
+		fees := quantity.NewQuantity()
+		for i := start; i < end; i++ {
+			// Adding two valid quantities can't fail
+			_ = fees.Add(&samples[i].LastBlockFees)
+		}
+		point.LastBlockFees = *fees
+		result = append(result, point)
+	}
+	return result
+}
@@ -0,0 +1,91 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/embedded"
+
+	lgr "github.com/SimplyVC/oasis_api_server/src/logger"
+)
+
+// instrumentationName names the tracer of the spans started by the API
+const instrumentationName = "github.com/SimplyVC/oasis_api_server/src"
+
+// Provider hands out tracers that start spans through the tracer provider
+// set by the latest call to Configure, so that instrumentation created once
+// keeps working when tracing is configured again
+var Provider trace.TracerProvider = &provider{}
+
+// provider starts spans through the current tracer provider
+type provider struct {
+	embedded.TracerProvider
+}
+
+// Tracer returns a tracer starting spans through the current provider
+func (p *provider) Tracer(name string,
+	options ...trace.TracerOption) trace.Tracer {
+
+	return &tracer{name: name, options: options}
+}
+
+// tracer starts spans through the current provider
+type tracer struct {
+	embedded.Tracer
+	name    string
+	options []trace.TracerOption
+}
+
+// Start starts a span through the tracer of the current provider
+func (t *tracer) Start(ctx context.Context, name string,
+	options ...trace.SpanStartOption) (context.Context, trace.Span) {
+
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return current.Tracer(t.name, t.options...).Start(ctx, name,
+		options...)
+}
+
+// Start starts a span as a child of the span of ctx
+func Start(ctx context.Context, name string,
+	attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+
+	return Provider.Tracer(instrumentationName).Start(ctx, name,
+		trace.WithAttributes(attrs...))
+}
+
+// End ends a span, marking it as failed when err isn't nil
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Handler records a span for every request served by next, continuing the
+// trace of the caller if its request carries a trace context
+func Handler(next http.Handler) http.Handler {
+	withRequestID := http.HandlerFunc(func(w http.ResponseWriter,
+		r *http.Request) {
+
+		if id := lgr.RequestID(r.Context()); id != "" {
+			trace.SpanFromContext(r.Context()).SetAttributes(
+				attribute.String("request_id", id))
+		}
+		next.ServeHTTP(w, r)
+	})
+
+	return otelhttp.NewHandler(withRequestID, "http.request",
+		otelhttp.WithTracerProvider(Provider),
+		otelhttp.WithPropagators(Propagator),
+		otelhttp.WithSpanNameFormatter(func(_ string,
+			r *http.Request) string {
+
+			return r.Method + " " + r.URL.Path
+		}))
+}
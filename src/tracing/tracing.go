@@ -0,0 +1,184 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	lgr "github.com/SimplyVC/oasis_api_server/src/logger"
+)
+
+// Exporters the spans can be sent through
+const (
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+)
+
+// Default settings used when the tracing section leaves them empty
+const (
+	defaultServiceName = "oasis_api_server"
+
+	// shutdownTimeout bounds how long spans are flushed for when the
+	// tracer provider is replaced or closed
+	shutdownTimeout = 5 * time.Second
+)
+
+// Propagator reads and writes the W3C trace context and baggage of requests
+// and of the calls made to the nodes
+var Propagator propagation.TextMapPropagator = propagation.
+	NewCompositeTextMapPropagator(propagation.TraceContext{},
+		propagation.Baggage{})
+
+var (
+	mutex    sync.RWMutex
+	current  trace.TracerProvider = noop.NewTracerProvider()
+	shutdown func(context.Context) error
+)
+
+// Settings configure tracing, they are read from the tracing section of the
+// main configuration
+type Settings struct {
+	Enabled     bool
+	Exporter    string
+	ServiceName string
+
+	// Endpoint is the host:port of the OTLP collector, the exporter falls
+	// back to the OTEL_EXPORTER_OTLP_* environment variables and then to
+	// localhost:4317 when it is empty
+	Endpoint string
+	Insecure bool
+
+	// SampleRate is the share of traces started by the API that are
+	// recorded, traces continued from a caller follow its decision
+	SampleRate float64
+}
+
+// LoadSettings parses the tracing section of the main configuration
+func LoadSettings(mainConf map[string]map[string]string) (*Settings, error) {
+	section := mainConf["tracing"]
+	settings := &Settings{
+		Exporter:    ExporterOTLP,
+		ServiceName: defaultServiceName,
+		Endpoint:    section["endpoint"],
+		SampleRate:  1,
+	}
+
+	if enabled := section["enabled"]; enabled != "" {
+		value, err := strconv.ParseBool(enabled)
+		if err != nil {
+			return nil, fmt.Errorf("tracing enabled needs to be true or "+
+				"false, got %s", enabled)
+		}
+		settings.Enabled = value
+	}
+	if exporter := strings.ToLower(section["exporter"]); exporter != "" {
+		if exporter != ExporterOTLP && exporter != ExporterStdout {
+			return nil, fmt.Errorf("tracing exporter needs to be %s or %s, "+
+				"got %s", ExporterOTLP, ExporterStdout, exporter)
+		}
+		settings.Exporter = exporter
+	}
+	if name := section["service_name"]; name != "" {
+		settings.ServiceName = name
+	}
+	if insecure := section["insecure"]; insecure != "" {
+		value, err := strconv.ParseBool(insecure)
+		if err != nil {
+			return nil, fmt.Errorf("tracing insecure needs to be true or "+
+				"false, got %s", insecure)
+		}
+		settings.Insecure = value
+	}
+	if rate := section["sample_rate"]; rate != "" {
+		value, err := strconv.ParseFloat(rate, 64)
+		if err != nil || value < 0 || value > 1 {
+			return nil, fmt.Errorf("tracing sample_rate needs to be a "+
+				"number between 0 and 1, got %s", rate)
+		}
+		settings.SampleRate = value
+	}
+	return settings, nil
+}
+
+// Configure sets how spans are recorded and exported, spans of the previous
+// configuration are flushed. Nothing is recorded when tracing is disabled.
+func Configure(settings *Settings) error {
+	if !settings.Enabled {
+		swap(noop.NewTracerProvider(), nil)
+		return nil
+	}
+
+	var option sdktrace.TracerProviderOption
+	switch settings.Exporter {
+	case ExporterStdout:
+		// Spans are written as they end so that they can be followed
+		// while testing locally
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout),
+			stdouttrace.WithPrettyPrint())
+		if err != nil {
+			return err
+		}
+		option = sdktrace.WithSyncer(exporter)
+	default:
+		var options []otlptracegrpc.Option
+		if settings.Endpoint != "" {
+			options = append(options,
+				otlptracegrpc.WithEndpoint(settings.Endpoint))
+		}
+		if settings.Insecure {
+			options = append(options, otlptracegrpc.WithInsecure())
+		}
+		exporter, err := otlptracegrpc.New(context.Background(),
+			options...)
+		if err != nil {
+			return err
+		}
+		option = sdktrace.WithBatcher(exporter)
+	}
+
+	provider := sdktrace.NewTracerProvider(option,
+		sdktrace.WithSampler(sdktrace.ParentBased(
+			sdktrace.TraceIDRatioBased(settings.SampleRate))),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL,
+			semconv.ServiceName(settings.ServiceName))))
+	swap(provider, provider.Shutdown)
+	return nil
+}
+
+// Close flushes the recorded spans and stops recording new ones
+func Close() {
+	swap(noop.NewTracerProvider(), nil)
+}
+
+// swap replaces the current tracer provider and shuts the previous one
+// down, flushing its spans
+func swap(provider trace.TracerProvider,
+	providerShutdown func(context.Context) error) {
+
+	mutex.Lock()
+	previous := shutdown
+	current, shutdown = provider, providerShutdown
+	mutex.Unlock()
+
+	if previous != nil {
+		ctx, cancel := context.WithTimeout(context.Background(),
+			shutdownTimeout)
+		defer cancel()
+		if err := previous(ctx); err != nil {
+			lgr.Error.Println("Flushing of spans has failed : ", err)
+		}
+	}
+}
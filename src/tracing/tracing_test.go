@@ -0,0 +1,177 @@
+package tracing_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	lgr "github.com/SimplyVC/oasis_api_server/src/logger"
+	"github.com/SimplyVC/oasis_api_server/src/tracing"
+)
+
+// span holds the fields of a span written by the stdout exporter that the
+// tests check
+type span struct {
+	Name        string
+	SpanContext struct {
+		TraceID string
+		SpanID  string
+	}
+	Parent struct {
+		TraceID string
+		SpanID  string
+	}
+	Attributes []struct {
+		Key   string
+		Value struct {
+			Value interface{}
+		}
+	}
+}
+
+// attribute returns the value of an attribute of the span
+func (s span) attribute(key string) interface{} {
+	for _, attr := range s.Attributes {
+		if attr.Key == key {
+			return attr.Value.Value
+		}
+	}
+	return nil
+}
+
+// serve configures tracing with the stdout exporter, serves a request and
+// returns the spans written by name
+func serve(t *testing.T, section map[string]string,
+	request *http.Request) map[string]span {
+
+	path := filepath.Join(t.TempDir(), "spans.json")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	// The stdout exporter writes to the standard output it is created with
+	stdout := os.Stdout
+	os.Stdout = file
+	section["exporter"] = tracing.ExporterStdout
+	settings, err := tracing.LoadSettings(
+		map[string]map[string]string{"tracing": section})
+	if err == nil {
+		err = tracing.Configure(settings)
+	}
+	os.Stdout = stdout
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := tracing.Handler(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			_, connect := tracing.Start(r.Context(), "connect")
+			tracing.End(connect, nil)
+			w.Write([]byte(`{"result":"pong"}`))
+		}))
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+	tracing.Close()
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	spans := map[string]span{}
+	decoder := json.NewDecoder(file)
+	for decoder.More() {
+		var s span
+		if err := decoder.Decode(&s); err != nil {
+			t.Fatal(err)
+		}
+		spans[s.Name] = s
+	}
+	return spans
+}
+
+func TestLoadSettings_Success(t *testing.T) {
+	settings, err := tracing.LoadSettings(map[string]map[string]string{
+		"tracing": {"enabled": "true", "exporter": "OTLP",
+			"endpoint": "collector:4317", "insecure": "true",
+			"sample_rate": "0.25"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !settings.Enabled || settings.Exporter != tracing.ExporterOTLP ||
+		settings.Endpoint != "collector:4317" || !settings.Insecure ||
+		settings.SampleRate != 0.25 ||
+		settings.ServiceName != "oasis_api_server" {
+		t.Errorf("Unexpected settings %+v", settings)
+	}
+
+	// Tracing is disabled unless enabled in the configuration
+	settings, err = tracing.LoadSettings(map[string]map[string]string{})
+	if err != nil || settings.Enabled || settings.SampleRate != 1 {
+		t.Errorf("Unexpected default settings %+v, %v", settings, err)
+	}
+}
+
+func TestLoadSettings_Failure(t *testing.T) {
+	for _, section := range []map[string]string{
+		{"enabled": "sometimes"},
+		{"exporter": "jaeger"},
+		{"insecure": "maybe"},
+		{"sample_rate": "1.5"},
+		{"sample_rate": "all"},
+	} {
+		if _, err := tracing.LoadSettings(map[string]map[string]string{
+			"tracing": section}); err == nil {
+			t.Errorf("Failed to reject tracing section %v", section)
+		}
+	}
+}
+
+func TestHandler_Spans(t *testing.T) {
+	request := httptest.NewRequest(http.MethodGet, "/api/ping", nil)
+	request = request.WithContext(lgr.WithRequestID(context.Background(),
+		"4f1c2a9e"))
+
+	spans := serve(t, map[string]string{"enabled": "true"}, request)
+	handler, ok := spans["GET /api/ping"]
+	if !ok {
+		t.Fatalf("Failed to record span of request, got %v", spans)
+	}
+	if id := handler.attribute("request_id"); id != "4f1c2a9e" {
+		t.Errorf("Unexpected request_id attribute %v", id)
+	}
+	connect, ok := spans["connect"]
+	if !ok || connect.Parent.SpanID != handler.SpanContext.SpanID ||
+		connect.SpanContext.TraceID != handler.SpanContext.TraceID {
+		t.Errorf("Unexpected connect span %+v of request span %+v",
+			connect, handler)
+	}
+}
+
+func TestHandler_ContinuesTrace(t *testing.T) {
+	request := httptest.NewRequest(http.MethodGet, "/api/ping", nil)
+	request.Header.Set("traceparent",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	// Traces continued from a caller follow its sampling decision
+	spans := serve(t, map[string]string{"enabled": "true",
+		"sample_rate": "0"}, request)
+	handler, ok := spans["GET /api/ping"]
+	if !ok || handler.SpanContext.TraceID !=
+		"4bf92f3577b34da6a3ce929d0e0e4736" ||
+		handler.Parent.SpanID != "00f067aa0ba902b7" {
+		t.Errorf("Failed to continue trace of caller, got %v", spans)
+	}
+}
+
+func TestHandler_Disabled(t *testing.T) {
+	spans := serve(t, map[string]string{"enabled": "false"},
+		httptest.NewRequest(http.MethodGet, "/api/ping", nil))
+	if len(spans) != 0 {
+		t.Errorf("Unexpected spans while disabled %v", spans)
+	}
+}